@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/MacAttak/pi-scanner/pkg/baseline"
+)
+
+// runBaselineCreate writes a baseline accepting every finding in a saved scan result
+func runBaselineCreate(out io.Writer, inputFile, outputFile string) error {
+	result, err := loadScanResult(inputFile)
+	if err != nil {
+		return err
+	}
+
+	var root, repo string
+	if result.Repository != nil {
+		root = result.Repository.LocalPath
+		repo = result.Repository.URL
+		if repo == "" {
+			repo = result.Repository.Name
+		}
+	}
+
+	b := baseline.New(result.DetectionFindings(), root, repo)
+	if err := b.Save(outputFile); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "✅ Baseline with %d findings saved to: %s\n", len(b.Entries), outputFile)
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/policy"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// hookMarker identifies pre-commit hooks written by pi-scanner
+const hookMarker = "# Installed by pi-scanner hook install"
+
+// runHookInstall writes a pre-commit hook that scans staged changes. An
+// existing hook is only replaced if it was installed by pi-scanner or force
+// is set.
+func runHookInstall(ctx context.Context, out io.Writer, repoPath, failOn string, force bool) error {
+	levels, err := policy.ParseRiskLevels(failOn)
+	if err != nil {
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	if len(levels) == 0 {
+		return fmt.Errorf("--fail-on must list at least one risk level")
+	}
+
+	hooksDir, err := repository.HooksDir(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+	if existing, err := os.ReadFile(hookPath); err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+		return fmt.Errorf("a pre-commit hook already exists at %s (use --force to replace it)", hookPath)
+	}
+
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = strings.ToLower(string(level))
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+%s
+# Blocks commits that add personally identifiable information.
+# Bypass once with: git commit --no-verify
+exec pi-scanner scan --staged --fail-on %s
+`, hookMarker, strings.Join(names, ","))
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write pre-commit hook: %w", err)
+	}
+	// WriteFile keeps the mode of a replaced hook
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return fmt.Errorf("failed to make pre-commit hook executable: %w", err)
+	}
+
+	fmt.Fprintf(out, "✅ Pre-commit hook installed: %s\n", hookPath)
+	return nil
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"runtime"
 	"strings"
 
+	"github.com/MacAttak/pi-scanner/pkg/allowlist"
+	"github.com/MacAttak/pi-scanner/pkg/baseline"
+	"github.com/MacAttak/pi-scanner/pkg/cache"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +24,7 @@ var (
 
 func main() {
 	if err := newRootCmd().Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -42,6 +47,10 @@ high accuracy with minimal false positives.`,
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newScanCmd())
 	rootCmd.AddCommand(newReportCmd())
+	rootCmd.AddCommand(newBaselineCmd())
+	rootCmd.AddCommand(newAllowlistCmd())
+	rootCmd.AddCommand(newRulesCmd())
+	rootCmd.AddCommand(newHookCmd())
 
 	return rootCmd
 }
@@ -63,22 +72,63 @@ func newVersionCmd() *cobra.Command {
 
 func newScanCmd() *cobra.Command {
 	var (
-		repoURL    string
-		repoList   string
-		configFile string
-		outputFile string
-		verbose    bool
+		repoURL      string
+		repoList     string
+		org          string
+		orgFlags     orgFlags
+		localPath    string
+		configFile   string
+		outputFile   string
+		outputDir    string
+		concurrency  int
+		history      bool
+		staged       bool
+		diffRange    string
+		format       string
+		since        string
+		commitRange  string
+		baselineFile string
+		allowFile    string
+		failOn       string
+		maxCounts    string
+		validated    bool
+		incremental  bool
+		cacheDir     string
+		memoryBudget int
+		verbose      bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "scan",
 		Short: "Scan repositories for personally identifiable information",
 		Long: `Scan one or more repositories for personally identifiable information
-using a multi-stage detection pipeline.`,
+using a multi-stage detection pipeline.
+
+Exit codes:
+  0  scan completed and no policy was violated
+  1  findings violate the --fail-on or --max-count policy
+  2  invalid usage, or a scan could not complete`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Validate inputs
-			if repoURL == "" && repoList == "" {
-				return fmt.Errorf("either --repo or --repo-list must be specified")
+			if staged {
+				if repoURL != "" || repoList != "" || org != "" {
+					return fmt.Errorf("--staged scans a local checkout; use --path instead of --repo, --repo-list or --org")
+				}
+				if history || diffRange != "" {
+					return fmt.Errorf("--staged cannot be combined with --history or --diff")
+				}
+				if localPath == "" {
+					localPath = "."
+				}
+			}
+
+			if repoURL == "" && repoList == "" && localPath == "" && org == "" {
+				return fmt.Errorf("either --repo, --repo-list, --org or --path must be specified")
+			}
+
+			orgFilter, err := orgFlags.filter(org)
+			if err != nil {
+				return err
 			}
 
 			// Validate repository URL format
@@ -88,24 +138,164 @@ using a multi-stage detection pipeline.`,
 				}
 			}
 
+			if !history && (since != "" || commitRange != "") {
+				return fmt.Errorf("--since and --commit-range require --history")
+			}
+
+			if diffRange != "" {
+				if history {
+					return fmt.Errorf("--diff and --history cannot be combined")
+				}
+				if _, err := repository.ParseDiffRange(diffRange); err != nil {
+					return err
+				}
+			}
+
+			format = strings.ToLower(format)
+			switch format {
+			case "json":
+			case "sarif", "github":
+				if repoList != "" || org != "" {
+					return fmt.Errorf("--format %s is not supported with --repo-list or --org", format)
+				}
+				if !cmd.Flags().Changed("output") {
+					outputFile = defaultOutputFile(format)
+				}
+			default:
+				return fmt.Errorf("unsupported output format: %s (expected json, sarif or github)", format)
+			}
+
+			if cacheDir != "" && !incremental {
+				return fmt.Errorf("--cache-dir requires --incremental")
+			}
+
+			if memoryBudget < 0 {
+				return fmt.Errorf("--memory-budget cannot be negative")
+			}
+
+			// With results on stdout, progress goes to stderr so the output
+			// stays valid JSON or SARIF
+			out := cmd.OutOrStdout()
+			if outputFile == "-" {
+				out = cmd.ErrOrStderr()
+			}
+
+			// Load configuration
+			scanConfig, err := loadScanConfig(out, configFile, verbose)
+			if err != nil {
+				return err
+			}
+			scanConfig.Output = out
+
+			scanPolicy, err := buildPolicy(failOn, maxCounts, validated, scanConfig.Detection.Rules)
+			if err != nil {
+				return err
+			}
+
+			// The flags are valid, so policy violations and scan failures do
+			// not print the usage
+			cmd.SilenceUsage = true
+
+			if baselineFile != "" {
+				b, err := baseline.Load(baselineFile)
+				if err != nil {
+					return err
+				}
+				scanConfig.Baseline = b
+				fmt.Fprintf(out, "Comparing against baseline: %s (%d findings)\n", baselineFile, len(b.Entries))
+			}
+
+			scanConfig.Policy = scanPolicy
+
+			if memoryBudget > 0 {
+				scanConfig.MemoryBudget = int64(memoryBudget) * 1024 * 1024
+			}
+
+			if allowFile != "" {
+				list, err := allowlist.Load(allowFile)
+				if err != nil {
+					return err
+				}
+				scanConfig.Allowlist = list
+				fmt.Fprintf(out, "Using allowlist: %s\n", allowFile)
+			}
+
+			if incremental {
+				if cacheDir == "" {
+					cacheDir = cache.DefaultDir()
+				}
+				scanConfig.CacheDir = cacheDir
+				fmt.Fprintf(out, "Using incremental cache: %s\n", cacheDir)
+			}
+
+			if history {
+				scanConfig.History = &repository.HistoryOptions{Range: commitRange, Since: since}
+				fmt.Fprintf(out, "Scanning commit history\n")
+			}
+
+			if diffRange != "" {
+				scanConfig.Diff = diffRange
+				fmt.Fprintf(out, "Scanning changes in: %s\n", diffRange)
+			}
+
+			// Enumerate and scan an organisation
+			if org != "" {
+				fmt.Fprintf(out, "Listing repositories in organisation: %s\n", org)
+				return runOrgScan(cmd.Context(), org, orgFilter, orgFlags.apiURL, outputFile, outputDir, concurrency, scanConfig)
+			}
+
 			// Handle repo list
 			if repoList != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "Reading repository list from: %s\n", repoList)
-				// TODO: Implement repo list reading
-				return fmt.Errorf("repo list scanning not yet implemented")
+				fmt.Fprintf(out, "Reading repository list from: %s\n", repoList)
+				return runRepoListScan(cmd.Context(), repoList, outputFile, outputDir, concurrency, scanConfig)
+			}
+
+			// Pre-commit scan of the git index; JSON results are only saved when --output is given
+			if staged {
+				scanConfig.Staged = true
+				if !cmd.Flags().Changed("output") && format == "json" {
+					outputFile = ""
+				}
+				return runStagedScan(cmd.Context(), out, localPath, outputFile, format, scanConfig)
+			}
+
+			// Local directory scan
+			if localPath != "" {
+				fmt.Fprintf(out, "Scanning local path: %s\n", localPath)
+				return runPathScan(cmd.Context(), localPath, outputFile, format, scanConfig)
 			}
 
 			// Single repo scan
-			return runScan(cmd.Context(), repoURL, outputFile, verbose)
+			return runScan(cmd.Context(), repoURL, outputFile, format, scanConfig)
 		},
 	}
 
 	// Add flags
 	cmd.Flags().StringVarP(&repoURL, "repo", "r", "", "Repository URL to scan")
 	cmd.Flags().StringVarP(&repoList, "repo-list", "l", "", "File containing list of repository URLs")
+	cmd.Flags().StringVar(&org, "org", "", "Scan every repository in a GitHub organisation")
+	orgFlags.register(cmd)
+	cmd.Flags().StringVarP(&localPath, "path", "p", "", "Local directory or existing checkout to scan without cloning")
 	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file (default: built-in)")
-	cmd.Flags().StringVarP(&outputFile, "output", "o", "scan-results.json", "Output file for results")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "scan-results.json", "Output file for results, or - for stdout with progress on stderr (the combined index with --repo-list or --org)")
+	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format (json, sarif, github); github writes workflow annotations to stdout by default")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "scan-results", "Directory for per-repository results with --repo-list or --org")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Number of repositories to scan in parallel with --repo-list or --org")
+	cmd.Flags().BoolVar(&history, "history", false, "Scan lines added by every commit instead of the working tree (disables shallow cloning)")
+	cmd.Flags().StringVar(&since, "since", "", "Only scan commits newer than this date with --history (e.g. 2024-01-01, \"6 months ago\")")
+	cmd.Flags().StringVar(&commitRange, "commit-range", "", "Only scan commits in this revision range with --history (e.g. v1.0..HEAD)")
+	cmd.Flags().StringVar(&diffRange, "diff", "", "Only report PI on lines added between two revisions (e.g. origin/main...HEAD); missing refs are fetched")
+	cmd.Flags().BoolVar(&staged, "staged", false, "Scan only lines added in the git index, e.g. from a pre-commit hook (default path: current directory)")
+	cmd.Flags().StringVar(&allowFile, "allowlist", "", "Allowlist file (default: .pi-scanner-allow.yaml in the scanned repository, if present)")
+	cmd.Flags().StringVar(&baselineFile, "baseline", "", "Baseline file; findings are marked new, unchanged or absent")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", "Exit with code 1 if findings at these risk levels remain (e.g. critical,high)")
+	cmd.Flags().StringVar(&maxCounts, "max-count", "", "Exit with code 1 if a PI type exceeds its maximum count (e.g. TFN=0,EMAIL=10)")
+	cmd.Flags().BoolVar(&validated, "validated-only", false, "Only count checksum-validated findings towards --fail-on and --max-count")
+	cmd.Flags().BoolVar(&incremental, "incremental", false, "Reuse findings for files unchanged since earlier scans and remember the last scanned commit")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory for the --incremental cache (default: pi-scanner in the user cache directory)")
+	cmd.Flags().IntVar(&memoryBudget, "memory-budget", 0, "Maximum MB of file content each repository scan holds in memory; file reading waits for workers beyond it (default: memory_budget from the config, 256MB)")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	cmd.MarkFlagsMutuallyExclusive("repo", "repo-list", "org", "path")
 
 	return cmd
 }
@@ -128,13 +318,11 @@ func validateRepositoryURL(repoURL string) error {
 		return fmt.Errorf("URL must include a host")
 	}
 
-	// Check if it looks like a GitHub URL
-	if strings.Contains(u.Host, "github.com") {
-		// Basic GitHub URL validation
-		pathParts := strings.Split(strings.Trim(u.Path, "/"), "/")
-		if len(pathParts) < 2 {
-			return fmt.Errorf("GitHub URL must be in format: https://github.com/owner/repo")
-		}
+	// Check the path against the hosting service's URL format. Hosts that are
+	// not recognised may still be mapped to a provider in the configuration.
+	providers := repository.NewProviderRegistry(repository.DefaultGitHubConfig())
+	if _, _, err := providers.Resolve(repoURL); err != nil && !errors.Is(err, repository.ErrUnsupportedHost) {
+		return err
 	}
 
 	return nil
@@ -150,7 +338,7 @@ func newReportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "report",
 		Short: "Generate reports from scan results",
-		Long:  `Generate HTML, CSV, or SARIF reports from previously saved scan results.`,
+		Long:  `Generate HTML, CSV, SARIF or GitHub annotation reports from previously saved scan results.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if inputFile == "" {
 				return fmt.Errorf("input file must be specified")
@@ -158,19 +346,25 @@ func newReportCmd() *cobra.Command {
 
 			fmt.Fprintf(cmd.OutOrStdout(), "Generating report from: %s\n", inputFile)
 
-			if format == "html" {
+			switch format {
+			case "html":
 				fmt.Fprintf(cmd.OutOrStdout(), "Generating HTML report\n")
+			case "csv":
+				fmt.Fprintf(cmd.OutOrStdout(), "Generating CSV report\n")
+			case "sarif":
+				fmt.Fprintf(cmd.OutOrStdout(), "Generating SARIF report\n")
+			case "github":
+				fmt.Fprintf(cmd.OutOrStdout(), "Generating GitHub annotations\n")
 			}
 
-			// TODO: Implement report generation
-			return fmt.Errorf("report generation not yet implemented")
+			return runReport(cmd.OutOrStdout(), inputFile, format, outputFile)
 		},
 	}
 
 	// Add flags
 	cmd.Flags().StringVarP(&inputFile, "input", "i", "", "Input scan results file")
-	cmd.Flags().StringVarP(&format, "format", "f", "html", "Report format (html, csv, sarif)")
-	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (default: report.<format>)")
+	cmd.Flags().StringVarP(&format, "format", "f", "html", "Report format (html, csv, sarif, github)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file, or - for stdout (default: report.<format>; stdout for github)")
 
 	cmd.MarkFlagRequired("input")
 
@@ -183,3 +377,137 @@ func isValidRepoURL(url string) bool {
 	// More comprehensive validation will be added later
 	return len(url) > 8 && url[:8] == "https://"
 }
+
+func newBaselineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage baselines of accepted findings",
+		Long: `Manage baseline files. A baseline records existing findings so that
+scans run with --baseline can separate new findings from accepted ones.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newBaselineCreateCmd())
+
+	return cmd
+}
+
+func newBaselineCreateCmd() *cobra.Command {
+	var (
+		inputFile  string
+		outputFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a baseline from scan results",
+		Long:  `Create a baseline file that accepts every finding in a saved scan result.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprintf(cmd.OutOrStdout(), "Creating baseline from: %s\n", inputFile)
+			return runBaselineCreate(cmd.OutOrStdout(), inputFile, outputFile)
+		},
+	}
+
+	// Add flags
+	cmd.Flags().StringVarP(&inputFile, "input", "i", "", "Input scan results file")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", ".pi-scanner-baseline.json", "Output baseline file")
+
+	cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+func newAllowlistCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allowlist",
+		Short: "Manage the allowlist of accepted findings",
+		Long: `Manage .pi-scanner-allow.yaml entries. Entries identify values by hash
+so the allowlist never contains the PI it accepts.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "hash <value>",
+		Short: "Print the value_hash for a PI value",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintln(cmd.OutOrStdout(), allowlist.HashValue(args[0]))
+		},
+	})
+
+	return cmd
+}
+
+func newRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Inspect detection rules",
+		Long: `Inspect the built-in detection patterns and the user-defined rules loaded
+from the files listed under scanner.rules in the configuration.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	var configFile string
+	list := &cobra.Command{
+		Use:   "list [rules files...]",
+		Short: "List the built-in patterns and user-defined rules",
+		Long: `List the patterns the scanner detects, in the order they claim overlapping
+values: user-defined rules first, then the enabled built-in patterns. Rules
+files given as arguments are loaded along with those in the configuration,
+so new rules can be checked before they are added to it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesList(cmd.OutOrStdout(), configFile, args)
+		},
+	}
+	list.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file (default: built-in)")
+	cmd.AddCommand(list)
+
+	return cmd
+}
+
+func newHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Manage git hooks",
+		Long:  `Manage git hooks that scan changes before they are committed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newHookInstallCmd())
+
+	return cmd
+}
+
+func newHookInstallCmd() *cobra.Command {
+	var (
+		repoPath string
+		failOn   string
+		force    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install a pre-commit hook that scans staged changes",
+		Long: `Install a git pre-commit hook that runs "pi-scanner scan --staged" and
+blocks commits adding PI at the --fail-on risk levels. pi-scanner must be on
+the PATH when committing.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHookInstall(cmd.Context(), cmd.OutOrStdout(), repoPath, failOn, force)
+		},
+	}
+
+	// Add flags
+	cmd.Flags().StringVarP(&repoPath, "path", "p", ".", "Repository to install the hook in")
+	cmd.Flags().StringVar(&failOn, "fail-on", "critical,high", "Risk levels that block a commit")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing pre-commit hook")
+
+	return cmd
+}
@@ -2,10 +2,18 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/MacAttak/pi-scanner/pkg/allowlist"
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -109,7 +117,7 @@ func TestScanCommand(t *testing.T) {
 			name: "scan without repo shows error",
 			args: []string{"scan"},
 			expectedOutput: []string{
-				"Error: either --repo or --repo-list must be specified",
+				"Error: either --repo, --repo-list, --org or --path must be specified",
 			},
 			expectedError: true,
 		},
@@ -121,6 +129,39 @@ func TestScanCommand(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "scan with missing local path",
+			args: []string{"scan", "--path", "does-not-exist"},
+			expectedOutput: []string{
+				"Scanning local path:",
+				"does-not-exist",
+			},
+			expectedError: true,
+		},
+		{
+			name: "scan with repo and path shows error",
+			args: []string{"scan", "--repo", "https://github.com/test/repo", "--path", "."},
+			expectedOutput: []string{
+				"if any flags in the group [repo repo-list org path] are set none of the others can be",
+			},
+			expectedError: true,
+		},
+		{
+			name: "scan with since but no history shows error",
+			args: []string{"scan", "--path", ".", "--since", "2024-01-01"},
+			expectedOutput: []string{
+				"Error: --since and --commit-range require --history",
+			},
+			expectedError: true,
+		},
+		{
+			name: "scan with cache dir but no incremental shows error",
+			args: []string{"scan", "--path", ".", "--cache-dir", "cache"},
+			expectedOutput: []string{
+				"Error: --cache-dir requires --incremental",
+			},
+			expectedError: true,
+		},
 		{
 			name: "scan with valid repo URL",
 			args: []string{"scan", "--repo", "https://github.com/test/repo"},
@@ -181,19 +222,29 @@ func TestScanCommand(t *testing.T) {
 
 func TestReportCommand(t *testing.T) {
 	// Create a temporary test results file
-	testResults := `{"findings": [], "summary": {"total": 0}}`
-	tmpFile, err := os.CreateTemp("", "scan-results-*.json")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
-
-	_, err = tmpFile.WriteString(testResults)
-	require.NoError(t, err)
-	tmpFile.Close()
+	testResults := `{
+  "repository": {"url": "https://github.com/test/repo", "name": "repo", "local_path": "/tmp/pi-scanner-1/repo"},
+  "files_scanned": 2,
+  "findings": [
+    {"type": "TFN", "match": "123456782", "file": "/tmp/pi-scanner-1/repo/src/payroll.go", "line": 12, "column": 8,
+     "context": "tfn := \"123456782\"", "risk_level": "HIGH", "confidence": 0.95, "context_modifier": 1.0, "validated": true,
+     "confidence_result": {"final_score": 0.87, "risk_level": "HIGH"},
+     "risk_assessment": {"overall_risk": 0.82, "risk_level": "CRITICAL", "risk_category": "IDENTITY_THEFT",
+       "compliance_flags": {"notifiable_data_breach": true}}},
+    {"type": "EMAIL", "match": "jane.citizen@example.com", "file": "/tmp/pi-scanner-1/repo/config.yaml", "line": 3, "column": 10,
+     "risk_level": "LOW", "confidence": 0.8, "context_modifier": 1.0}
+  ]
+}`
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "scan-results.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(testResults), 0644))
 
 	tests := []struct {
 		name           string
 		args           []string
+		outputFile     string
 		expectedOutput []string
+		expectedReport []string
 		expectedError  bool
 	}{
 		{
@@ -205,20 +256,53 @@ func TestReportCommand(t *testing.T) {
 			expectedError: true,
 		},
 		{
-			name: "report with valid input",
-			args: []string{"report", "--input", tmpFile.Name()},
+			name:       "report with valid input",
+			args:       []string{"report", "--input", inputFile},
+			outputFile: "default.html",
 			expectedOutput: []string{
 				"Generating report from:",
+				"Report saved to:",
 			},
-			expectedError: false,
+			expectedReport: []string{"PI Scanner Report", "123****82"},
 		},
 		{
-			name: "report with format flag",
-			args: []string{"report", "--input", tmpFile.Name(), "--format", "html"},
+			name:       "report with format flag",
+			args:       []string{"report", "--input", inputFile, "--format", "html"},
+			outputFile: "report.html",
 			expectedOutput: []string{
 				"Generating HTML report",
 			},
-			expectedError: false,
+			expectedReport: []string{"Tax File Number", "repo"},
+		},
+		{
+			name:       "report in CSV format",
+			args:       []string{"report", "--input", inputFile, "--format", "csv"},
+			outputFile: "report.csv",
+			expectedOutput: []string{
+				"Generating CSV report",
+			},
+			expectedReport: []string{"File Path", "src/payroll.go", "Tax File Number", "123****82", "IDENTITY_THEFT", "0.87"},
+		},
+		{
+			name:       "report in SARIF format",
+			args:       []string{"report", "--input", inputFile, "--format", "sarif"},
+			outputFile: "report.sarif",
+			expectedOutput: []string{
+				"Generating SARIF report",
+			},
+			expectedReport: []string{"\"version\": \"2.1.0\"", "\"uri\": \"src/payroll.go\"", "PI001"},
+		},
+		{
+			name:          "report with unsupported format",
+			args:          []string{"report", "--input", inputFile, "--format", "pdf"},
+			outputFile:    "report.pdf",
+			expectedError: true,
+		},
+		{
+			name:          "report with missing input file",
+			args:          []string{"report", "--input", filepath.Join(tmpDir, "missing.json")},
+			outputFile:    "missing.html",
+			expectedError: true,
 		},
 	}
 
@@ -226,26 +310,422 @@ func TestReportCommand(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var stdout, stderr bytes.Buffer
 
+			args := tt.args
+			outputPath := ""
+			if tt.outputFile != "" {
+				outputPath = filepath.Join(tmpDir, tt.outputFile)
+				args = append(args, "--output", outputPath)
+			}
+
 			cmd := newRootCmd()
 			cmd.SetOut(&stdout)
 			cmd.SetErr(&stderr)
-			cmd.SetArgs(tt.args)
+			cmd.SetArgs(args)
 
 			err := cmd.Execute()
 
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
-				// Skip actual report generation for now
-				t.Skip("Report implementation pending")
+				require.NoError(t, err)
 			}
 
 			output := stdout.String() + stderr.String()
 			for _, expected := range tt.expectedOutput {
-				if tt.expectedError {
-					assert.Contains(t, output, expected)
+				assert.Contains(t, output, expected)
+			}
+
+			if len(tt.expectedReport) > 0 {
+				content, err := os.ReadFile(outputPath)
+				require.NoError(t, err)
+				for _, expected := range tt.expectedReport {
+					assert.Contains(t, string(content), expected)
 				}
 			}
 		})
 	}
 }
+
+func TestBuildHTMLTemplateData_LastCommit(t *testing.T) {
+	lastCommit := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	result := &ScanResult{
+		ScanStarted: time.Now(),
+		Repository:  &repository.RepositoryInfo{Name: "payments", LastCommit: lastCommit},
+	}
+
+	data := buildHTMLTemplateData(result, nil)
+	assert.Equal(t, lastCommit, data.Repository.LastCommitDate)
+
+	// Unknown without repository metadata, rather than the scan time
+	data = buildHTMLTemplateData(&ScanResult{ScanStarted: time.Now()}, nil)
+	assert.True(t, data.Repository.LastCommitDate.IsZero())
+}
+
+func TestBaselineWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "legacy.go"), []byte("package legacy\n\nvar tfn = \"123456782\"\n"), 0644))
+
+	run := func(args ...string) string {
+		t.Helper()
+		var stdout bytes.Buffer
+		cmd := newRootCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stdout)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute(), stdout.String())
+		return stdout.String()
+	}
+
+	firstScan := filepath.Join(tmpDir, "first.json")
+	baselineFile := filepath.Join(tmpDir, "baseline.json")
+	run("scan", "--path", repoDir, "--output", firstScan)
+	output := run("baseline", "create", "--input", firstScan, "--output", baselineFile)
+	assert.Contains(t, output, "Baseline with")
+
+	content, err := os.ReadFile(baselineFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"file": "legacy.go"`)
+	assert.NotContains(t, string(content), "123456782", "baseline must not store raw values")
+
+	// Add a new TFN and remove nothing
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "payroll.go"), []byte("package payroll\n\nvar tfn = \"876543210\"\nvar other = \"123456782\"\n"), 0644))
+
+	secondScan := filepath.Join(tmpDir, "second.json")
+	output = run("scan", "--path", repoDir, "--baseline", baselineFile, "--output", secondScan)
+	assert.Contains(t, output, "Comparing against baseline:")
+
+	result, err := loadScanResult(secondScan)
+	require.NoError(t, err)
+	require.NotNil(t, result.Baseline)
+	assert.Zero(t, result.Baseline.Absent)
+	assert.Positive(t, result.Baseline.New)
+	assert.Positive(t, result.Baseline.Unchanged)
+	for _, f := range result.Findings {
+		if filepath.Base(f.File) == "legacy.go" {
+			assert.Equal(t, detection.BaselineStateUnchanged, f.BaselineState)
+		} else {
+			assert.Equal(t, detection.BaselineStateNew, f.BaselineState)
+		}
+	}
+
+	sarifFile := filepath.Join(tmpDir, "report.sarif")
+	run("report", "--input", secondScan, "--format", "sarif", "--output", sarifFile)
+	sarif, err := os.ReadFile(sarifFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(sarif), `"baselineState": "new"`)
+	assert.Contains(t, string(sarif), `"baselineState": "unchanged"`)
+}
+
+func TestBaselineCreate_MissingInput(t *testing.T) {
+	var stdout bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"baseline", "create"})
+
+	assert.Error(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), `required flag(s) "input" not set`)
+}
+
+func TestAllowlistHashCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"allowlist", "hash", "123 456 782"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, allowlist.HashValue("123456782")+"\n", stdout.String())
+}
+
+func TestRulesListCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	rules := `rules:
+  - id: member-number
+    type: MEMBER_NUMBER
+    pattern: '\b\d{9}\b'
+    keywords: [member]
+    validator: {algorithm: mod11, weights: [1, 4, 3, 7, 5, 8, 6, 9, 10]}
+    risk_level: high
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "members.yaml"), []byte(rules), 0644))
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("scanner:\n  rules: [members.yaml]\n"), 0644))
+
+	var stdout bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"rules", "list", "--config", configPath})
+
+	require.NoError(t, cmd.Execute())
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Greater(t, len(lines), 2)
+	assert.Regexp(t, `^ID\s+TYPE\s+VALIDATOR\s+KEYWORDS\s+RISK\s+SOURCE$`, lines[0])
+	assert.Regexp(t, `^member-number\s+MEMBER_NUMBER\s+mod11\s+member\s+HIGH\s+.*members.yaml$`, lines[1])
+	assert.Regexp(t, `^ihi\s+IHI\s+ihi\s+-\s+\w+\s+built-in$`, lines[2])
+
+	// Invalid rules fail the command
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "bad.yaml"), []byte("rules:\n  - id: bad\n    type: BAD\n"), 0644))
+	cmd = newRootCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"rules", "list", filepath.Join(tmpDir, "bad.yaml")})
+	assert.ErrorContains(t, cmd.Execute(), "bad: pattern is required")
+}
+
+func TestScanPolicyExitCodes(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "payroll.go"), []byte("package payroll\n\nvar employeeTFN = \"123456782\"\n"), 0644))
+
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+		usage    bool
+	}{
+		{"no policy", nil, exitClean, false},
+		{"validated TFN fails critical and high", []string{"--fail-on", "critical,high"}, exitPolicyViolation, false},
+		{"fail on low ignores the higher detector risk", []string{"--fail-on", "low"}, exitClean, false},
+		{"max count allows findings", []string{"--max-count", "TFN=5"}, exitClean, false},
+		{"max count exceeded", []string{"--max-count", "TFN=0"}, exitPolicyViolation, false},
+		{"invalid risk level", []string{"--fail-on", "severe"}, exitScanError, true},
+		{"unknown max count type", []string{"--max-count", "TNF=0"}, exitScanError, true},
+		{"validated only without rules", []string{"--validated-only"}, exitScanError, true},
+		{"missing path", []string{"--path", filepath.Join(tmpDir, "missing")}, exitScanError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			args := []string{"scan", "--path", repoDir, "--output", filepath.Join(tmpDir, "results.json")}
+			if len(tt.args) > 0 && tt.args[0] == "--path" {
+				args = []string{"scan", "--output", filepath.Join(tmpDir, "results.json")}
+			}
+
+			cmd := newRootCmd()
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(append(args, tt.args...))
+
+			err := cmd.Execute()
+			assert.Equal(t, tt.exitCode, exitCode(err), stdout.String())
+			if tt.exitCode == exitPolicyViolation {
+				assert.Contains(t, stdout.String(), "policy violated")
+			}
+			// Only invalid flags print the usage
+			assert.Equal(t, tt.usage, strings.Contains(stdout.String(), "Usage:"), stdout.String())
+		})
+	}
+}
+
+func TestScanMaxCountRuleType(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "staff.go"), []byte("package staff\n\nvar employeeID = \"EMP-204816\"\n"), 0644))
+
+	// A rule type written in lower case is matched by --max-count in any case
+	rules := `rules:
+  - id: employee-id
+    type: employee_id
+    pattern: '\bEMP-\d{6}\b'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "staff.yaml"), []byte(rules), 0644))
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("scanner:\n  rules: [staff.yaml]\n"), 0644))
+
+	var stdout bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"scan", "--path", repoDir, "--config", configPath, "--output", filepath.Join(tmpDir, "results.json"),
+		"--max-count", "employee_id=0"})
+
+	err := cmd.Execute()
+	assert.Equal(t, exitPolicyViolation, exitCode(err), stdout.String())
+	assert.Contains(t, stdout.String(), "1 EMPLOYEE_ID findings exceed the maximum of 0")
+}
+
+func TestStagedScanAndHookInstall(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	run := func(args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := newRootCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stdout)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	git("init", "-q")
+
+	// Nothing staged
+	output, err := run("scan", "--staged", "--path", repoDir, "--fail-on", "critical,high")
+	require.NoError(t, err, output)
+	assert.Contains(t, output, "No PI found in staged changes")
+	assert.NoFileExists(t, filepath.Join(repoDir, "scan-results.json"), "staged scans only save results with --output")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "payroll.go"), []byte("package payroll\n\nvar employeeTFN = \"123456782\"\nvar medicareNumber = \"2123456701\"\n"), 0644))
+	git("add", "payroll.go")
+
+	// The hook's default policy fails on validated identifiers
+	output, err = run("scan", "--staged", "--path", repoDir, "--fail-on", "critical,high")
+	assert.Equal(t, exitPolicyViolation, exitCode(err), output)
+	assert.Contains(t, output, "payroll.go:3 TFN 123****82 (CRITICAL)")
+	assert.Contains(t, output, "payroll.go:4 MEDICARE")
+
+	_, err = run("scan", "--staged", "--repo", "https://github.com/org/repo")
+	assert.ErrorContains(t, err, "use --path")
+
+	// Hook installation
+	output, err = run("hook", "install", "--path", repoDir)
+	require.NoError(t, err, output)
+
+	hookPath := filepath.Join(repoDir, ".git", "hooks", "pre-commit")
+	script, err := os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "pi-scanner scan --staged --fail-on critical,high")
+
+	info, err := os.Stat(hookPath)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100, "hook must be executable")
+
+	// Reinstalling our own hook is allowed, replacing another hook needs --force
+	_, err = run("hook", "install", "--path", repoDir, "--fail-on", "critical")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\nmake lint\n"), 0755))
+	_, err = run("hook", "install", "--path", repoDir)
+	assert.ErrorContains(t, err, "--force")
+
+	_, err = run("hook", "install", "--path", repoDir, "--force")
+	require.NoError(t, err)
+}
+
+func TestDiffScanFormats(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	run := func(args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := newRootCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stdout)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	git("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "payroll.go"), []byte("package payroll\n"), 0644))
+	git("add", ".")
+	git("commit", "-q", "-m", "Add payroll")
+	git("checkout", "-q", "-b", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "payroll.go"), []byte("package payroll\n\nvar employeeTFN = \"123456782\"\n"), 0644))
+	git("commit", "-q", "-am", "Add employee")
+
+	sarifFile := filepath.Join(t.TempDir(), "results.sarif")
+	output, err := run("scan", "--path", repoDir, "--diff", "main...feature", "--format", "sarif", "--output", sarifFile)
+	require.NoError(t, err, output)
+	assert.Contains(t, output, "Scanning changes in: main...feature")
+
+	data, err := os.ReadFile(sarifFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"piValueHash/v1"`)
+	assert.Contains(t, string(data), `"uri": "payroll.go"`)
+
+	annotationsFile := filepath.Join(t.TempDir(), "annotations.txt")
+	output, err = run("scan", "--path", repoDir, "--diff", "main...feature", "--format", "github", "--output", annotationsFile)
+	require.NoError(t, err, output)
+
+	data, err = os.ReadFile(annotationsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), " file=payroll.go,line=3,")
+	assert.Contains(t, string(data), "123****82")
+
+	_, err = run("scan", "--path", repoDir, "--diff", "main")
+	assert.ErrorContains(t, err, "invalid diff range")
+
+	_, err = run("scan", "--path", repoDir, "--diff", "main..feature", "--history")
+	assert.ErrorContains(t, err, "cannot be combined")
+
+	_, err = run("scan", "--path", repoDir, "--format", "xml")
+	assert.ErrorContains(t, err, "unsupported output format")
+}
+
+// captureStdout returns what fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+
+	fn()
+	require.NoError(t, w.Close())
+	return string(<-done)
+}
+
+func TestScanOutputToStdout(t *testing.T) {
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "payroll.go"), []byte("package payroll\n\nvar employeeTFN = \"123456782\"\n"), 0644))
+
+	for _, format := range []string{"json", "sarif"} {
+		t.Run(format, func(t *testing.T) {
+			var progress bytes.Buffer
+			cmd := newRootCmd()
+			cmd.SetOut(&progress)
+			cmd.SetErr(&progress)
+			cmd.SetArgs([]string{"scan", "--path", repoDir, "--format", format, "--output", "-", "--verbose"})
+
+			var err error
+			stdout := captureStdout(t, func() { err = cmd.Execute() })
+			require.NoError(t, err, progress.String())
+
+			// Only the results are written to stdout
+			var result map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(stdout), &result), stdout)
+			assert.Contains(t, progress.String(), "Scanning local path: "+repoDir)
+			assert.Contains(t, progress.String(), "Discovering files to scan")
+		})
+	}
+}
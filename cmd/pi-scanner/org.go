@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/MacAttak/pi-scanner/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+// orgFlags holds the repository filters for --org
+type orgFlags struct {
+	topics          []string
+	visibility      string
+	includeArchived bool
+	pushedSince     string
+	apiURL          string
+}
+
+// register adds the organisation filter flags to the scan command
+func (f *orgFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&f.topics, "topic", nil, "Only scan --org repositories with all of these topics")
+	cmd.Flags().StringVar(&f.visibility, "visibility", "", "Only scan --org repositories with this visibility (public, private, internal)")
+	cmd.Flags().BoolVar(&f.includeArchived, "include-archived", false, "Include archived repositories with --org")
+	cmd.Flags().StringVar(&f.pushedSince, "pushed-since", "", "Only scan --org repositories pushed to since a date or age (e.g. 2024-01-01, 30d)")
+	cmd.Flags().StringVar(&f.apiURL, "github-api-url", repository.DefaultGitHubAPIURL, "GitHub REST API URL for --org (https://HOST/api/v3 for GitHub Enterprise)")
+}
+
+// filter validates the flags and builds the organisation filter. The filter
+// flags are only allowed with --org.
+func (f *orgFlags) filter(org string) (repository.OrgFilter, error) {
+	if org == "" {
+		if len(f.topics) > 0 || f.visibility != "" || f.includeArchived || f.pushedSince != "" {
+			return repository.OrgFilter{}, fmt.Errorf("--topic, --visibility, --include-archived and --pushed-since require --org")
+		}
+		return repository.OrgFilter{}, nil
+	}
+
+	filter := repository.OrgFilter{
+		Topics:          f.topics,
+		Visibility:      strings.ToLower(f.visibility),
+		IncludeArchived: f.includeArchived,
+	}
+
+	switch filter.Visibility {
+	case "", repository.VisibilityPublic, repository.VisibilityPrivate, repository.VisibilityInternal:
+	default:
+		return filter, fmt.Errorf("invalid --visibility: %s (expected public, private or internal)", f.visibility)
+	}
+
+	if f.pushedSince != "" {
+		since, err := parsePushedSince(f.pushedSince, time.Now())
+		if err != nil {
+			return filter, err
+		}
+		filter.PushedSince = since
+	}
+
+	return filter, nil
+}
+
+// parsePushedSince parses a date (2024-01-01), an RFC 3339 timestamp or an
+// age in days (30d), which suits scheduled sweeps of recently active repositories
+func parsePushedSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --pushed-since: %s (expected a date such as 2024-01-01 or an age such as 30d)", value)
+}
+
+// runOrgScan lists the repositories of a GitHub organisation and scans those
+// that pass the filter in the same way as a repository list
+func runOrgScan(ctx context.Context, org string, filter repository.OrgFilter, apiURL, outputFile, outputDir string,
+	concurrency int, scanConfig scanner.Config) error {
+
+	client := repository.NewOrgClient(apiURL, repository.GitHubToken(ctx, repositoryConfig(scanConfig)))
+	repos, err := client.ListRepositories(ctx, org, filter)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories in %s match the filters", org)
+	}
+
+	fmt.Fprintf(scanConfig.Output, "📚 Found %d repositories in %s\n", len(repos), org)
+
+	urls := make([]string, len(repos))
+	for i, repo := range repos {
+		urls[i] = repo.HTMLURL
+	}
+
+	// GitHub Enterprise hosts are not recognised by name, so map them for cloning
+	if apiURL != "" && apiURL != repository.DefaultGitHubAPIURL {
+		hosts := make(map[string]repository.ProviderKind, len(scanConfig.ProviderHosts)+1)
+		for host, kind := range scanConfig.ProviderHosts {
+			hosts[host] = kind
+		}
+		for _, repoURL := range urls {
+			if u, err := url.Parse(repoURL); err == nil && u.Host != "" {
+				if _, ok := hosts[u.Host]; !ok {
+					hosts[u.Host] = repository.ProviderGitHub
+				}
+			}
+		}
+		scanConfig.ProviderHosts = hosts
+	}
+
+	return scanRepositoryList(ctx, "org:"+org, urls, outputFile, outputDir, concurrency, scanConfig)
+}
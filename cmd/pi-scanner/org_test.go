@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePushedSince(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2024-01-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-05-01T09:30:00Z", want: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)},
+		{value: "30d", want: time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)},
+		{value: "last month", wantErr: true},
+		{value: "-5d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parsePushedSince(tt.value, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScanOrg(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/acme/repos" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[{"name": "legacy", "html_url": "https://github.com/acme/legacy", "archived": true, "pushed_at": "2024-05-01T00:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := newRootCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stdout)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	output, err := run("scan", "--org", "acme", "--github-api-url", server.URL, "--output-dir", t.TempDir())
+	assert.ErrorContains(t, err, "no repositories in acme match the filters", output)
+	assert.Contains(t, output, "Listing repositories in organisation: acme")
+
+	_, err = run("scan", "--org", "missing", "--github-api-url", server.URL)
+	assert.ErrorContains(t, err, "404")
+
+	_, err = run("scan", "--org", "acme", "--visibility", "secret")
+	assert.ErrorContains(t, err, "invalid --visibility")
+
+	_, err = run("scan", "--org", "acme", "--pushed-since", "yesterday")
+	assert.ErrorContains(t, err, "invalid --pushed-since")
+
+	_, err = run("scan", "--path", ".", "--topic", "pii")
+	assert.ErrorContains(t, err, "require --org")
+
+	_, err = run("scan", "--org", "acme", "--format", "sarif")
+	assert.ErrorContains(t, err, "not supported with --repo-list or --org")
+
+	_, err = run("scan", "--org", "acme", "--repo", "https://github.com/acme/legacy")
+	assert.Error(t, err)
+}
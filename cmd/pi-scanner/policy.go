@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/policy"
+)
+
+// Exit codes returned by pi-scanner
+const (
+	exitClean           = 0 // Completed without policy violations
+	exitPolicyViolation = 1 // Findings violate the --fail-on or --max-count policy
+	exitScanError       = 2 // Invalid usage, or a scan could not complete
+)
+
+// policyViolationError reports findings that break the scan policy
+type policyViolationError struct {
+	violations []string
+}
+
+func (e *policyViolationError) Error() string {
+	return "policy violated: " + strings.Join(e.violations, "; ")
+}
+
+// exitCode maps a command error to the process exit code
+func exitCode(err error) int {
+	if err == nil {
+		return exitClean
+	}
+
+	var violation *policyViolationError
+	if errors.As(err, &violation) {
+		return exitPolicyViolation
+	}
+
+	return exitScanError
+}
+
+// buildPolicy creates the scan policy from command line flags. It returns nil
+// when no rules are set, in which case findings never fail the command.
+// --max-count accepts the built-in types and those of the user-defined rules.
+func buildPolicy(failOn, maxCounts string, validatedOnly bool, rules []detection.Rule) (*policy.Policy, error) {
+	levels, err := policy.ParseRiskLevels(failOn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --fail-on: %w", err)
+	}
+
+	known := detection.PITypes()
+	for _, rule := range rules {
+		known = append(known, rule.Type)
+	}
+
+	counts, err := policy.ParseMaxCounts(maxCounts, known)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --max-count: %w", err)
+	}
+
+	p := &policy.Policy{FailOn: levels, MaxCounts: counts, ValidatedOnly: validatedOnly}
+	if !p.Enabled() {
+		if validatedOnly {
+			return nil, fmt.Errorf("--validated-only requires --fail-on or --max-count")
+		}
+		return nil, nil
+	}
+
+	return p, nil
+}
+
+// checkResult turns a saved scan result into the command outcome
+func checkResult(result *ScanResult) error {
+	if result.Error != "" {
+		return fmt.Errorf("scan failed: %s", result.Error)
+	}
+
+	if len(result.PolicyViolations) > 0 {
+		messages := make([]string, 0, len(result.PolicyViolations))
+		for _, v := range result.PolicyViolations {
+			messages = append(messages, v.Message)
+		}
+		return &policyViolationError{violations: messages}
+	}
+
+	return nil
+}
+
+// checkIndex turns a multi-repository scan index into the command outcome
+func checkIndex(index *ScanIndex) error {
+	if index.Failed > 0 {
+		return fmt.Errorf("scan failed for %d of %d repositories", index.Failed, index.TotalRepositories)
+	}
+
+	var messages []string
+	for _, entry := range index.Repositories {
+		for _, v := range entry.PolicyViolations {
+			messages = append(messages, fmt.Sprintf("%s: %s", entry.URL, v.Message))
+		}
+	}
+	if len(messages) > 0 {
+		return &policyViolationError{violations: messages}
+	}
+
+	return nil
+}
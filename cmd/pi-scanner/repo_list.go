@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/policy"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/MacAttak/pi-scanner/pkg/scanner"
+)
+
+// ScanIndex summarises a multi-repository scan and points at the per-repo results
+type ScanIndex struct {
+	Source            string           `json:"source"`
+	ScanStarted       time.Time        `json:"scan_started"`
+	ScanFinished      time.Time        `json:"scan_finished"`
+	Duration          time.Duration    `json:"duration"`
+	TotalRepositories int              `json:"total_repositories"`
+	Succeeded         int              `json:"succeeded"`
+	Failed            int              `json:"failed"`
+	TotalFindings     int              `json:"total_findings"`
+	FindingsByRisk    map[string]int   `json:"findings_by_risk"`
+	Repositories      []ScanIndexEntry `json:"repositories"`
+}
+
+// ScanIndexEntry records the outcome of scanning one repository
+type ScanIndexEntry struct {
+	URL            string         `json:"url"`
+	ResultFile     string         `json:"result_file,omitempty"`
+	FilesScanned   int            `json:"files_scanned"`
+	Findings       int            `json:"findings"`
+	FindingsByRisk map[string]int `json:"findings_by_risk,omitempty"`
+	Duration       time.Duration  `json:"duration"`
+	Error          string         `json:"error,omitempty"`
+
+	PolicyViolations []policy.Violation `json:"policy_violations,omitempty"`
+}
+
+// readRepoList reads repository URLs from a file, one per line. Blank lines and
+// lines starting with '#' are ignored, as are trailing " # comment" annotations.
+// Duplicate URLs are only returned once.
+func readRepoList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository list: %w", err)
+	}
+	defer file.Close()
+
+	var repos []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Strip trailing comments
+		if idx := strings.Index(line, " #"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if idx := strings.Index(line, "\t#"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		repos = append(repos, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+
+	return repos, nil
+}
+
+// runRepoListScan scans every repository in a list file using a bounded pool of
+// workers, writing one result file per repository plus a combined index.
+func runRepoListScan(ctx context.Context, repoListFile, outputFile, outputDir string, concurrency int, scanConfig scanner.Config) error {
+	repos, err := readRepoList(repoListFile)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories found in %s", repoListFile)
+	}
+
+	return scanRepositoryList(ctx, repoListFile, repos, outputFile, outputDir, concurrency, scanConfig)
+}
+
+// scanRepositoryList clones and scans repositories concurrently, then checks
+// the combined index against the policy. The source names the list in the index.
+func scanRepositoryList(ctx context.Context, source string, repos []string, outputFile, outputDir string,
+	concurrency int, scanConfig scanner.Config) error {
+
+	repoManager := repository.NewRepositoryManager(repositoryConfig(scanConfig))
+	if err := repoManager.CheckRepositoryAuthentication(ctx, repos...); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	defer repoManager.CleanupAll()
+
+	index, err := scanRepositories(ctx, source, repos, outputFile, outputDir, concurrency, scanConfig.Output, scanConfig.Verbose,
+		func(ctx context.Context, repoURL string) *ScanResult {
+			return scanRepository(ctx, repoManager, repoURL, scanConfig)
+		})
+	if err != nil {
+		return err
+	}
+
+	return checkIndex(index)
+}
+
+// scanRepositories fans repository scans out over a bounded worker pool. A
+// failure in one repository is recorded in the index and does not stop the run.
+// Progress messages go to out.
+func scanRepositories(ctx context.Context, source string, repos []string, outputFile, outputDir string,
+	concurrency int, out io.Writer, verbose bool, scan func(context.Context, string) *ScanResult) (*ScanIndex, error) {
+
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	index := &ScanIndex{
+		Source:            source,
+		ScanStarted:       time.Now(),
+		TotalRepositories: len(repos),
+		FindingsByRisk:    make(map[string]int),
+		Repositories:      make([]ScanIndexEntry, len(repos)),
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				index.Repositories[i] = scanIndexedRepository(ctx, repos[i], outputDir, out, verbose, scan)
+			}
+		}()
+	}
+
+	for i := range repos {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, entry := range index.Repositories {
+		if entry.URL == "" {
+			// Never dispatched because the context was cancelled
+			entry = ScanIndexEntry{URL: repos[i], Error: "scan cancelled"}
+			index.Repositories[i] = entry
+		}
+
+		if entry.Error != "" {
+			index.Failed++
+		} else {
+			index.Succeeded++
+		}
+		index.TotalFindings += entry.Findings
+		for risk, count := range entry.FindingsByRisk {
+			index.FindingsByRisk[risk] += count
+		}
+	}
+
+	index.ScanFinished = time.Now()
+	index.Duration = index.ScanFinished.Sub(index.ScanStarted)
+
+	fmt.Fprintf(out, "📚 Scanned %d repositories: %d succeeded, %d failed, %d findings\n",
+		index.TotalRepositories, index.Succeeded, index.Failed, index.TotalFindings)
+
+	if err := saveIndex(out, index, outputFile); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// scanIndexedRepository scans a single repository from a list and saves its result
+func scanIndexedRepository(ctx context.Context, repoURL, outputDir string, out io.Writer, verbose bool,
+	scan func(context.Context, string) *ScanResult) ScanIndexEntry {
+
+	entry := ScanIndexEntry{URL: repoURL}
+
+	if err := validateRepositoryURL(repoURL); err != nil {
+		entry.Error = fmt.Sprintf("Invalid repository URL: %v", err)
+		return entry
+	}
+
+	if verbose {
+		fmt.Fprintf(out, "🔍 Starting PI scan of repository: %s\n", repoURL)
+	}
+
+	result := scan(ctx, repoURL)
+	if result.Repository == nil {
+		result.Repository = &repository.RepositoryInfo{URL: repoURL}
+	}
+
+	entry.ResultFile = filepath.Join(outputDir, resultFileName(repoURL))
+	entry.FilesScanned = result.FilesScanned
+	entry.Findings = len(result.Findings)
+	entry.FindingsByRisk = result.Stats.FindingsByRisk
+	entry.Duration = result.Duration
+	entry.Error = result.Error
+	entry.PolicyViolations = result.PolicyViolations
+
+	if err := saveResult(out, result, entry.ResultFile); err != nil {
+		entry.ResultFile = ""
+		if entry.Error == "" {
+			entry.Error = err.Error()
+		}
+	}
+
+	return entry
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// resultFileName derives a stable, filesystem-safe result file name for a repository URL
+func resultFileName(repoURL string) string {
+	name := repoURL
+	if u, err := url.Parse(repoURL); err == nil && u.Host != "" {
+		name = u.Host + "/" + strings.Trim(u.Path, "/")
+	}
+	name = strings.TrimSuffix(name, ".git")
+	name = unsafeFileChars.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		name = "repository"
+	}
+	return name + ".json"
+}
+
+// saveIndex writes the combined index for a multi-repository scan. An output
+// file of "-" writes to stdout; progress messages go to out.
+func saveIndex(out io.Writer, index *ScanIndex, outputFile string) error {
+	jsonData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan index: %w", err)
+	}
+
+	if outputFile == "-" {
+		if _, err := fmt.Fprintln(os.Stdout, string(jsonData)); err != nil {
+			return fmt.Errorf("failed to write scan index: %w", err)
+		}
+		return nil
+	}
+
+	dir := filepath.Dir(outputFile)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write scan index: %w", err)
+	}
+
+	fmt.Fprintf(out, "✅ Scan index saved to: %s\n", outputFile)
+	return nil
+}
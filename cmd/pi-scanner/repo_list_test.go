@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/MacAttak/pi-scanner/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRepoList(t *testing.T) {
+	content := `# Production repositories
+https://github.com/org/api
+
+  https://github.com/org/web   # frontend
+https://github.com/org/api
+	# indented comment
+https://gitlab.com/group/sub/project
+`
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	repos, err := readRepoList(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://github.com/org/api",
+		"https://github.com/org/web",
+		"https://gitlab.com/group/sub/project",
+	}, repos)
+
+	_, err = readRepoList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestResultFileName(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/org/api", "github.com_org_api.json"},
+		{"https://github.com/org/api.git", "github.com_org_api.json"},
+		{"https://gitlab.example.com/group/sub/project/", "gitlab.example.com_group_sub_project.json"},
+		{"not a url", "not_a_url.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resultFileName(tt.url))
+		})
+	}
+}
+
+func TestScanRepositories(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "results")
+	indexFile := filepath.Join(tmpDir, "index.json")
+
+	repos := []string{
+		"https://github.com/org/one",
+		"https://github.com/org/broken",
+		"invalid-url",
+		"https://github.com/org/two",
+	}
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	scanned := make(map[string]bool)
+
+	scan := func(ctx context.Context, repoURL string) *ScanResult {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			prev := atomic.LoadInt32(&maxRunning)
+			if current <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		mu.Lock()
+		scanned[repoURL] = true
+		mu.Unlock()
+
+		result := scanner.NewScanResult()
+		if repoURL == "https://github.com/org/broken" {
+			result.Error = "Failed to clone repository: not found"
+			return result
+		}
+
+		result.Repository = &repository.RepositoryInfo{URL: repoURL}
+		result.FilesScanned = 3
+		result.Findings = []scanner.Finding{{Finding: detection.Finding{Type: detection.PITypeTFN, RiskLevel: detection.RiskLevelHigh}}}
+		result.Stats.FindingsByRisk[string(detection.RiskLevelHigh)] = 1
+		return result
+	}
+
+	summary, err := scanRepositories(context.Background(), "repos.txt", repos, indexFile, outputDir, 2, io.Discard, false, scan)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Failed)
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+	assert.False(t, scanned["invalid-url"], "invalid URLs should not be scanned")
+
+	data, err := os.ReadFile(indexFile)
+	require.NoError(t, err)
+
+	var index ScanIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+
+	assert.Equal(t, 4, index.TotalRepositories)
+	assert.Equal(t, 2, index.Succeeded)
+	assert.Equal(t, 2, index.Failed)
+	assert.Equal(t, 2, index.TotalFindings)
+	assert.Equal(t, 2, index.FindingsByRisk["HIGH"])
+	require.Len(t, index.Repositories, 4)
+
+	// Entries keep the order of the input list
+	for i, entry := range index.Repositories {
+		assert.Equal(t, repos[i], entry.URL)
+	}
+
+	assert.Contains(t, index.Repositories[1].Error, "Failed to clone")
+	assert.Contains(t, index.Repositories[2].Error, "Invalid repository URL")
+	assert.Empty(t, index.Repositories[2].ResultFile)
+
+	for _, i := range []int{0, 1, 3} {
+		_, err := os.Stat(index.Repositories[i].ResultFile)
+		assert.NoError(t, err, "result file should exist for %s", repos[i])
+	}
+}
+
+func TestScanRepositories_IndexToStdout(t *testing.T) {
+	// Relative paths resolve in the working directory, where no "-" file may appear
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	repos := []string{"https://github.com/org/api"}
+	scan := func(ctx context.Context, repoURL string) *ScanResult {
+		result := scanner.NewScanResult()
+		result.Repository = &repository.RepositoryInfo{URL: repoURL}
+		return result
+	}
+
+	var progress bytes.Buffer
+	stdout := captureStdout(t, func() {
+		_, err = scanRepositories(context.Background(), "repos.txt", repos, "-", "results", 1, &progress, true, scan)
+	})
+	require.NoError(t, err)
+
+	// Only the index is written to stdout
+	var index ScanIndex
+	require.NoError(t, json.Unmarshal([]byte(stdout), &index), stdout)
+	assert.Equal(t, 1, index.Succeeded)
+	assert.NoFileExists(t, "-")
+	assert.Contains(t, progress.String(), "Results saved to: results/github.com_org_api.json")
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/report"
+	"github.com/google/uuid"
+)
+
+const (
+	toolName = "PI Scanner"
+	toolURI  = "https://github.com/MacAttak/pi-scanner"
+)
+
+// runReport loads a saved scan result and renders it in the requested format
+func runReport(out io.Writer, inputFile, format, outputFile string) error {
+	format = strings.ToLower(format)
+	switch format {
+	case "html", "csv", "sarif":
+		if outputFile == "" {
+			outputFile = "report." + format
+		}
+	case "github":
+		if outputFile == "" {
+			outputFile = "-"
+		}
+	default:
+		return fmt.Errorf("unsupported report format: %s (expected html, csv, sarif or github)", format)
+	}
+
+	result, err := loadScanResult(inputFile)
+	if err != nil {
+		return err
+	}
+
+	records := buildIntegrationRecords(result)
+	metadata := buildExportMetadata(result)
+
+	w, closeOutput, err := openOutput(out, outputFile)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	switch format {
+	case "html":
+		err = writeHTMLReport(w, result, records)
+	case "csv":
+		err = writeCSVReport(w, records, metadata)
+	case "sarif":
+		err = writeSARIFReport(w, result, records, metadata)
+	case "github":
+		err = writeGitHubAnnotations(w, result, records)
+	}
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "-" {
+		fmt.Fprintf(out, "✅ Report saved to: %s\n", outputFile)
+	}
+	return nil
+}
+
+// openOutput opens a report destination, creating its directory if needed.
+// A path of "-" writes to stdout.
+func openOutput(stdout io.Writer, path string) (io.Writer, func(), error) {
+	if path == "-" {
+		return stdout, func() {}, nil
+	}
+
+	// Create output directory if needed
+	dir := filepath.Dir(path)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create report file: %w", err)
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// loadScanResult reads a scan result JSON file produced by saveResult
+func loadScanResult(path string) (*ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan results: %w", err)
+	}
+
+	var result ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse scan results: %w", err)
+	}
+
+	return &result, nil
+}
+
+// buildIntegrationRecords converts saved findings into report integration
+// records, carrying over the confidence scores and risk assessments
+func buildIntegrationRecords(result *ScanResult) []report.IntegrationRecord {
+	records := make([]report.IntegrationRecord, 0, len(result.Findings))
+	for _, finding := range result.Findings {
+		record := report.IntegrationRecord{
+			Finding:         finding.Finding,
+			ConfidenceScore: float64(finding.Confidence),
+			RiskAssessment:  finding.RiskAssessment,
+			Environment:     findingEnvironment(finding.Finding),
+		}
+		if finding.ConfidenceResult != nil {
+			record.ConfidenceScore = finding.ConfidenceResult.FinalScore
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// buildExportMetadata maps scan result details onto export metadata
+func buildExportMetadata(result *ScanResult) report.ExportMetadata {
+	metadata := report.ExportMetadata{
+		ScanID:       uuid.NewString(),
+		ScanDuration: result.Duration,
+		ToolVersion:  version,
+		Timestamp:    result.ScanStarted,
+	}
+
+	if result.Repository != nil {
+		metadata.Repository = result.Repository.URL
+		if metadata.Repository == "" {
+			metadata.Repository = result.Repository.Name
+		}
+	}
+
+	if metadata.Timestamp.IsZero() {
+		metadata.Timestamp = time.Now()
+	}
+
+	return metadata
+}
+
+// findingEnvironment classifies a finding using the detector's context modifier
+func findingEnvironment(finding detection.Finding) string {
+	switch {
+	case finding.ContextModifier > 0 && finding.ContextModifier <= 0.1:
+		return "test"
+	case finding.ContextModifier > 0 && finding.ContextModifier < 1.0:
+		return "example"
+	default:
+		return "production"
+	}
+}
+
+// writeHTMLReport renders the HTML report template
+func writeHTMLReport(w io.Writer, result *ScanResult, records []report.IntegrationRecord) error {
+	tmpl, err := report.GetHTMLTemplate()
+	if err != nil {
+		return fmt.Errorf("failed to load HTML template: %w", err)
+	}
+
+	data := buildHTMLTemplateData(result, records)
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+
+	return nil
+}
+
+// writeCSVReport writes findings as CSV rows
+func writeCSVReport(w io.Writer, records []report.IntegrationRecord, metadata report.ExportMetadata) error {
+	exporter := report.NewCSVExporter(report.WithMaskedValues(), report.WithContext(), report.WithMetadata())
+
+	rows := make([]report.CSVRecord, 0, len(records))
+	for _, ir := range records {
+		row := exporter.ConvertIntegrationRecord(ir, metadata)
+		row.CodeContext = ir.Finding.Context
+		if ir.RiskAssessment == nil {
+			row.RiskLevel = string(ir.Finding.RiskLevel)
+		}
+		rows = append(rows, row)
+	}
+
+	if err := exporter.Export(w, rows); err != nil {
+		return fmt.Errorf("failed to write CSV report: %w", err)
+	}
+
+	return nil
+}
+
+// writeSARIFReport writes findings as a SARIF log
+func writeSARIFReport(w io.Writer, result *ScanResult, records []report.IntegrationRecord, metadata report.ExportMetadata) error {
+	exporter := report.NewSARIFExporter(toolName, version, toolURI)
+	if result.Repository != nil && result.Repository.LocalPath != "" {
+		exporter.SetBaseURI(result.Repository.LocalPath)
+	}
+
+	// Baseline findings that were not detected again are reported as absent
+	for _, finding := range result.AbsentFindings {
+		records = append(records, report.IntegrationRecord{
+			Finding:     finding,
+			Environment: findingEnvironment(finding),
+		})
+	}
+
+	if err := exporter.ExportWithRiskAssessment(w, records, metadata); err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+
+	return nil
+}
+
+// writeGitHubAnnotations writes findings as GitHub Actions workflow annotations
+func writeGitHubAnnotations(w io.Writer, result *ScanResult, records []report.IntegrationRecord) error {
+	exporter := report.NewGitHubAnnotationExporter()
+	if result.Repository != nil && result.Repository.LocalPath != "" {
+		exporter.SetBaseURI(result.Repository.LocalPath)
+	}
+
+	if err := exporter.Export(w, records); err != nil {
+		return fmt.Errorf("failed to write GitHub annotations: %w", err)
+	}
+
+	return nil
+}
+
+// buildHTMLTemplateData maps a scan result onto the HTML template model
+func buildHTMLTemplateData(result *ScanResult, records []report.IntegrationRecord) report.HTMLTemplateData {
+	data := report.HTMLTemplateData{
+		ReportID:     uuid.NewString(),
+		GeneratedAt:  time.Now(),
+		ScanDuration: result.Duration.Round(time.Millisecond).String(),
+		ToolVersion:  version,
+		Statistics: report.Statistics{
+			TypeDistribution:     make(map[string]int),
+			RiskDistribution:     make(map[string]int),
+			FileTypeDistribution: make(map[string]int),
+		},
+		Compliance: report.ComplianceInfo{
+			APRACompliant:       true,
+			PrivacyActCompliant: true,
+		},
+	}
+
+	if result.Repository != nil {
+		data.Repository = report.RepositoryInfo{
+			Name:           result.Repository.Name,
+			URL:            result.Repository.URL,
+			LastCommitDate: result.Repository.LastCommit,
+		}
+	}
+	data.Repository.FilesScanned = result.FilesScanned
+
+	types := make(map[string]bool)
+	notifications := make(map[string]bool)
+	fileCounts := make(map[string]*report.FileStats)
+
+	for i, ir := range records {
+		finding := buildHTMLFinding(i, ir)
+
+		switch finding.RiskLevel {
+		case string(detection.RiskLevelCritical):
+			data.CriticalFindings = append(data.CriticalFindings, finding)
+			data.Summary.CriticalCount++
+		case string(detection.RiskLevelHigh):
+			data.HighFindings = append(data.HighFindings, finding)
+			data.Summary.HighCount++
+		case string(detection.RiskLevelMedium):
+			data.MediumFindings = append(data.MediumFindings, finding)
+			data.Summary.MediumCount++
+		default:
+			data.LowFindings = append(data.LowFindings, finding)
+			data.Summary.LowCount++
+		}
+
+		data.Summary.TotalFindings++
+		data.Statistics.TypeDistribution[finding.Type]++
+		data.Statistics.RiskDistribution[finding.RiskLevel]++
+		types[finding.Type] = true
+
+		if ext := strings.ToLower(filepath.Ext(finding.File)); ext != "" {
+			data.Statistics.FileTypeDistribution[ext]++
+		}
+
+		stats, ok := fileCounts[finding.File]
+		if !ok {
+			stats = &report.FileStats{Path: finding.File}
+			fileCounts[finding.File] = stats
+		}
+		stats.FindingsCount++
+		if finding.RiskAssessment.OverallRisk > stats.RiskScore {
+			stats.RiskScore = finding.RiskAssessment.OverallRisk
+		}
+
+		if finding.Validated {
+			data.Summary.ValidatedCount++
+			data.Statistics.ValidationStats.ValidCount++
+		} else {
+			data.Statistics.ValidationStats.InvalidCount++
+		}
+		data.Statistics.ValidationStats.TotalChecked++
+
+		switch ir.Environment {
+		case "test":
+			data.Summary.TestDataCount++
+			data.Statistics.EnvironmentStats.TestFindings++
+		case "example":
+			data.Statistics.EnvironmentStats.MockFindings++
+		default:
+			data.Statistics.EnvironmentStats.ProductionFindings++
+		}
+		if isConfigFile(finding.File) {
+			data.Statistics.EnvironmentStats.ConfigFindings++
+		}
+
+		if ir.RiskAssessment != nil {
+			flags := ir.RiskAssessment.ComplianceFlags
+			if flags.APRAReporting {
+				data.Compliance.APRACompliant = false
+			}
+			if flags.PrivacyActBreach {
+				data.Compliance.PrivacyActCompliant = false
+			}
+			if flags.NotifiableDataBreach {
+				data.Compliance.NotifiableBreaches++
+			}
+			for _, n := range flags.RequiredNotifications {
+				notifications[n] = true
+			}
+		}
+	}
+
+	if total := data.Statistics.ValidationStats.TotalChecked; total > 0 {
+		data.Statistics.ValidationStats.ValidationRate = float64(data.Statistics.ValidationStats.ValidCount) / float64(total)
+	}
+
+	for t := range types {
+		data.Summary.UniqueTypes = append(data.Summary.UniqueTypes, t)
+	}
+	sort.Strings(data.Summary.UniqueTypes)
+
+	for n := range notifications {
+		data.Compliance.RequiredNotifications = append(data.Compliance.RequiredNotifications, n)
+	}
+	sort.Strings(data.Compliance.RequiredNotifications)
+
+	data.Statistics.TopAffectedFiles = topAffectedFiles(fileCounts, 10)
+
+	return data
+}
+
+// buildHTMLFinding converts an integration record into an HTML report finding
+func buildHTMLFinding(index int, ir report.IntegrationRecord) report.Finding {
+	f := ir.Finding
+	finding := report.Finding{
+		ID:              fmt.Sprintf("finding-%d", index+1),
+		Type:            string(f.Type),
+		TypeDisplay:     report.GetPITypeDisplay(f.Type),
+		RiskLevel:       string(f.RiskLevel),
+		ConfidenceScore: ir.ConfidenceScore,
+		File:            f.File,
+		Line:            f.Line,
+		Column:          f.Column,
+		MaskedMatch:     report.MaskSensitiveData(f.Match, string(f.Type)),
+		Context:         f.Context,
+		Validated:       f.Validated,
+		IsTestData:      ir.Environment == "test",
+	}
+
+	if f.Suppression != nil {
+		finding.Suppressed = true
+		finding.Suppression = f.Suppression.Justification
+	}
+
+	if ra := ir.RiskAssessment; ra != nil {
+		finding.RiskLevel = string(ra.RiskLevel)
+		finding.RiskAssessment = report.RiskAssessmentInfo{
+			OverallRisk:     ra.OverallRisk,
+			ImpactScore:     ra.ImpactScore,
+			LikelihoodScore: ra.LikelihoodScore,
+			ExposureScore:   ra.ExposureScore,
+			RiskCategory:    string(ra.RiskCategory),
+		}
+		for _, m := range ra.Mitigations {
+			finding.Mitigations = append(finding.Mitigations, report.Mitigation{
+				Title:       m.Title,
+				Description: m.Description,
+				Priority:    m.Priority,
+				Effort:      m.Effort,
+				Timeline:    m.Timeline,
+			})
+		}
+	}
+
+	return finding
+}
+
+// topAffectedFiles returns the files with the most findings
+func topAffectedFiles(files map[string]*report.FileStats, limit int) []report.FileStats {
+	result := make([]report.FileStats, 0, len(files))
+	for _, stats := range files {
+		result = append(result, *stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FindingsCount != result[j].FindingsCount {
+			return result[i].FindingsCount > result[j].FindingsCount
+		}
+		return result[i].Path < result[j].Path
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// isConfigFile reports whether a path looks like a configuration file
+func isConfigFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json", ".toml", ".ini", ".cfg", ".conf", ".config", ".env", ".properties":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/MacAttak/pi-scanner/pkg/config"
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/scanner"
+)
+
+// runRulesList prints the built-in patterns and the user-defined rules from
+// the configuration and ruleFiles, in the order they claim overlapping values
+func runRulesList(out io.Writer, configFile string, ruleFiles []string) error {
+	if configFile != "" {
+		if _, err := os.Stat(configFile); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	cfg, err := config.LoadConfigWithDefaults(configFile)
+	if err != nil {
+		return err
+	}
+
+	detectionConfig := scanner.FromConfig(cfg).Detection
+	detectionConfig.Rules, err = detection.LoadRules(append(cfg.Scanner.Rules, ruleFiles...)...)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tVALIDATOR\tKEYWORDS\tRISK\tSOURCE")
+	for _, rule := range detection.ListRules(detectionConfig) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			rule.ID, rule.Type, orDash(rule.Validator), orDash(strings.Join(rule.Keywords, ",")), rule.RiskLevel, rule.Source)
+	}
+	return w.Flush()
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -4,272 +4,210 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"time"
+	"strings"
 
+	"github.com/MacAttak/pi-scanner/pkg/config"
 	"github.com/MacAttak/pi-scanner/pkg/detection"
-	"github.com/MacAttak/pi-scanner/pkg/discovery"
-	"github.com/MacAttak/pi-scanner/pkg/processing"
+	"github.com/MacAttak/pi-scanner/pkg/report"
 	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/MacAttak/pi-scanner/pkg/scanner"
 )
 
 // ScanResult represents the results of scanning a repository
-type ScanResult struct {
-	Repository   *repository.RepositoryInfo `json:"repository"`
-	ScanStarted  time.Time                  `json:"scan_started"`
-	ScanFinished time.Time                  `json:"scan_finished"`
-	Duration     time.Duration              `json:"duration"`
-	FilesScanned int                        `json:"files_scanned"`
-	Findings     []detection.Finding        `json:"findings"`
-	Stats        ScanStats                  `json:"stats"`
-	Error        string                     `json:"error,omitempty"`
-}
+type ScanResult = scanner.ScanResult
+
+// loadScanConfig loads the YAML configuration and maps it onto the scan pipeline
+func loadScanConfig(out io.Writer, configFile string, verbose bool) (scanner.Config, error) {
+	if configFile != "" {
+		fmt.Fprintf(out, "Using configuration: %s\n", configFile)
+		if _, err := os.Stat(configFile); err != nil {
+			return scanner.Config{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
 
-// ScanStats provides statistics about the scan
-type ScanStats struct {
-	TotalFiles     int            `json:"total_files"`
-	ScannedFiles   int            `json:"scanned_files"`
-	SkippedFiles   int            `json:"skipped_files"`
-	TotalSize      int64          `json:"total_size"`
-	FindingsByType map[string]int `json:"findings_by_type"`
-	FindingsByRisk map[string]int `json:"findings_by_risk"`
-	ProcessingTime time.Duration  `json:"processing_time"`
-}
+	cfg, err := config.LoadConfigWithDefaults(configFile)
+	if err != nil {
+		return scanner.Config{}, err
+	}
 
-// runScan performs the actual scanning logic
-func runScan(ctx context.Context, repoURL, outputFile string, verbose bool) error {
-	result := &ScanResult{
-		ScanStarted: time.Now(),
-		Stats: ScanStats{
-			FindingsByType: make(map[string]int),
-			FindingsByRisk: make(map[string]int),
-		},
+	scanConfig := scanner.FromConfig(cfg)
+	scanConfig.Verbose = verbose
+
+	if len(cfg.Scanner.Rules) > 0 {
+		rules, err := detection.LoadRules(cfg.Scanner.Rules...)
+		if err != nil {
+			return scanner.Config{}, err
+		}
+		scanConfig.Detection.Rules = rules
+		fmt.Fprintf(out, "Using %d detection rules from %s\n", len(rules), strings.Join(cfg.Scanner.Rules, ", "))
 	}
 
+	return scanConfig, nil
+}
+
+// runScan performs the actual scanning logic
+func runScan(ctx context.Context, repoURL, outputFile, format string, scanConfig scanner.Config) error {
+	verbose := scanConfig.Verbose
 	if verbose {
-		fmt.Printf("🔍 Starting PI scan of repository: %s\n", repoURL)
+		fmt.Fprintf(scanConfig.Output, "🔍 Starting PI scan of repository: %s\n", repoURL)
 	}
 
 	// Step 1: Set up repository manager
-	repoConfig := repository.DefaultGitHubConfig()
-	repoManager := repository.NewRepositoryManager(repoConfig)
+	repoManager := repository.NewRepositoryManager(repositoryConfig(scanConfig))
 
 	// Check authentication
 	if verbose {
-		fmt.Printf("🔐 Checking GitHub authentication...\n")
+		fmt.Fprintf(scanConfig.Output, "🔐 Checking repository authentication...\n")
 	}
 
-	err := repoManager.CheckAuthentication(ctx)
+	err := repoManager.CheckRepositoryAuthentication(ctx, repoURL)
 	if err != nil {
+		result := scanner.NewScanResult()
 		result.Error = fmt.Sprintf("Authentication failed: %v", err)
-		return saveResult(result, outputFile)
+		if err := writeResult(scanConfig.Output, result, outputFile, format); err != nil {
+			return err
+		}
+		return checkResult(result)
 	}
 
 	if verbose {
-		fmt.Printf("✅ GitHub authentication successful\n")
+		fmt.Fprintf(scanConfig.Output, "✅ Repository authentication successful\n")
+	}
+
+	result := scanRepository(ctx, repoManager, repoURL, scanConfig)
+
+	// Step 9: Save results
+	if err := writeResult(scanConfig.Output, result, outputFile, format); err != nil {
+		return err
 	}
+	return checkResult(result)
+}
+
+// scanRepository clones a repository, runs the detection pipeline over it and
+// removes the clone afterwards. Failures are recorded on the returned result.
+func scanRepository(ctx context.Context, repoManager *repository.RepositoryManager, repoURL string, scanConfig scanner.Config) *ScanResult {
+	verbose := scanConfig.Verbose
 
 	// Step 2: Clone repository
 	if verbose {
-		fmt.Printf("📥 Cloning repository...\n")
+		fmt.Fprintf(scanConfig.Output, "📥 Cloning repository...\n")
 	}
 
 	repoInfo, err := repoManager.CloneAndTrack(ctx, repoURL)
 	if err != nil {
+		result := scanner.NewScanResult()
 		result.Error = fmt.Sprintf("Failed to clone repository: %v", err)
-		return saveResult(result, outputFile)
+		return result
 	}
 
-	result.Repository = repoInfo
-
 	// Ensure cleanup happens
 	defer func() {
 		if verbose {
-			fmt.Printf("🧹 Cleaning up cloned repository...\n")
+			fmt.Fprintf(scanConfig.Output, "🧹 Cleaning up cloned repository...\n")
 		}
-		repoManager.CleanupAll()
+		repoManager.Cleanup(repoInfo.LocalPath)
 	}()
 
 	if verbose {
-		fmt.Printf("✅ Repository cloned to: %s\n", repoInfo.LocalPath)
-		fmt.Printf("📊 Repository info: %d files, %d bytes\n", repoInfo.FileCount, repoInfo.Size)
+		fmt.Fprintf(scanConfig.Output, "✅ Repository cloned to: %s\n", repoInfo.LocalPath)
+		fmt.Fprintf(scanConfig.Output, "📊 Repository info: %d files, %d bytes\n", repoInfo.FileCount, repoInfo.Size)
 	}
 
-	// Step 3: Set up detectors
-	if verbose {
-		fmt.Printf("🔧 Setting up detection pipeline...\n")
+	if err := repoManager.LookupMetadata(ctx, repoInfo); err != nil && verbose {
+		fmt.Fprintf(scanConfig.Output, "⚠️  %v\n", err)
 	}
 
-	var detectors []detection.Detector
-
-	// Add pattern detector
-	patternDetector := detection.NewDetector()
-	detectors = append(detectors, patternDetector)
-
-	// Add Gitleaks detector
-	gitleaksConfigPath := filepath.Join("configs", "gitleaks.toml")
-	if _, err := os.Stat(gitleaksConfigPath); err == nil {
-		gitleaksDetector, err := detection.NewGitleaksDetector(gitleaksConfigPath)
-		if err != nil {
-			if verbose {
-				fmt.Printf("⚠️  Gitleaks detector setup failed: %v\n", err)
-			}
-		} else {
-			detectors = append(detectors, gitleaksDetector)
-			if verbose {
-				fmt.Printf("✅ Gitleaks detector loaded\n")
-			}
-		}
-	} else {
-		if verbose {
-			fmt.Printf("⚠️  Gitleaks config not found, skipping\n")
-		}
-	}
-
-	if verbose {
-		fmt.Printf("✅ %d detectors configured\n", len(detectors))
+	// Steps 3-8: Run the detection pipeline
+	if scanConfig.History != nil {
+		return scanner.New(scanConfig).ScanHistory(ctx, repoInfo, *scanConfig.History)
 	}
-
-	// Step 4: Discover files
-	if verbose {
-		fmt.Printf("🔍 Discovering files to scan...\n")
+	if scanConfig.Diff != "" {
+		return scanner.New(scanConfig).ScanDiff(ctx, repoInfo, scanConfig.Diff)
 	}
+	return scanner.New(scanConfig).Scan(ctx, repoInfo)
+}
 
-	discoveryConfig := discovery.DefaultConfig()
-	fileDiscovery := discovery.NewFileDiscovery(discoveryConfig)
-
-	files, err := fileDiscovery.DiscoverFiles(ctx, repoInfo.LocalPath)
-	if err != nil {
-		result.Error = fmt.Sprintf("File discovery failed: %v", err)
-		return saveResult(result, outputFile)
+// repositoryConfig returns the clone settings for a scan. History and diff
+// scans need the commit graph, so they disable shallow cloning.
+func repositoryConfig(scanConfig scanner.Config) repository.GitHubConfig {
+	repoConfig := repository.DefaultGitHubConfig()
+	repoConfig.Providers.Hosts = scanConfig.ProviderHosts
+	if scanConfig.History != nil || scanConfig.Diff != "" {
+		repoConfig.ShallowClone = false
 	}
+	return repoConfig
+}
 
-	result.Stats.TotalFiles = len(files)
-
-	if verbose {
-		fmt.Printf("✅ Discovered %d files\n", len(files))
+// runPathScan scans a local directory in place, without authentication or cloning
+func runPathScan(ctx context.Context, path, outputFile, format string, scanConfig scanner.Config) error {
+	if scanConfig.Verbose {
+		fmt.Fprintf(scanConfig.Output, "🔍 Starting PI scan of local path: %s\n", path)
 	}
 
-	// Step 5: Set up file processor
-	processorConfig := processing.DefaultProcessorConfig()
-	processorConfig.NumWorkers = 4 // Reasonable for testing
-
-	fileProcessor := processing.NewFileProcessor(processorConfig, detectors)
-
-	// Step 6: Create processing jobs
-	var jobs []processing.FileJob
-	for _, file := range files {
-		if file.IsBinary {
-			result.Stats.SkippedFiles++
-			continue
-		}
-
-		// Read file content
-		content, err := os.ReadFile(file.Path)
-		if err != nil {
-			if verbose {
-				fmt.Printf("⚠️  Could not read file %s: %v\n", file.Path, err)
-			}
-			result.Stats.SkippedFiles++
-			continue
-		}
+	// Public checkouts score higher, so look up the hosting details of the remote
+	scanConfig.RepositoryMetadata = repository.NewRepositoryManager(repositoryConfig(scanConfig)).LookupMetadata
 
-		result.Stats.TotalSize += int64(len(content))
-
-		job := processing.FileJob{
-			FilePath: file.Path,
-			Content:  content,
-			FileInfo: file,
-		}
-
-		jobs = append(jobs, job)
+	result, err := scanner.New(scanConfig).ScanPath(ctx, path)
+	if err != nil {
+		return err
 	}
 
-	result.Stats.ScannedFiles = len(jobs)
-
-	if verbose {
-		fmt.Printf("📋 Prepared %d files for scanning (%d skipped)\n", len(jobs), result.Stats.SkippedFiles)
+	if err := writeResult(scanConfig.Output, result, outputFile, format); err != nil {
+		return err
 	}
+	return checkResult(result)
+}
 
-	// Step 7: Process files
-	if verbose {
-		fmt.Printf("🚀 Starting file processing with %d workers...\n", processorConfig.NumWorkers)
+// writeResult writes the scan result in the requested format: the JSON
+// result file, a SARIF log, or GitHub workflow annotations. An output file of
+// "-" writes to stdout; progress messages go to out.
+func writeResult(out io.Writer, result *ScanResult, outputFile, format string) error {
+	if format == "json" && outputFile != "-" {
+		return saveResult(out, result, outputFile)
 	}
 
-	processingStart := time.Now()
-
-	batchProcessor := processing.NewBatchProcessor(fileProcessor, 50)
-	results, err := batchProcessor.ProcessFiles(ctx, jobs)
+	w, closeOutput, err := openOutput(os.Stdout, outputFile)
 	if err != nil {
-		result.Error = fmt.Sprintf("File processing failed: %v", err)
-		return saveResult(result, outputFile)
+		return err
 	}
-
-	result.Stats.ProcessingTime = time.Since(processingStart)
-
-	if verbose {
-		fmt.Printf("✅ Processing completed in %v\n", result.Stats.ProcessingTime)
+	defer closeOutput()
+
+	switch format {
+	case "sarif":
+		err = writeSARIFReport(w, result, buildIntegrationRecords(result), buildExportMetadata(result))
+	case "github":
+		err = writeGitHubAnnotations(w, result, buildIntegrationRecords(result))
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(result); err != nil {
+			err = fmt.Errorf("failed to marshal results: %w", err)
+		}
 	}
-
-	// Step 8: Collect and analyze findings
-	if verbose {
-		fmt.Printf("📊 Analyzing findings...\n")
+	if err != nil {
+		return err
 	}
 
-	var allFindings []detection.Finding
-	for _, procResult := range results {
-		if procResult.Error != nil {
-			if verbose {
-				fmt.Printf("⚠️  Error processing %s: %v\n", procResult.FilePath, procResult.Error)
-			}
-			continue
-		}
-
-		for _, finding := range procResult.Findings {
-			allFindings = append(allFindings, finding)
-
-			// Update statistics
-			piType := string(finding.Type)
-			result.Stats.FindingsByType[piType]++
-
-			riskLevel := string(finding.RiskLevel)
-			result.Stats.FindingsByRisk[riskLevel]++
-		}
+	if outputFile != "-" {
+		fmt.Fprintf(out, "✅ Results saved to: %s\n", outputFile)
 	}
+	return nil
+}
 
-	result.Findings = allFindings
-	result.FilesScanned = len(results)
-	result.ScanFinished = time.Now()
-	result.Duration = result.ScanFinished.Sub(result.ScanStarted)
-
-	if verbose {
-		fmt.Printf("🎯 Scan Summary:\n")
-		fmt.Printf("   • Duration: %v\n", result.Duration)
-		fmt.Printf("   • Files scanned: %d\n", result.FilesScanned)
-		fmt.Printf("   • Total findings: %d\n", len(allFindings))
-
-		if len(result.Stats.FindingsByType) > 0 {
-			fmt.Printf("   • Findings by type:\n")
-			for piType, count := range result.Stats.FindingsByType {
-				fmt.Printf("     - %s: %d\n", piType, count)
-			}
-		}
-
-		if len(result.Stats.FindingsByRisk) > 0 {
-			fmt.Printf("   • Findings by risk:\n")
-			for risk, count := range result.Stats.FindingsByRisk {
-				fmt.Printf("     - %s: %d\n", risk, count)
-			}
-		}
+// defaultOutputFile returns where a scan writes results in a non-JSON format
+// when --output is not given. Annotations only take effect on stdout.
+func defaultOutputFile(format string) string {
+	if format == "github" {
+		return "-"
 	}
-
-	// Step 9: Save results
-	return saveResult(result, outputFile)
+	return "scan-results." + format
 }
 
-// saveResult saves the scan result to a JSON file
-func saveResult(result *ScanResult, outputFile string) error {
+// saveResult saves the scan result to a JSON file, reporting it to out
+func saveResult(out io.Writer, result *ScanResult, outputFile string) error {
 	// Create output directory if needed
 	dir := filepath.Dir(outputFile)
 	if dir != "." {
@@ -291,6 +229,46 @@ func saveResult(result *ScanResult, outputFile string) error {
 		return fmt.Errorf("failed to write results file: %w", err)
 	}
 
-	fmt.Printf("✅ Results saved to: %s\n", outputFile)
+	fmt.Fprintf(out, "✅ Results saved to: %s\n", outputFile)
+	if b := result.Baseline; b != nil {
+		fmt.Fprintf(out, "📏 Baseline: %d new, %d unchanged, %d absent\n", b.New, b.Unchanged, b.Absent)
+	}
 	return nil
 }
+
+// runStagedScan scans the lines added in the git index and lists the findings,
+// so a pre-commit hook can show why a commit was blocked. The result is only
+// saved when outputFile is set.
+func runStagedScan(ctx context.Context, out io.Writer, path, outputFile, format string, scanConfig scanner.Config) error {
+	fmt.Fprintf(out, "🔍 Scanning staged changes in: %s\n", path)
+
+	result, err := scanner.New(scanConfig).ScanPath(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		if err := writeResult(scanConfig.Output, result, outputFile, format); err != nil {
+			return err
+		}
+	}
+
+	reported := 0
+	for _, f := range result.Findings {
+		if f.Suppressed() {
+			continue
+		}
+		file := f.File
+		if rel, err := filepath.Rel(result.Repository.LocalPath, f.File); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		fmt.Fprintf(out, "⚠️  %s:%d %s %s (%s)\n", file, f.Line, f.Type, report.MaskSensitiveData(f.Match, string(f.Type)), f.PolicyRiskLevel())
+		reported++
+	}
+
+	if reported == 0 && result.Error == "" {
+		fmt.Fprintf(out, "✅ No PI found in staged changes\n")
+	}
+
+	return checkResult(result)
+}
@@ -0,0 +1,164 @@
+package allowlist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the allowlist file name looked up in the scanned directory
+const DefaultFile = ".pi-scanner-allow.yaml"
+
+// hashPrefix marks value hashes in the allowlist file
+const hashPrefix = "sha256:"
+
+// Allowlist is a reviewed list of accepted findings
+type Allowlist struct {
+	Version string  `yaml:"version"`
+	Entries []Entry `yaml:"entries"`
+}
+
+// Entry accepts findings matching every criterion it sets. At least one of
+// ValueHash, Path or Type must be set, and each entry needs a justification
+// and an expiry date so accepted exposures are reviewed periodically.
+type Entry struct {
+	ValueHash     string    `yaml:"value_hash,omitempty" json:"value_hash,omitempty"` // HashValue of the PI value
+	Path          string    `yaml:"path,omitempty" json:"path,omitempty"`             // Glob relative to the repository root
+	Type          string    `yaml:"type,omitempty" json:"type,omitempty"`             // PI type, e.g. ABN
+	Justification string    `yaml:"justification" json:"justification"`
+	Expires       time.Time `yaml:"expires" json:"expires"`
+}
+
+// Load reads and validates an allowlist file
+func Load(path string) (*Allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %w", err)
+	}
+
+	var list Allowlist
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse allowlist: %w", err)
+	}
+
+	if err := list.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid allowlist %s: %w", path, err)
+	}
+
+	return &list, nil
+}
+
+// Validate checks that every entry is complete
+func (a *Allowlist) Validate() error {
+	for i, entry := range a.Entries {
+		if entry.ValueHash == "" && entry.Path == "" && entry.Type == "" {
+			return fmt.Errorf("entry %d: one of value_hash, path or type is required", i+1)
+		}
+		if strings.TrimSpace(entry.Justification) == "" {
+			return fmt.Errorf("entry %d: justification is required", i+1)
+		}
+		if entry.Expires.IsZero() {
+			return fmt.Errorf("entry %d: expires is required", i+1)
+		}
+		if entry.ValueHash != "" && !validHash(entry.ValueHash) {
+			return fmt.Errorf("entry %d: value_hash must be %s followed by 64 hex characters", i+1, hashPrefix)
+		}
+		if entry.Path != "" && !doublestar.ValidatePattern(entry.Path) {
+			return fmt.Errorf("entry %d: invalid path pattern %q", i+1, entry.Path)
+		}
+	}
+	return nil
+}
+
+// Apply marks findings matched by an unexpired entry as suppressed. Findings
+// already suppressed inline are left alone. It returns the entries that have
+// expired so they can be reported for review.
+func (a *Allowlist) Apply(findings []detection.Finding, root string, now time.Time) []Entry {
+	// Entries remain valid until the end of their expiry date
+	var active, expired []Entry
+	for _, entry := range a.Entries {
+		if !now.Before(entry.Expires.AddDate(0, 0, 1)) {
+			expired = append(expired, entry)
+		} else {
+			active = append(active, entry)
+		}
+	}
+
+	for i := range findings {
+		if findings[i].Suppressed() {
+			continue
+		}
+
+		for _, entry := range active {
+			if !entry.matches(findings[i], root) {
+				continue
+			}
+
+			expires := entry.Expires
+			findings[i].Suppression = &detection.Suppression{
+				Source:        detection.SuppressionAllowlist,
+				Justification: entry.Justification,
+				Expires:       &expires,
+			}
+			break
+		}
+	}
+
+	return expired
+}
+
+// matches reports whether the entry covers a finding
+func (e Entry) matches(finding detection.Finding, root string) bool {
+	if e.Type != "" && !strings.EqualFold(e.Type, string(finding.Type)) {
+		return false
+	}
+
+	if e.ValueHash != "" && !strings.EqualFold(e.ValueHash, HashValue(finding.Match)) {
+		return false
+	}
+
+	if e.Path != "" {
+		path := filepath.ToSlash(finding.File)
+		if root != "" {
+			path = strings.TrimPrefix(path, strings.TrimSuffix(filepath.ToSlash(root), "/")+"/")
+		}
+		if matched, err := doublestar.Match(e.Path, path); err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HashValue returns the allowlist hash of a PI value. Whitespace and hyphens
+// are ignored and letters are lower-cased so formatting differences such as
+// "123 456 782" and "123-456-782" hash the same.
+func HashValue(value string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, value)
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// validHash checks the format of a value hash
+func validHash(hash string) bool {
+	if !strings.HasPrefix(hash, hashPrefix) {
+		return false
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(hash, hashPrefix))
+	return err == nil && len(decoded) == sha256.Size
+}
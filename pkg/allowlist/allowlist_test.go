@@ -0,0 +1,102 @@
+package allowlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAllowlist(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeAllowlist(t, `version: "1.0"
+entries:
+  - value_hash: `+HashValue("51 824 753 556")+`
+    type: ABN
+    justification: Our published company ABN
+    expires: 2030-06-30
+  - path: "testdata/**"
+    justification: Synthetic fixtures
+    expires: 2030-01-01
+`)
+
+	list, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	assert.Equal(t, "ABN", list.Entries[0].Type)
+	assert.Equal(t, time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC), list.Entries[0].Expires)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		wantErr string
+	}{
+		{"no criteria", "justification: x\n    expires: 2030-01-01", "one of value_hash, path or type"},
+		{"no justification", "type: TFN\n    expires: 2030-01-01", "justification is required"},
+		{"no expiry", "type: TFN\n    justification: x", "expires is required"},
+		{"bad hash", "value_hash: abc\n    justification: x\n    expires: 2030-01-01", "value_hash must be"},
+		{"bad glob", "path: \"[\"\n    justification: x\n    expires: 2030-01-01", "invalid path pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeAllowlist(t, "entries:\n  - "+tt.entry+"\n")
+			_, err := Load(path)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAllowlist_Apply(t *testing.T) {
+	root := "/tmp/repo"
+	now := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	list := &Allowlist{Entries: []Entry{
+		{ValueHash: HashValue("123456782"), Type: "TFN", Justification: "ATO published test value", Expires: now.AddDate(1, 0, 0)},
+		{Path: "testdata/**", Justification: "Fixtures", Expires: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{Type: "EMAIL", Justification: "Expired review", Expires: now.AddDate(0, 0, -2)},
+	}}
+
+	inline := &detection.Suppression{Source: detection.SuppressionInline, Justification: "inline"}
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123 456 782", File: "/tmp/repo/src/a.go"},
+		{Type: detection.PITypeTFN, Match: "876543210", File: "/tmp/repo/src/a.go"},
+		{Type: detection.PITypePhone, Match: "0412345678", File: "/tmp/repo/testdata/users.csv"},
+		{Type: detection.PITypeEmail, Match: "jane@example.com", File: "/tmp/repo/src/a.go"},
+		{Type: detection.PITypeTFN, Match: "123456782", File: "/tmp/repo/src/b.go", Suppression: inline},
+	}
+
+	expired := list.Apply(findings, root, now)
+
+	require.NotNil(t, findings[0].Suppression, "hash matches regardless of formatting")
+	assert.Equal(t, detection.SuppressionAllowlist, findings[0].Suppression.Source)
+	assert.Equal(t, "ATO published test value", findings[0].Suppression.Justification)
+	require.NotNil(t, findings[0].Suppression.Expires)
+
+	assert.Nil(t, findings[1].Suppression)
+	assert.NotNil(t, findings[2].Suppression, "entry is valid until the end of its expiry date")
+	assert.Nil(t, findings[3].Suppression, "expired entries do not suppress")
+	assert.Same(t, inline, findings[4].Suppression)
+
+	require.Len(t, expired, 1)
+	assert.Equal(t, "Expired review", expired[0].Justification)
+}
+
+func TestHashValue(t *testing.T) {
+	assert.Equal(t, HashValue("123456782"), HashValue("123-456-782"))
+	assert.Equal(t, HashValue("Jane@Example.com"), HashValue("jane@example.com"))
+	assert.NotEqual(t, HashValue("123456782"), HashValue("123456783"))
+	assert.True(t, validHash(HashValue("x")))
+}
@@ -0,0 +1,188 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/report"
+)
+
+// Version is the baseline file format version
+const Version = "1.0"
+
+// Baseline records findings that have been accepted so later scans only
+// report what is new. Entries are keyed on the SARIF fingerprint and never
+// store the raw PI value.
+type Baseline struct {
+	Version    string    `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	Repository string    `json:"repository,omitempty"`
+	Entries    []Entry   `json:"findings"`
+}
+
+// Entry is a single accepted finding
+type Entry struct {
+	Fingerprint string           `json:"fingerprint"`
+	Type        detection.PIType `json:"type"`
+	File        string           `json:"file"` // Relative to the repository root
+	Line        int              `json:"line"`
+	Match       string           `json:"match"` // Masked value
+}
+
+// Summary counts findings by baseline state
+type Summary struct {
+	New       int `json:"new"`
+	Unchanged int `json:"unchanged"`
+	Absent    int `json:"absent"`
+}
+
+// New creates a baseline from the findings of a scan rooted at root
+func New(findings []detection.Finding, root, repository string) *Baseline {
+	b := &Baseline{
+		Version:    Version,
+		CreatedAt:  time.Now().UTC(),
+		Repository: repository,
+		Entries:    make([]Entry, 0, len(findings)),
+	}
+
+	seen := make(map[string]bool)
+	for _, finding := range findings {
+		if finding.BaselineState == detection.BaselineStateAbsent {
+			continue
+		}
+
+		fingerprint := report.Fingerprint(finding, root)
+		if seen[fingerprint] {
+			continue
+		}
+		seen[fingerprint] = true
+
+		b.Entries = append(b.Entries, Entry{
+			Fingerprint: fingerprint,
+			Type:        finding.Type,
+			File:        relativePath(finding.File, root),
+			Line:        finding.Line,
+			Match:       report.MaskSensitiveData(finding.Match, string(finding.Type)),
+		})
+	}
+
+	sort.Slice(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Line != c.Line {
+			return a.Line < c.Line
+		}
+		return a.Fingerprint < c.Fingerprint
+	})
+
+	return b
+}
+
+// Load reads a baseline file
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported baseline version: %q", b.Version)
+	}
+
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON
+func (b *Baseline) Save(path string) error {
+	dir := filepath.Dir(path)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	return nil
+}
+
+// Compare marks each finding as new or unchanged and returns the baseline
+// entries that were not found again as absent findings. The findings slice is
+// updated in place.
+func (b *Baseline) Compare(findings []detection.Finding, root string) ([]detection.Finding, Summary) {
+	entries := make(map[string]bool, len(b.Entries))
+	for _, entry := range b.Entries {
+		entries[entry.Fingerprint] = true
+	}
+
+	var summary Summary
+	matched := make(map[string]bool)
+
+	for i := range findings {
+		fingerprint := report.Fingerprint(findings[i], root)
+		findings[i].Fingerprint = fingerprint
+
+		if entries[fingerprint] {
+			findings[i].BaselineState = detection.BaselineStateUnchanged
+			matched[fingerprint] = true
+			summary.Unchanged++
+		} else {
+			findings[i].BaselineState = detection.BaselineStateNew
+			summary.New++
+		}
+	}
+
+	var absent []detection.Finding
+	for _, entry := range b.Entries {
+		if matched[entry.Fingerprint] {
+			continue
+		}
+		matched[entry.Fingerprint] = true
+
+		file := entry.File
+		if root != "" {
+			file = filepath.Join(root, filepath.FromSlash(entry.File))
+		}
+
+		absent = append(absent, detection.Finding{
+			Type:          entry.Type,
+			Match:         entry.Match,
+			File:          file,
+			Line:          entry.Line,
+			BaselineState: detection.BaselineStateAbsent,
+			Fingerprint:   entry.Fingerprint,
+		})
+		summary.Absent++
+	}
+
+	return absent, summary
+}
+
+// relativePath returns path relative to root using forward slashes
+func relativePath(path, root string) string {
+	path = filepath.ToSlash(path)
+	if root == "" {
+		return path
+	}
+	return strings.TrimPrefix(path, strings.TrimSuffix(filepath.ToSlash(root), "/")+"/")
+}
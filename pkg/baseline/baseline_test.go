@@ -0,0 +1,74 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFindings(root string) []detection.Finding {
+	return []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: filepath.Join(root, "src", "payroll.go"), Line: 12},
+		{Type: detection.PITypeEmail, Match: "jane.citizen@example.com", File: filepath.Join(root, "config.yaml"), Line: 3},
+	}
+}
+
+func TestNew(t *testing.T) {
+	root := "/tmp/pi-scanner-1/repo"
+	findings := testFindings(root)
+	findings = append(findings, findings[0]) // Duplicates collapse to one entry
+
+	b := New(findings, root, "https://github.com/test/repo")
+
+	assert.Equal(t, Version, b.Version)
+	assert.Equal(t, "https://github.com/test/repo", b.Repository)
+	require.Len(t, b.Entries, 2)
+
+	// Sorted by file and the raw value is never stored
+	assert.Equal(t, "config.yaml", b.Entries[0].File)
+	assert.Equal(t, "src/payroll.go", b.Entries[1].File)
+	assert.Equal(t, 12, b.Entries[1].Line)
+	assert.NotEqual(t, "123456782", b.Entries[1].Match)
+	assert.NotEmpty(t, b.Entries[1].Fingerprint)
+}
+
+func TestBaseline_Compare(t *testing.T) {
+	// The baseline was created from a clone in a different directory
+	b := New(testFindings("/tmp/pi-scanner-1/repo"), "/tmp/pi-scanner-1/repo", "")
+
+	root := "/tmp/pi-scanner-2/repo"
+	findings := []detection.Finding{
+		testFindings(root)[0],
+		{Type: detection.PITypeTFN, Match: "876543210", File: filepath.Join(root, "src", "new.go"), Line: 1},
+	}
+
+	absent, summary := b.Compare(findings, root)
+
+	assert.Equal(t, Summary{New: 1, Unchanged: 1, Absent: 1}, summary)
+	assert.Equal(t, detection.BaselineStateUnchanged, findings[0].BaselineState)
+	assert.Equal(t, detection.BaselineStateNew, findings[1].BaselineState)
+	assert.Equal(t, b.Entries[1].Fingerprint, findings[0].Fingerprint)
+
+	require.Len(t, absent, 1)
+	assert.Equal(t, detection.BaselineStateAbsent, absent[0].BaselineState)
+	assert.Equal(t, detection.PITypeEmail, absent[0].Type)
+	assert.Equal(t, filepath.Join(root, "config.yaml"), absent[0].File)
+	assert.Equal(t, b.Entries[0].Fingerprint, absent[0].Fingerprint)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baselines", "baseline.json")
+	b := New(testFindings("/repo"), "/repo", "")
+
+	require.NoError(t, b.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, b.Entries, loaded.Entries)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
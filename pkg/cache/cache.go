@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// formatVersion is bumped whenever the layout of cache entries changes
+const formatVersion = "1"
+
+// Cache stores detector findings by file content so unchanged files are not
+// scanned again, and remembers the last commit scanned in each repository.
+//
+// Entries are keyed on the git blob hash of the content and the file name
+// (detectors skip files by name), and are kept per version: a version is the
+// pi-scanner build together with the ruleset fingerprint passed to Open, so
+// changing either starts a fresh cache. Entries hold raw matches, so the cache
+// directory is only readable by its owner.
+type Cache struct {
+	dir     string
+	version string
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// ScanRecord is the last scan of a repository
+type ScanRecord struct {
+	Commit    string    `json:"commit"`
+	Version   string    `json:"version"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// entry is a cached detector result
+type entry struct {
+	Findings []detection.Finding `json:"findings"`
+}
+
+// DefaultDir returns the pi-scanner directory in the user's cache directory
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "pi-scanner")
+}
+
+// Open opens the cache in dir for a ruleset, creating the directory if
+// needed. Entries written for other versions are removed.
+func Open(dir, ruleset string) (*Cache, error) {
+	sum := sha256.Sum256([]byte(formatVersion + "\x00" + buildVersion() + "\x00" + ruleset))
+	c := &Cache{dir: dir, version: hex.EncodeToString(sum[:8])}
+
+	if err := os.MkdirAll(c.findingsDir(), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "commits"), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Only the current version is ever read, so drop the others
+	if versions, err := os.ReadDir(filepath.Join(dir, "findings")); err == nil {
+		for _, v := range versions {
+			if v.Name() != c.version {
+				os.RemoveAll(filepath.Join(dir, "findings", v.Name()))
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Version identifies the build and ruleset the cache entries belong to
+func (c *Cache) Version() string {
+	return c.version
+}
+
+// BlobHash returns the git blob hash of content, so cache keys match the
+// object IDs git reports for committed files
+func BlobHash(content []byte) string {
+	h := sha1.New()
+	h.Write([]byte("blob " + strconv.Itoa(len(content)) + "\x00"))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached findings for a blob scanned under a file name
+func (c *Cache) Get(blobHash, filename string) ([]detection.Finding, bool) {
+	data, err := os.ReadFile(c.entryPath(blobHash, filename))
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.hits.Add(1)
+	return e.Findings, true
+}
+
+// Put stores the findings for a blob scanned under a file name
+func (c *Cache) Put(blobHash, filename string, findings []detection.Finding) error {
+	data, err := json.Marshal(entry{Findings: findings})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	return writeFile(c.entryPath(blobHash, filename), data)
+}
+
+// Stats returns the number of cache hits and misses since the cache was opened
+func (c *Cache) Stats() (hits, misses int) {
+	return int(c.hits.Load()), int(c.misses.Load())
+}
+
+// LastScan returns the last scan recorded for a repository, if it was made
+// with the current version
+func (c *Cache) LastScan(repo string) (ScanRecord, bool) {
+	data, err := os.ReadFile(c.recordPath(repo))
+	if err != nil {
+		return ScanRecord{}, false
+	}
+
+	var record ScanRecord
+	if err := json.Unmarshal(data, &record); err != nil || record.Version != c.version {
+		return ScanRecord{}, false
+	}
+	return record, true
+}
+
+// RecordScan remembers the commit a repository was scanned at
+func (c *Cache) RecordScan(repo, commit string) error {
+	data, err := json.Marshal(ScanRecord{Commit: commit, Version: c.version, ScannedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan record: %w", err)
+	}
+	return writeFile(c.recordPath(repo), data)
+}
+
+// findingsDir is the directory of the current version's entries
+func (c *Cache) findingsDir() string {
+	return filepath.Join(c.dir, "findings", c.version)
+}
+
+// entryPath spreads entries over subdirectories by their key's first byte
+func (c *Cache) entryPath(blobHash, filename string) string {
+	key := hashKey(blobHash + "\x00" + filename)
+	return filepath.Join(c.findingsDir(), key[:2], key+".json")
+}
+
+// recordPath stores each repository's record separately, so concurrent scans
+// of different repositories do not overwrite each other
+func (c *Cache) recordPath(repo string) string {
+	return filepath.Join(c.dir, "commits", hashKey(repo)+".json")
+}
+
+func hashKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFile writes through a temporary file and a rename, so concurrent
+// readers never see a partial entry
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// buildVersion identifies the running binary: its module version and, when
+// built from a checkout, the VCS revision. Detector code changes between
+// builds therefore invalidate the cache.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	version := info.Main.Version
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.modified":
+			version += " " + setting.Value
+		}
+	}
+	return version
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobHash(t *testing.T) {
+	// Matches `git hash-object` for the same content
+	assert.Equal(t, "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", BlobHash(nil))
+	assert.Equal(t, "ce013625030ba8dba906f756967f9e9ca394464a", BlobHash([]byte("hello\n")))
+}
+
+func TestCache_Findings(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir, "rules-v1")
+	require.NoError(t, err)
+
+	blob := BlobHash([]byte("const tfn = \"123456782\"\n"))
+	findings := []detection.Finding{{Type: detection.PITypeTFN, Match: "123456782", File: "payroll.go", Line: 1, Validated: true}}
+
+	_, ok := c.Get(blob, "payroll.go")
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put(blob, "payroll.go", findings))
+	got, ok := c.Get(blob, "payroll.go")
+	require.True(t, ok)
+	assert.Equal(t, findings, got)
+
+	// Detectors skip files by name, so the name is part of the key
+	_, ok = c.Get(blob, "payroll_test.go")
+	assert.False(t, ok)
+
+	// Files without findings are cached too
+	empty := BlobHash([]byte("package payroll\n"))
+	require.NoError(t, c.Put(empty, "payroll.go", nil))
+	got, ok = c.Get(empty, "payroll.go")
+	assert.True(t, ok)
+	assert.Empty(t, got)
+
+	hits, misses := c.Stats()
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, 2, misses)
+
+	// The same ruleset shares entries
+	reopened, err := Open(dir, "rules-v1")
+	require.NoError(t, err)
+	_, ok = reopened.Get(blob, "payroll.go")
+	assert.True(t, ok)
+
+	// A changed ruleset starts again and removes the old entries
+	changed, err := Open(dir, "rules-v2")
+	require.NoError(t, err)
+	assert.NotEqual(t, c.Version(), changed.Version())
+	_, ok = changed.Get(blob, "payroll.go")
+	assert.False(t, ok)
+	_, err = os.Stat(filepath.Join(dir, "findings", c.Version()))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCache_LastScan(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir, "rules-v1")
+	require.NoError(t, err)
+
+	_, ok := c.LastScan("https://github.com/acme/payroll")
+	assert.False(t, ok)
+
+	require.NoError(t, c.RecordScan("https://github.com/acme/payroll", "0123456789abcdef"))
+	record, ok := c.LastScan("https://github.com/acme/payroll")
+	require.True(t, ok)
+	assert.Equal(t, "0123456789abcdef", record.Commit)
+	assert.False(t, record.ScannedAt.IsZero())
+
+	_, ok = c.LastScan("https://github.com/acme/website")
+	assert.False(t, ok)
+
+	// A scan made with other rules does not count
+	changed, err := Open(dir, "rules-v2")
+	require.NoError(t, err)
+	_, ok = changed.LastScan("https://github.com/acme/payroll")
+	assert.False(t, ok)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,23 +12,32 @@ import (
 
 // Config represents the complete scanner configuration
 type Config struct {
-	Version string        `yaml:"version"`
-	Scanner ScannerConfig `yaml:"scanner"`
-	Risk    RiskConfig    `yaml:"risk"`
-	Report  ReportConfig  `yaml:"report"`
-	Github  GithubConfig  `yaml:"github"`
-	Logging LoggingConfig `yaml:"logging"`
+	Version   string          `yaml:"version"`
+	Scanner   ScannerConfig   `yaml:"scanner"`
+	Risk      RiskConfig      `yaml:"risk"`
+	Report    ReportConfig    `yaml:"report"`
+	Github    GithubConfig    `yaml:"github"`
+	Providers ProvidersConfig `yaml:"providers"`
+	Logging   LoggingConfig   `yaml:"logging"`
 }
 
 // ScannerConfig contains scanner-specific settings
 type ScannerConfig struct {
-	Workers           int             `yaml:"workers"`
-	FileTypes         []string        `yaml:"file_types"`
-	ExcludePaths      []string        `yaml:"exclude_paths"`
-	MaxFileSize       int64           `yaml:"max_file_size"`
-	Timeout           time.Duration   `yaml:"timeout"`
-	Validators        ValidatorConfig `yaml:"validators"`
-	ProximityDistance int             `yaml:"proximity_distance"`
+	Workers            int             `yaml:"workers"`
+	FileTypes          []string        `yaml:"file_types"`
+	ExcludePaths       []string        `yaml:"exclude_paths"`
+	MaxFileSize        int64           `yaml:"max_file_size"`         // Larger files are scanned in overlapping chunks of this size
+	MaxScannedFileSize int64           `yaml:"max_scanned_file_size"` // Larger files are skipped and listed in the scan stats
+	ChunkOverlap       int             `yaml:"chunk_overlap"`         // Bytes each chunk shares with the next
+	MemoryBudget       int64           `yaml:"memory_budget"`         // Bytes of file content held in memory at once
+	Archives           ArchiveConfig   `yaml:"archives"`
+	Documents          DocumentConfig  `yaml:"documents"`
+	Decoding           DecodingConfig  `yaml:"decoding"`
+	Timeout            time.Duration   `yaml:"timeout"`
+	GitleaksConfig     string          `yaml:"gitleaks_config,omitempty"`
+	Rules              []string        `yaml:"rules,omitempty"` // Files of user-defined detection rules
+	Validators         ValidatorConfig `yaml:"validators"`
+	ProximityDistance  int             `yaml:"proximity_distance"`
 }
 
 // ValidatorConfig contains validator settings
@@ -41,6 +51,29 @@ type ValidatorConfig struct {
 	Phone      ValidatorSettings `yaml:"phone"`
 }
 
+// ArchiveConfig controls scanning inside zip, jar, tar and tar.gz archives
+type ArchiveConfig struct {
+	Enabled      bool  `yaml:"enabled"`
+	MaxDepth     int   `yaml:"max_depth"`      // Levels of nested archives expanded
+	MaxEntrySize int64 `yaml:"max_entry_size"` // Larger files inside archives are skipped
+	MaxTotalSize int64 `yaml:"max_total_size"` // Decompressed bytes read from one archive
+	MaxEntries   int   `yaml:"max_entries"`    // Entries read from one archive
+}
+
+// DocumentConfig controls scanning the text of Office documents and PDFs
+type DocumentConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// DecodingConfig controls scanning the text decoded from base64, URL-encoded
+// and hex values
+type DecodingConfig struct {
+	Enabled      bool `yaml:"enabled"`
+	MaxDepth     int  `yaml:"max_depth"`      // Levels of encoding undone, as in base64 inside base64
+	MaxSize      int  `yaml:"max_size"`       // Longer encoded values are not decoded
+	MaxTotalSize int  `yaml:"max_total_size"` // Decoded bytes produced from one file
+}
+
 // ValidatorSettings contains individual validator settings
 type ValidatorSettings struct {
 	Enabled       bool    `yaml:"enabled"`
@@ -106,6 +139,11 @@ type GithubConfig struct {
 	TempDirectory string        `yaml:"temp_directory"`
 }
 
+// ProvidersConfig maps self-hosted git servers onto repository providers
+type ProvidersConfig struct {
+	Hosts map[string]string `yaml:"hosts,omitempty"` // e.g. gitlab.example.com: gitlab
+}
+
 // LoggingConfig contains logging settings
 type LoggingConfig struct {
 	Level      string `yaml:"level"`
@@ -116,18 +154,31 @@ type LoggingConfig struct {
 	MaxAge     int    `yaml:"max_age"`
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML file. Settings missing from the
+// file keep their values from the default configuration.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
+	config := *DefaultConfig()
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// Resolve the Gitleaks rules relative to the config file
+	if config.Scanner.GitleaksConfig != "" && !filepath.IsAbs(config.Scanner.GitleaksConfig) {
+		config.Scanner.GitleaksConfig = filepath.Join(filepath.Dir(path), config.Scanner.GitleaksConfig)
+	}
+
+	// And the rules files
+	for i, rules := range config.Scanner.Rules {
+		if !filepath.IsAbs(rules) {
+			config.Scanner.Rules[i] = filepath.Join(filepath.Dir(path), rules)
+		}
+	}
+
 	// Apply defaults
 	config.applyDefaults()
 
@@ -179,10 +230,46 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max file size cannot be negative")
 	}
 
+	if c.Scanner.MaxScannedFileSize < 0 {
+		return fmt.Errorf("max scanned file size cannot be negative")
+	}
+
+	if c.Scanner.ChunkOverlap < 0 {
+		return fmt.Errorf("chunk overlap cannot be negative")
+	}
+
+	if c.Scanner.MemoryBudget < 0 {
+		return fmt.Errorf("memory budget cannot be negative")
+	}
+
+	if a := c.Scanner.Archives; a.MaxDepth < 0 || a.MaxEntrySize < 0 || a.MaxTotalSize < 0 || a.MaxEntries < 0 {
+		return fmt.Errorf("archive limits cannot be negative")
+	}
+
+	if d := c.Scanner.Decoding; d.MaxDepth < 0 || d.MaxSize < 0 || d.MaxTotalSize < 0 {
+		return fmt.Errorf("decoding limits cannot be negative")
+	}
+
 	if c.Scanner.ProximityDistance < 0 {
 		return fmt.Errorf("proximity distance cannot be negative")
 	}
 
+	if c.Scanner.Timeout < 0 {
+		return fmt.Errorf("scanner timeout cannot be negative")
+	}
+
+	// Validate validator settings
+	for name, settings := range c.Scanner.Validators.byName() {
+		if settings.MinConfidence < 0 || settings.MinConfidence > 1 {
+			return fmt.Errorf("%s validator min_confidence must be between 0 and 1", name)
+		}
+		if settings.CustomPattern != "" {
+			if _, err := regexp.Compile(settings.CustomPattern); err != nil {
+				return fmt.Errorf("%s validator custom_pattern is invalid: %w", name, err)
+			}
+		}
+	}
+
 	// Validate risk thresholds
 	if c.Risk.Thresholds.Critical < c.Risk.Thresholds.High ||
 		c.Risk.Thresholds.High < c.Risk.Thresholds.Medium ||
@@ -205,6 +292,14 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate provider hosts
+	validProviders := map[string]bool{"github": true, "gitlab": true, "bitbucket-server": true, "azure-devops": true}
+	for host, provider := range c.Providers.Hosts {
+		if !validProviders[provider] {
+			return fmt.Errorf("invalid provider for host %s: %s (expected github, gitlab, bitbucket-server or azure-devops)", host, provider)
+		}
+	}
+
 	// Validate logging level
 	validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
 	if !validLevels[c.Logging.Level] {
@@ -214,6 +309,19 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// byName returns the validator settings keyed by their YAML names
+func (v ValidatorConfig) byName() map[string]ValidatorSettings {
+	return map[string]ValidatorSettings{
+		"tfn":         v.TFN,
+		"medicare":    v.Medicare,
+		"abn":         v.ABN,
+		"bsb":         v.BSB,
+		"credit_card": v.CreditCard,
+		"email":       v.Email,
+		"phone":       v.Phone,
+	}
+}
+
 // applyDefaults applies default values to missing configuration
 func (c *Config) applyDefaults() {
 	if c.Version == "" {
@@ -230,6 +338,36 @@ func (c *Config) applyDefaults() {
 	if c.Scanner.MaxFileSize == 0 {
 		c.Scanner.MaxFileSize = 10 * 1024 * 1024 // 10MB
 	}
+	if c.Scanner.MaxScannedFileSize == 0 {
+		c.Scanner.MaxScannedFileSize = 1024 * 1024 * 1024 // 1GB
+	}
+	if c.Scanner.ChunkOverlap == 0 {
+		c.Scanner.ChunkOverlap = 4 * 1024 // 4KB
+	}
+	if c.Scanner.MemoryBudget == 0 {
+		c.Scanner.MemoryBudget = 256 * 1024 * 1024 // 256MB
+	}
+	if c.Scanner.Archives.MaxDepth == 0 {
+		c.Scanner.Archives.MaxDepth = 3
+	}
+	if c.Scanner.Archives.MaxEntrySize == 0 {
+		c.Scanner.Archives.MaxEntrySize = 10 * 1024 * 1024 // 10MB
+	}
+	if c.Scanner.Archives.MaxTotalSize == 0 {
+		c.Scanner.Archives.MaxTotalSize = 100 * 1024 * 1024 // 100MB
+	}
+	if c.Scanner.Archives.MaxEntries == 0 {
+		c.Scanner.Archives.MaxEntries = 10000
+	}
+	if c.Scanner.Decoding.MaxDepth == 0 {
+		c.Scanner.Decoding.MaxDepth = 3
+	}
+	if c.Scanner.Decoding.MaxSize == 0 {
+		c.Scanner.Decoding.MaxSize = 1024 * 1024 // 1MB
+	}
+	if c.Scanner.Decoding.MaxTotalSize == 0 {
+		c.Scanner.Decoding.MaxTotalSize = 10 * 1024 * 1024 // 10MB
+	}
 	if c.Scanner.Timeout == 0 {
 		c.Scanner.Timeout = 30 * time.Minute
 	}
@@ -333,7 +471,7 @@ func DefaultFileTypes() []string {
 		".go", ".py", ".js", ".ts", ".java", ".cs", ".rb", ".php",
 		".cpp", ".c", ".h", ".hpp", ".swift", ".kt", ".scala",
 		".json", ".yaml", ".yml", ".xml", ".properties", ".conf",
-		".env", ".config", ".ini", ".toml", ".txt", ".csv",
+		".env", ".config", ".ini", ".toml", ".txt", ".csv", ".tsv",
 		".sql", ".sh", ".bash", ".zsh", ".ps1", ".bat", ".cmd",
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,6 +46,39 @@ risk:
 	assert.Equal(t, 0.7, config.Risk.Thresholds.High)
 }
 
+func TestLoadConfig_PartialFileKeepsDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "partial.yaml")
+
+	testConfig := `
+scanner:
+  timeout: 5m
+  gitleaks_config: rules/gitleaks.toml
+  rules: [rules/members.yaml, /etc/pi-scanner/rules.yaml]
+  validators:
+    email:
+      enabled: false
+`
+
+	require.NoError(t, os.WriteFile(configPath, []byte(testConfig), 0644))
+
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+
+	// Explicit settings are applied
+	assert.Equal(t, 5*time.Minute, config.Scanner.Timeout)
+	assert.False(t, config.Scanner.Validators.Email.Enabled)
+	assert.Equal(t, filepath.Join(tmpDir, "rules", "gitleaks.toml"), config.Scanner.GitleaksConfig)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "rules", "members.yaml"), "/etc/pi-scanner/rules.yaml"}, config.Scanner.Rules)
+
+	// Omitted settings keep their defaults
+	assert.True(t, config.Scanner.Validators.TFN.Enabled)
+	assert.True(t, config.Scanner.Validators.TFN.StrictMode)
+	assert.Equal(t, 4, config.Scanner.Workers)
+	assert.Contains(t, config.Scanner.ExcludePaths, "node_modules")
+	assert.Equal(t, DefaultFileTypes(), config.Scanner.FileTypes)
+}
+
 func TestLoadConfig_InvalidFile(t *testing.T) {
 	_, err := LoadConfig("/non/existent/file.yaml")
 	assert.Error(t, err)
@@ -152,6 +186,41 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: "max file size cannot be negative",
 		},
+		{
+			name: "negative max scanned file size",
+			modifyFunc: func(c *Config) {
+				c.Scanner.MaxScannedFileSize = -1
+			},
+			expectedErr: "max scanned file size cannot be negative",
+		},
+		{
+			name: "negative chunk overlap",
+			modifyFunc: func(c *Config) {
+				c.Scanner.ChunkOverlap = -1
+			},
+			expectedErr: "chunk overlap cannot be negative",
+		},
+		{
+			name: "negative memory budget",
+			modifyFunc: func(c *Config) {
+				c.Scanner.MemoryBudget = -1
+			},
+			expectedErr: "memory budget cannot be negative",
+		},
+		{
+			name: "negative archive limit",
+			modifyFunc: func(c *Config) {
+				c.Scanner.Archives.MaxTotalSize = -1
+			},
+			expectedErr: "archive limits cannot be negative",
+		},
+		{
+			name: "negative decoding limit",
+			modifyFunc: func(c *Config) {
+				c.Scanner.Decoding.MaxDepth = -1
+			},
+			expectedErr: "decoding limits cannot be negative",
+		},
 		{
 			name: "invalid risk thresholds order",
 			modifyFunc: func(c *Config) {
@@ -174,6 +243,27 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: "invalid report format: invalid_format",
 		},
+		{
+			name: "invalid validator min confidence",
+			modifyFunc: func(c *Config) {
+				c.Scanner.Validators.TFN.MinConfidence = 1.5
+			},
+			expectedErr: "tfn validator min_confidence must be between 0 and 1",
+		},
+		{
+			name: "invalid custom pattern",
+			modifyFunc: func(c *Config) {
+				c.Scanner.Validators.Email.CustomPattern = "[a-z"
+			},
+			expectedErr: "email validator custom_pattern is invalid",
+		},
+		{
+			name: "invalid provider host",
+			modifyFunc: func(c *Config) {
+				c.Providers.Hosts = map[string]string{"git.example.com": "gitea"}
+			},
+			expectedErr: "invalid provider for host git.example.com: gitea",
+		},
 		{
 			name: "invalid logging level",
 			modifyFunc: func(c *Config) {
@@ -208,6 +298,13 @@ func TestConfig_applyDefaults(t *testing.T) {
 	assert.Equal(t, 4, config.Scanner.Workers)
 	assert.NotEmpty(t, config.Scanner.FileTypes)
 	assert.Equal(t, int64(10*1024*1024), config.Scanner.MaxFileSize)
+	assert.Equal(t, int64(1024*1024*1024), config.Scanner.MaxScannedFileSize)
+	assert.Equal(t, 4*1024, config.Scanner.ChunkOverlap)
+	assert.Equal(t, int64(256*1024*1024), config.Scanner.MemoryBudget)
+	assert.Equal(t, 3, config.Scanner.Archives.MaxDepth)
+	assert.Equal(t, int64(100*1024*1024), config.Scanner.Archives.MaxTotalSize)
+	assert.Equal(t, 3, config.Scanner.Decoding.MaxDepth)
+	assert.Equal(t, 10*1024*1024, config.Scanner.Decoding.MaxTotalSize)
 	assert.Equal(t, 10, config.Scanner.ProximityDistance)
 
 	// ML validation removed
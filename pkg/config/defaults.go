@@ -27,11 +27,30 @@ func hardcodedDefaults() *Config {
 	return &Config{
 		Version: "1.0",
 		Scanner: ScannerConfig{
-			Workers:           4,
-			FileTypes:         DefaultFileTypes(),
-			ExcludePaths:      defaultExcludePaths(),
-			MaxFileSize:       10 * 1024 * 1024, // 10MB
-			ProximityDistance: 10,
+			Workers:            4,
+			FileTypes:          DefaultFileTypes(),
+			ExcludePaths:       defaultExcludePaths(),
+			MaxFileSize:        10 * 1024 * 1024,   // 10MB
+			MaxScannedFileSize: 1024 * 1024 * 1024, // 1GB
+			ChunkOverlap:       4 * 1024,           // 4KB
+			MemoryBudget:       256 * 1024 * 1024,  // 256MB
+			ProximityDistance:  10,
+			Archives: ArchiveConfig{
+				Enabled:      true,
+				MaxDepth:     3,
+				MaxEntrySize: 10 * 1024 * 1024,  // 10MB
+				MaxTotalSize: 100 * 1024 * 1024, // 100MB
+				MaxEntries:   10000,
+			},
+			Documents: DocumentConfig{
+				Enabled: true,
+			},
+			Decoding: DecodingConfig{
+				Enabled:      true,
+				MaxDepth:     3,
+				MaxSize:      1024 * 1024,      // 1MB
+				MaxTotalSize: 10 * 1024 * 1024, // 10MB
+			},
 			Validators: ValidatorConfig{
 				TFN: ValidatorSettings{
 					Enabled:       true,
@@ -84,5 +84,5 @@ func ExampleMergeConfig() {
 
 	// Output:
 	// Workers: 8
-	// File Types Count: 34
+	// File Types Count: 35
 }
@@ -28,6 +28,9 @@ type ValidationResult struct {
 	InComment  bool    `json:"in_comment"`
 	InString   bool    `json:"in_string"`
 	HasContext bool    `json:"has_context"`
+
+	// Suppression is set when an inline pi-scanner:ignore comment covers the finding
+	Suppression *detection.Suppression `json:"suppression,omitempty"`
 }
 
 // NewContextValidator creates a new context validator
@@ -47,6 +50,9 @@ func (cv *ContextValidator) Validate(ctx context.Context, finding detection.Find
 		Reason:     "Pattern match",
 	}
 
+	// Inline suppression comments are honoured before any other checks
+	result.Suppression = cv.syntaxAnalyzer.FindSuppression(fileContent, finding)
+
 	// Check if it's in test/mock data (be more permissive for now)
 	result.IsTestData = cv.isTestData(finding, fileContent)
 	result.IsMockData = cv.isMockData(finding, fileContent)
@@ -289,6 +295,30 @@ func NewProximityAnalyzer() *ProximityAnalyzer {
 			"medicare", "health", "medical", "doctor", "hospital", "clinic",
 			"patient", "healthcare", "medicine", "treatment",
 		},
+		detection.PITypeIHI: {
+			"ihi", "healthcare identifier", "patient", "my health record",
+			"hospital", "clinic", "medical", "health",
+		},
+		detection.PITypeHPII: {
+			"hpi-i", "hpii", "practitioner", "provider", "prescriber",
+			"doctor", "clinician", "ahpra",
+		},
+		detection.PITypeHPIO: {
+			"hpi-o", "hpio", "organisation", "facility", "provider",
+			"hospital", "clinic", "pharmacy",
+		},
+		detection.PITypeCRN: {
+			"crn", "centrelink", "customer reference", "welfare", "payment",
+			"pension", "benefit", "concession",
+		},
+		detection.PITypeDVA: {
+			"dva", "veteran", "gold card", "white card", "service",
+			"pension", "widow", "file number",
+		},
+		detection.PITypeImmiCard: {
+			"immicard", "immi card", "visa", "vevo", "immigration",
+			"home affairs", "refugee", "arrival",
+		},
 		detection.PITypeEmail: {
 			"email", "mail", "contact", "address", "send", "from", "to",
 			"reply", "message", "communication", "notify",
@@ -401,6 +431,68 @@ func (sca *SyntaxContextAnalyzer) isInComment(line string, column int) bool {
 	return false
 }
 
+// suppressionMarker starts an inline suppression comment, e.g.
+// "// pi-scanner:ignore TFN reason=ATO published test value"
+const suppressionMarker = "pi-scanner:ignore"
+
+// FindSuppression returns the inline suppression covering a finding, if any.
+// The marker must be inside a comment on the finding's line or the line
+// directly above it. It may list the PI types it applies to; without a list
+// it covers every type.
+func (sca *SyntaxContextAnalyzer) FindSuppression(content string, finding detection.Finding) *detection.Suppression {
+	lines := strings.Split(content, "\n")
+	if finding.Line <= 0 || finding.Line > len(lines) {
+		return nil
+	}
+
+	candidates := []string{lines[finding.Line-1]}
+	if finding.Line > 1 {
+		candidates = append(candidates, lines[finding.Line-2])
+	}
+
+	for _, line := range candidates {
+		idx := strings.Index(line, suppressionMarker)
+		if idx == -1 || !sca.isInComment(line, idx+1) {
+			continue
+		}
+
+		types, reason := parseSuppression(line[idx+len(suppressionMarker):])
+		if len(types) > 0 && !types[strings.ToUpper(string(finding.Type))] {
+			continue
+		}
+
+		return &detection.Suppression{
+			Source:        detection.SuppressionInline,
+			Justification: reason,
+		}
+	}
+
+	return nil
+}
+
+// parseSuppression splits the text after the marker into PI types and a reason
+func parseSuppression(text string) (map[string]bool, string) {
+	// Drop block comment terminators
+	for _, end := range []string{"*/", "-->"} {
+		if idx := strings.Index(text, end); idx != -1 {
+			text = text[:idx]
+		}
+	}
+
+	reason := ""
+	if idx := strings.Index(text, "reason="); idx != -1 {
+		reason = strings.Trim(strings.TrimSpace(text[idx+len("reason="):]), `"'`)
+		text = text[:idx]
+	}
+
+	types := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		types[strings.ToUpper(field)] = true
+	}
+
+	return types, reason
+}
+
 // isInString checks if position is within a string literal
 func (sca *SyntaxContextAnalyzer) isInString(line string, column int) bool {
 	// Simple string detection - count quotes before position
@@ -0,0 +1,191 @@
+package decode
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Encoding is a reversible text encoding that can hide PI from the detectors
+type Encoding string
+
+const (
+	EncodingBase64 Encoding = "base64"
+	EncodingURL    Encoding = "url"
+	EncodingHex    Encoding = "hex"
+)
+
+// minEncodedLength is the shortest base64 text decoded, padding aside:
+// enough for a nine digit TFN, and long enough that ordinary words are rarely
+// tried. alphabetRun holds runs to the same length.
+const minEncodedLength = 12
+
+var (
+	// Runs of the standard and URL-safe base64 alphabets, which hex digits
+	// are part of
+	alphabetRun = regexp.MustCompile(`[A-Za-z0-9+/_-]{12,}={0,2}`)
+	// Runs of text holding at least one percent-encoded byte, up to the
+	// separators of a query string
+	percentRun = regexp.MustCompile(`[^\s"'<>%?&=]*(?:%[0-9A-Fa-f]{2}[^\s"'<>%?&=]*)+`)
+	hexRun     = regexp.MustCompile(`^(?:[0-9A-Fa-f]{2})+$`)
+)
+
+// Limits bounds decoding, so text encoded many times over or files full of
+// encoded text cannot exhaust memory or time
+type Limits struct {
+	MaxDepth     int // Levels of encoding undone; 1 decodes no text found inside decoded text
+	MaxSize      int // Longest encoded text decoded; longer runs are skipped
+	MaxTotalSize int // Decoded bytes produced from one file, all levels included
+}
+
+// DefaultLimits returns the default decoding limits
+func DefaultLimits() Limits {
+	return Limits{
+		MaxDepth:     3,
+		MaxSize:      1024 * 1024,      // 1MB
+		MaxTotalSize: 10 * 1024 * 1024, // 10MB
+	}
+}
+
+// Payload is text found by decoding part of a file
+type Payload struct {
+	Start, End int        // Span of the outermost encoded text in the content
+	Chain      []Encoding // Encodings undone, outermost first
+	Text       []byte     // Decoded text
+}
+
+// Find decodes the base64, URL-encoded and hex text in content, and the
+// encoded text inside what it decodes, down to limits.MaxDepth levels. Only
+// text that decodes to printable UTF-8 is returned, which tells encoded
+// values apart from encrypted or binary ones.
+func Find(content []byte, limits Limits) []Payload {
+	if limits.MaxDepth <= 0 {
+		return nil
+	}
+	f := &finder{limits: limits, remaining: limits.MaxTotalSize}
+	f.find(content, -1, -1, nil)
+	return f.payloads
+}
+
+// finder collects payloads, tracking the decoded bytes still allowed
+type finder struct {
+	limits    Limits
+	remaining int
+	payloads  []Payload
+}
+
+// find decodes the encoded runs of text. Nested runs are reported at the
+// span of the outermost run, given as start and end; -1 means text is the
+// content itself.
+func (f *finder) find(text []byte, start, end int, chain []Encoding) {
+	for _, run := range candidates(text) {
+		if run.end-run.start > f.limits.MaxSize {
+			continue
+		}
+		decoded, ok := decodeRun(text[run.start:run.end], run.encoding)
+		if !ok || !printable(decoded) {
+			continue
+		}
+		if len(decoded) > f.remaining {
+			return
+		}
+		f.remaining -= len(decoded)
+
+		payload := Payload{
+			Start: start,
+			End:   end,
+			Chain: append(chain[:len(chain):len(chain)], run.encoding),
+			Text:  decoded,
+		}
+		if start < 0 {
+			payload.Start, payload.End = run.start, run.end
+		}
+		f.payloads = append(f.payloads, payload)
+
+		if len(payload.Chain) < f.limits.MaxDepth {
+			f.find(decoded, payload.Start, payload.End, payload.Chain)
+		}
+	}
+}
+
+// run is a span of text that may be encoded
+type run struct {
+	start, end int
+	encoding   Encoding
+}
+
+// candidates returns the runs of text that may be base64, hex or URL-encoded
+func candidates(text []byte) []run {
+	var runs []run
+	for _, loc := range alphabetRun.FindAllIndex(text, -1) {
+		r := run{start: loc[0], end: loc[1], encoding: EncodingBase64}
+		switch data := text[r.start:r.end]; {
+		case hexRun.Match(data):
+			r.encoding = EncodingHex
+		case len(data) > 2 && data[0] == '0' && (data[1] == 'x' || data[1] == 'X') && hexRun.Match(data[2:]):
+			r.start += 2
+			r.encoding = EncodingHex
+		}
+		runs = append(runs, r)
+	}
+	for _, loc := range percentRun.FindAllIndex(text, -1) {
+		runs = append(runs, run{start: loc[0], end: loc[1], encoding: EncodingURL})
+	}
+	return runs
+}
+
+// decodeRun decodes a run of text, reporting whether it was validly encoded
+func decodeRun(data []byte, encoding Encoding) ([]byte, bool) {
+	switch encoding {
+	case EncodingHex:
+		decoded := make([]byte, hex.DecodedLen(len(data)))
+		if _, err := hex.Decode(decoded, data); err != nil {
+			return nil, false
+		}
+		return decoded, true
+
+	case EncodingBase64:
+		s := strings.TrimRight(string(data), "=")
+		if len(s) < minEncodedLength {
+			return nil, false
+		}
+		encoding := base64.RawStdEncoding
+		if strings.ContainsAny(s, "-_") {
+			encoding = base64.RawURLEncoding
+		}
+		decoded, err := encoding.DecodeString(s)
+		if err != nil {
+			return nil, false
+		}
+		return decoded, true
+
+	case EncodingURL:
+		decoded, err := url.QueryUnescape(string(data))
+		if err != nil || decoded == string(data) {
+			return nil, false
+		}
+		return []byte(decoded), true
+	}
+	return nil, false
+}
+
+// printable reports whether decoded bytes are text: valid UTF-8 with almost
+// no control characters, as encrypted and binary data never is
+func printable(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+
+	runes, control := 0, 0
+	for _, r := range string(data) {
+		runes++
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			control++
+		}
+	}
+	return control*20 <= runes
+}
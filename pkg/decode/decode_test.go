@@ -0,0 +1,105 @@
+package decode
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainOf returns the encoding chain of the first payload holding value
+func chainOf(payloads []Payload, value string) []Encoding {
+	for _, p := range payloads {
+		if strings.Contains(string(p.Text), value) {
+			return p.Chain
+		}
+	}
+	return nil
+}
+
+func TestFind_Encodings(t *testing.T) {
+	value := `{"tfn":"123456782","email":"jane@example.com"}`
+	b64 := base64.StdEncoding.EncodeToString([]byte(value))
+	b64url := base64.RawURLEncoding.EncodeToString([]byte(value))
+	hexed := hex.EncodeToString([]byte(value))
+	escaped := url.QueryEscape(value)
+
+	tests := map[string]struct {
+		content string
+		chain   []Encoding
+	}{
+		"kubernetes secret": {"data:\n  customer: " + b64 + "\n", []Encoding{EncodingBase64}},
+		"jwt payload":       {"eyJhbGciOiJIUzI1NiJ9." + b64url + ".c2lnbmF0dXJl", []Encoding{EncodingBase64}},
+		"hex":               {"payload = 0x" + hexed, []Encoding{EncodingHex}},
+		"query string":      {"https://api.example.com/hook?data=" + escaped + "&v=2", []Encoding{EncodingURL}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			payloads := Find([]byte(tc.content), DefaultLimits())
+			assert.Equal(t, tc.chain, chainOf(payloads, value))
+		})
+	}
+}
+
+func TestFind_Spans(t *testing.T) {
+	b64 := base64.StdEncoding.EncodeToString([]byte("tfn=123456782"))
+	content := "TOKEN=" + b64 + "\n"
+
+	payloads := Find([]byte(content), DefaultLimits())
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "tfn=123456782", string(payloads[0].Text))
+	assert.Equal(t, b64, content[payloads[0].Start:payloads[0].End])
+}
+
+func TestFind_Nested(t *testing.T) {
+	inner := base64.StdEncoding.EncodeToString([]byte(`{"medicare":"2123456701"}`))
+	outer := url.QueryEscape(`{"blob":"` + inner + `"}`)
+	content := "callback?state=" + outer
+
+	payloads := Find([]byte(content), DefaultLimits())
+	assert.Equal(t, []Encoding{EncodingURL, EncodingBase64}, chainOf(payloads, "2123456701"))
+
+	// Nested payloads keep the span of the outermost encoded text
+	for _, p := range payloads {
+		assert.Equal(t, outer, content[p.Start:p.End])
+	}
+
+	// One level of decoding leaves the inner base64 encoded
+	limits := DefaultLimits()
+	limits.MaxDepth = 1
+	assert.Nil(t, chainOf(Find([]byte(content), limits), "2123456701"))
+}
+
+func TestFind_Limits(t *testing.T) {
+	b64 := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("tfn 123456782 ", 10)))
+
+	limits := DefaultLimits()
+	limits.MaxSize = len(b64) - 1
+	assert.Empty(t, Find([]byte(b64), limits))
+
+	limits = DefaultLimits()
+	limits.MaxTotalSize = 10
+	assert.Empty(t, Find([]byte(b64), limits))
+
+	assert.Empty(t, Find([]byte(b64), Limits{}))
+}
+
+func TestFind_IgnoresBinaryAndPlainText(t *testing.T) {
+	content := strings.Join([]string{
+		// Encrypted or random bytes are not text once decoded
+		base64.StdEncoding.EncodeToString([]byte{0x8f, 0x01, 0xc3, 0x28, 0x00, 0x9a, 0xff, 0x10, 0x02, 0x7e, 0x81, 0x00}),
+		// A git commit hash is hex, but not of text
+		"9fceb02d0ae598e95dc970b74767f19372d61af8",
+		// Identifiers and paths are not valid encodings of text
+		"internationalization",
+		"src/components/UserProfile",
+		"0412345678901234",
+	}, "\n")
+
+	assert.Empty(t, Find([]byte(content), DefaultLimits()))
+}
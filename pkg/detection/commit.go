@@ -0,0 +1,37 @@
+package detection
+
+import (
+	"context"
+	"time"
+)
+
+// CommitInfo identifies the commit that introduced a finding during history scans
+type CommitInfo struct {
+	SHA           string    `json:"sha"`
+	Author        string    `json:"author"`
+	AuthorEmail   string    `json:"author_email,omitempty"`
+	Date          time.Time `json:"date"`
+	Message       string    `json:"message,omitempty"`
+	PresentAtHead bool      `json:"present_at_head"` // Whether the value still exists in the file at HEAD
+}
+
+type commitContextKey struct{}
+
+// WithCommit returns a context carrying the commit whose changes are being scanned.
+// Detectors attach it to every finding they produce.
+func WithCommit(ctx context.Context, commit *CommitInfo) context.Context {
+	if commit == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, commitContextKey{}, commit)
+}
+
+// CommitFromContext returns a copy of the commit carried by the context, if any
+func CommitFromContext(ctx context.Context) *CommitInfo {
+	commit, ok := ctx.Value(commitContextKey{}).(*CommitInfo)
+	if !ok || commit == nil {
+		return nil
+	}
+	c := *commit
+	return &c
+}
@@ -7,18 +7,26 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/MacAttak/pi-scanner/pkg/validation"
 )
 
+var (
+	// Used by the matcher validators and extractors for every match, so
+	// compiled once
+	separatorPattern         = regexp.MustCompile(`[\s\-]`)
+	medicareSeparatorPattern = regexp.MustCompile(`[\s\-/]`)
+	nonDigitPattern          = regexp.MustCompile(`[^\d]`)
+	digitsPattern            = regexp.MustCompile(`^\d+$`)
+	acnNumberPattern         = regexp.MustCompile(`\d{3}[\s]?\d{3}[\s]?\d{3}`)
+)
+
 // detector implements the Detector interface
 type detector struct {
 	config     *Config
 	matchers   []PatternMatcher
-	mu         sync.RWMutex
-	compiled   map[string]*regexp.Regexp
+	engine     *engine
 	validators *validation.ValidatorRegistry
 }
 
@@ -32,12 +40,19 @@ func NewDetectorWithConfig(config *Config) Detector {
 	d := &detector{
 		config:     config,
 		matchers:   []PatternMatcher{},
-		compiled:   make(map[string]*regexp.Regexp),
 		validators: validation.NewValidatorRegistry(),
 	}
 
 	// Initialize pattern matchers
 	d.initializeMatchers()
+	d.applyTypeSettings()
+	d.addRules()
+	for _, matcher := range d.matchers {
+		if rm, ok := matcher.(*regexMatcher); ok {
+			rm.compile()
+		}
+	}
+	d.engine = newEngine(d.matchers)
 
 	return d
 }
@@ -68,42 +83,38 @@ func (d *detector) Detect(ctx context.Context, content []byte, filename string)
 
 	findings := []Finding{}
 	contentStr := string(content)
+	matches := d.engine.match(content)
+	fileModifier := d.getContextModifier(filename)
 
-	// Track which positions have been matched to avoid overlaps
-	type matchRange struct {
-		start, end int
-		piType     PIType
-	}
-	var matchedRanges []matchRange
+	// Bytes matched so far; earlier matchers take priority over overlapping
+	// matches that start or end inside them
+	var matched []bool
+	var lines *lineIndex
 
 	// Apply each matcher
-	for _, matcher := range d.matchers {
-		matches := matcher.Match(content)
-
-		for _, match := range matches {
-			// Check if this match overlaps with an existing match
-			overlaps := false
-			for _, existing := range matchedRanges {
-				if (match.StartIndex >= existing.start && match.StartIndex < existing.end) ||
-					(match.EndIndex > existing.start && match.EndIndex <= existing.end) {
-					overlaps = true
-					break
-				}
+	for i, matcher := range d.matchers {
+		rule := matcherRule(matcher)
+		contextModifier := fileModifier
+		if rule != nil {
+			if modifier, ok := rule.contextModifier(filename); ok {
+				contextModifier = modifier
 			}
+		}
 
-			if overlaps {
+		for _, match := range matches[i] {
+			if matched == nil {
+				matched = make([]bool, len(content))
+				lines = newLineIndex(contentStr)
+			}
+			if matched[match.StartIndex] || matched[match.EndIndex-1] {
 				continue
 			}
-
-			// Add to matched ranges
-			matchedRanges = append(matchedRanges, matchRange{
-				start:  match.StartIndex,
-				end:    match.EndIndex,
-				piType: matcher.Type(),
-			})
+			for j := match.StartIndex; j < match.EndIndex; j++ {
+				matched[j] = true
+			}
 
 			// Calculate line and column
-			line, column := d.getPosition(contentStr, match.StartIndex)
+			line, column := lines.position(match.StartIndex)
 
 			// Extract context
 			contextBefore, contextAfter := d.extractContext(contentStr, match.StartIndex, match.EndIndex)
@@ -120,12 +131,14 @@ func (d *detector) Detect(ctx context.Context, content []byte, filename string)
 				DetectedAt:      time.Now(),
 				DetectorName:    d.Name(),
 				Confidence:      0.8, // Base confidence for pattern match
-				ContextModifier: d.getContextModifier(filename),
+				ContextModifier: contextModifier,
+				Commit:          CommitFromContext(ctx),
 			}
 
 			// Validate if enabled and validator exists
+			checksumFailed := false
 			if d.config.ValidateChecksums {
-				if validator, ok := d.validators.Get(string(finding.Type)); ok {
+				if validator, ok := d.validatorFor(matcher); ok {
 					valid, err := validator.Validate(finding.Match)
 					finding.Validated = valid
 					if err != nil {
@@ -138,6 +151,7 @@ func (d *detector) Detect(ctx context.Context, content []byte, filename string)
 					} else {
 						// Decrease confidence if validation fails
 						finding.Confidence = 0.5
+						checksumFailed = true
 						if err == nil {
 							finding.ValidationError = "Checksum validation failed"
 						}
@@ -145,11 +159,21 @@ func (d *detector) Detect(ctx context.Context, content []byte, filename string)
 				}
 			}
 
+			// Strict mode only reports values that pass checksum validation
+			if checksumFailed {
+				if settings, ok := d.config.TypeSettings[finding.Type]; ok && settings.StrictMode {
+					continue
+				}
+			}
+
 			// Set initial risk level based on type
 			finding.RiskLevel = d.calculateRiskLevel(finding.Type)
+			if rule != nil && rule.RiskLevel != "" {
+				finding.RiskLevel = rule.RiskLevel
+			}
 
 			// Apply context validation and confidence-based filtering
-			if d.shouldIncludeFinding(ctx, finding, contentStr) {
+			if d.shouldIncludeFinding(ctx, finding, lines) {
 				findings = append(findings, finding)
 			}
 		}
@@ -168,11 +192,52 @@ func (d *detector) Detect(ctx context.Context, content []byte, filename string)
 
 // initializeMatchers sets up all pattern matchers
 func (d *detector) initializeMatchers() {
+	// Healthcare identifiers - 16 digits starting 800360 (IHI), 800361 (HPI-I)
+	// or 800362 (HPI-O), often written in groups of four. Checked first, as
+	// their digit groups also fit the shorter number patterns.
+	for _, hi := range []struct {
+		digit  string
+		piType PIType
+	}{
+		{"0", PITypeIHI},
+		{"1", PITypeHPII},
+		{"2", PITypeHPIO},
+	} {
+		d.matchers = append(d.matchers, &regexMatcher{
+			pattern: `\b8003[\s\-]?6` + hi.digit + `\d{2}[\s\-]?\d{4}[\s\-]?\d{4}\b`,
+			piType:  hi.piType,
+		})
+	}
+
+	// Centrelink CRN - 9 digits and a check letter (before TFN, whose digits
+	// it shares)
+	d.matchers = append(d.matchers, &regexMatcher{
+		pattern: `\b\d{3}[\s\-]?\d{3}[\s\-]?\d{3}[A-Z]\b`,
+		piType:  PITypeCRN,
+	})
+
+	// DVA file number - state letter, war code, up to 6 digits and a segment
+	// letter. Short enough to resemble many codes, so a label must be near.
+	d.matchers = append(d.matchers, &regexMatcher{
+		pattern: `\b[NVQSWT](?:[A-Z]{1,3}\s?)?\d{1,6}[A-Z]?\b`,
+		piType:  PITypeDVA,
+		validator: func(match string) bool {
+			return len(separatorPattern.ReplaceAllString(match, "")) <= 9
+		},
+		near: keywordsNear([]string{"dva", "veteran", "gold card", "white card", "file no", "file number"}, DefaultKeywordDistance),
+	})
+
+	// ImmiCard number - 3 letters and 6 digits, with a label near
+	d.matchers = append(d.matchers, &regexMatcher{
+		pattern: `\b[A-Z]{3}\s?\d{6}\b`,
+		piType:  PITypeImmiCard,
+		near:    keywordsNear([]string{"immicard", "immi card", "immi-card", "vevo"}, DefaultKeywordDistance),
+	})
+
 	// ABN matcher - 11 digits (check first to avoid TFN confusion)
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b\d{2}[\s]?\d{3}[\s]?\d{3}[\s]?\d{3}\b`,
 		piType:  PITypeABN,
-		d:       d,
 		validator: func(match string) bool {
 			// Remove spaces
 			clean := strings.ReplaceAll(match, " ", "")
@@ -196,10 +261,9 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b[2-6]\d{3}[\s\-]?\d{5}[\s\-]?\d{1}(?:/\d)?\b`,
 		piType:  PITypeMedicare,
-		d:       d,
 		validator: func(match string) bool {
 			// Remove spaces, dashes, and issue number
-			clean := regexp.MustCompile(`[\s\-/]`).ReplaceAllString(match, "")
+			clean := medicareSeparatorPattern.ReplaceAllString(match, "")
 			// Extract first 10 digits (ignore issue number if present)
 			if len(clean) < 10 {
 				return false
@@ -221,10 +285,9 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b\d{3}[\s\-]?\d{3}[\s\-]?\d{3}\b`,
 		piType:  PITypeTFN,
-		d:       d,
 		validator: func(match string) bool {
 			// Remove spaces and dashes
-			clean := regexp.MustCompile(`[\s\-]`).ReplaceAllString(match, "")
+			clean := separatorPattern.ReplaceAllString(match, "")
 			// Must be exactly 9 digits and not start with 0
 			if len(clean) != 9 || clean[0] == '0' {
 				return false
@@ -240,10 +303,9 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b\d{3}[\-]?\d{3}\b`,
 		piType:  PITypeBSB,
-		d:       d,
 		validator: func(match string) bool {
 			// Remove dashes and spaces
-			clean := regexp.MustCompile(`[\s\-]`).ReplaceAllString(match, "")
+			clean := separatorPattern.ReplaceAllString(match, "")
 			// Must be exactly 6 digits
 			if len(clean) != 6 {
 				return false
@@ -261,11 +323,9 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `(?i)(?:acn[:\s]*|company\s*acn[:\s]*|australian\s*company\s*number[:\s]*|findByACN\s*\(|// .*acn[:\s]*)\s*["']?\d{3}[\s]?\d{3}[\s]?\d{3}["']?`,
 		piType:  PITypeACN,
-		d:       d,
 		extractor: func(match string) string {
 			// Extract just the number part
-			numRe := regexp.MustCompile(`\d{3}[\s]?\d{3}[\s]?\d{3}`)
-			if num := numRe.FindString(match); num != "" {
+			if num := acnNumberPattern.FindString(match); num != "" {
 				return num
 			}
 			return ""
@@ -288,10 +348,9 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `(?:\+61[\s.-]?[2-9]\d{8}|\b0[2-9](?:[\s.-]?\d){8}\b|\(\d{2}\)\s*\d{4}\s*\d{4}|\b1[38]00[\s.-]?\d{3}[\s.-]?\d{3}\b)`,
 		piType:  PITypePhone,
-		d:       d,
 		validator: func(match string) bool {
 			// Remove all non-digits
-			digits := regexp.MustCompile(`[^\d]`).ReplaceAllString(match, "")
+			digits := nonDigitPattern.ReplaceAllString(match, "")
 			// Check for valid Australian phone formats
 			// Mobile: 10 digits starting with 04 or +614
 			// Landline: 10 digits starting with 02-09
@@ -311,7 +370,6 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}\b`,
 		piType:  PITypeEmail,
-		d:       d,
 	})
 
 	// Driver License matcher - state-specific patterns (AFTER phone to avoid conflicts)
@@ -323,10 +381,9 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b(?:[A-Z]\d{6}|[A-Z]{2}\d{5}|\d{7})\b`,
 		piType:  PITypeDriverLicense,
-		d:       d,
 		validator: func(match string) bool {
 			// Remove all non-digits for checking
-			digits := regexp.MustCompile(`[^\d]`).ReplaceAllString(match, "")
+			digits := nonDigitPattern.ReplaceAllString(match, "")
 
 			// Exclude phone numbers - they start with 0, +61, or 1300/1800
 			if match[0] == '0' || strings.HasPrefix(match, "+61") ||
@@ -338,7 +395,7 @@ func (d *detector) initializeMatchers() {
 			// Check driver license formats
 			if len(match) >= 7 && len(match) <= 10 {
 				// Numeric formats (NSW/QLD/VIC/WA/TAS)
-				if regexp.MustCompile(`^\d+$`).MatchString(match) {
+				if digitsPattern.MatchString(match) {
 					// Exclude 8 or 9-digit numbers that could be TFNs or other IDs
 					if len(match) == 8 || len(match) == 9 {
 						return false
@@ -363,7 +420,6 @@ func (d *detector) initializeMatchers() {
 	d.matchers = append(d.matchers, &regexMatcher{
 		pattern: `\b[A-Z][a-z]{2,}\s+[A-Z][a-z]{2,}(?:\s+[A-Z][a-z]{2,})?\b`,
 		piType:  PITypeName,
-		d:       d,
 		validator: func(match string) bool {
 			// Context-aware validation for code scanning
 			return d.isValidPersonName(match)
@@ -371,6 +427,63 @@ func (d *detector) initializeMatchers() {
 	})
 }
 
+// applyTypeSettings removes disabled matchers and swaps in custom patterns
+func (d *detector) applyTypeSettings() {
+	if len(d.config.TypeSettings) == 0 {
+		return
+	}
+
+	matchers := d.matchers[:0]
+	for _, matcher := range d.matchers {
+		settings, ok := d.config.TypeSettings[matcher.Type()]
+		if !ok {
+			matchers = append(matchers, matcher)
+			continue
+		}
+		if !settings.Enabled {
+			continue
+		}
+		if rm, isRegex := matcher.(*regexMatcher); isRegex && settings.CustomPattern != "" {
+			rm.pattern = settings.CustomPattern
+			rm.extractor = nil
+		}
+		matchers = append(matchers, matcher)
+	}
+	d.matchers = matchers
+}
+
+// addRules puts the user-defined rules ahead of the built-in matchers, so
+// their usually more specific patterns claim overlapping values
+func (d *detector) addRules() {
+	if len(d.config.Rules) == 0 {
+		return
+	}
+
+	matchers := make([]PatternMatcher, 0, len(d.config.Rules)+len(d.matchers))
+	for i := range d.config.Rules {
+		matchers = append(matchers, ruleMatcher(&d.config.Rules[i]))
+	}
+	d.matchers = append(matchers, d.matchers...)
+}
+
+// matcherRule returns the user-defined rule behind a matcher, or nil for the
+// built-in matchers
+func matcherRule(matcher PatternMatcher) *Rule {
+	if rm, ok := matcher.(*regexMatcher); ok {
+		return rm.rule
+	}
+	return nil
+}
+
+// validatorFor returns the checksum validator for a matcher's values. Rules
+// with a validator use their own; others use the validator for their type.
+func (d *detector) validatorFor(matcher PatternMatcher) (validation.Validator, bool) {
+	if rm, ok := matcher.(*regexMatcher); ok && rm.checksum != nil {
+		return rm.checksum, true
+	}
+	return d.validators.Get(string(matcher.Type()))
+}
+
 // shouldExclude checks if a file should be excluded from scanning
 func (d *detector) shouldExclude(filename string) bool {
 	for _, pattern := range d.config.ExcludePaths {
@@ -411,35 +524,25 @@ func (d *detector) getContextModifier(filename string) float32 {
 
 // calculateRiskLevel determines risk level based on PI type
 func (d *detector) calculateRiskLevel(piType PIType) RiskLevel {
-	weight := d.config.RiskWeights[piType]
+	thresholds := d.config.RiskThresholds
+	if thresholds == (RiskThresholds{}) {
+		thresholds = DefaultRiskThresholds()
+	}
+
+	score := float64(d.config.RiskWeights[piType]) / 100
 
 	switch {
-	case weight >= 90:
+	case thresholds.Critical > 0 && score >= thresholds.Critical:
+		return RiskLevelCritical
+	case score >= thresholds.High:
 		return RiskLevelHigh
-	case weight >= 60:
+	case score >= thresholds.Medium:
 		return RiskLevelMedium
 	default:
 		return RiskLevelLow
 	}
 }
 
-// getPosition calculates line and column from byte index
-func (d *detector) getPosition(content string, index int) (line, column int) {
-	line = 1
-	column = 1
-
-	for i := 0; i < index && i < len(content); i++ {
-		if content[i] == '\n' {
-			line++
-			column = 1
-		} else {
-			column++
-		}
-	}
-
-	return line, column
-}
-
 // extractContext extracts surrounding context
 func (d *detector) extractContext(content string, start, end int) (before, after string) {
 	contextSize := 50
@@ -574,13 +677,13 @@ func (d *detector) isValidPersonName(name string) bool {
 }
 
 // shouldIncludeFinding determines if a finding should be included based on context validation and confidence thresholds
-func (d *detector) shouldIncludeFinding(ctx context.Context, finding Finding, fileContent string) bool {
+func (d *detector) shouldIncludeFinding(ctx context.Context, finding Finding, lines *lineIndex) bool {
 	// For test files (low context modifier), we still want to detect PI
 	// but we'll rely more on context validation rather than confidence thresholds
 
 	// Apply advanced context validation if enabled
 	if d.config.EnableContextValidation {
-		isValid := d.validateContext(finding, fileContent)
+		isValid := d.validateContext(finding, lines)
 		if !isValid {
 			return false
 		}
@@ -599,6 +702,11 @@ func (d *detector) shouldIncludeFinding(ctx context.Context, finding Finding, fi
 
 // getMinimumConfidenceThreshold returns the minimum confidence threshold for a finding
 func (d *detector) getMinimumConfidenceThreshold(finding Finding) float32 {
+	// Per-type thresholds take precedence
+	if settings, ok := d.config.TypeSettings[finding.Type]; ok && settings.MinConfidence > 0 {
+		return settings.MinConfidence
+	}
+
 	// Use the configured minimum threshold if set
 	if d.config.MinConfidenceThreshold > 0 {
 		return d.config.MinConfidenceThreshold
@@ -611,24 +719,24 @@ func (d *detector) getMinimumConfidenceThreshold(finding Finding) float32 {
 }
 
 // validateContext performs simplified context validation
-func (d *detector) validateContext(finding Finding, fileContent string) bool {
+func (d *detector) validateContext(finding Finding, lines *lineIndex) bool {
 	// For test files, we're less strict about context validation
 	// since tests often contain real PI examples for testing purposes
 	isTestFile := finding.ContextModifier <= 0.1
 
 	// Check if finding is in test data context
-	if !isTestFile && d.isInTestContext(finding, fileContent) {
+	if !isTestFile && d.isInTestContext(finding, lines) {
 		return false // Suppress findings in test contexts (but not in test files)
 	}
 
 	// Check if finding is in comment and looks like example data
 	// Only suppress if it explicitly mentions it's an example
-	if d.isInCommentExample(finding, fileContent) {
+	if d.isInCommentExample(finding, lines) {
 		return false // Suppress obvious examples in comments
 	}
 
 	// Check if finding looks like mock/dummy data
-	if d.isInMockContext(finding, fileContent) {
+	if d.isInMockContext(finding, lines) {
 		return false // Suppress mock data
 	}
 
@@ -636,9 +744,9 @@ func (d *detector) validateContext(finding Finding, fileContent string) bool {
 }
 
 // isInTestContext checks if the finding is in a test-related context
-func (d *detector) isInTestContext(finding Finding, content string) bool {
+func (d *detector) isInTestContext(finding Finding, lines *lineIndex) bool {
 	// Extract context around the finding
-	context := d.extractLineContext(content, finding.Line, 3)
+	context := lines.around(finding.Line, 3)
 	contextLower := strings.ToLower(context)
 
 	// Test framework keywords
@@ -659,8 +767,8 @@ func (d *detector) isInTestContext(finding Finding, content string) bool {
 }
 
 // isInCommentExample checks if finding is in a comment that appears to be an example
-func (d *detector) isInCommentExample(finding Finding, content string) bool {
-	line := d.getLineContent(content, finding.Line)
+func (d *detector) isInCommentExample(finding Finding, lines *lineIndex) bool {
+	line := lines.around(finding.Line, 0)
 
 	// Check if line contains comment markers
 	if strings.Contains(line, "//") || strings.Contains(line, "#") ||
@@ -685,8 +793,8 @@ func (d *detector) isInCommentExample(finding Finding, content string) bool {
 }
 
 // isInMockContext checks if finding appears to be mock or dummy data
-func (d *detector) isInMockContext(finding Finding, content string) bool {
-	context := d.extractLineContext(content, finding.Line, 2)
+func (d *detector) isInMockContext(finding Finding, lines *lineIndex) bool {
+	context := lines.around(finding.Line, 2)
 	contextLower := strings.ToLower(context)
 
 	mockKeywords := []string{
@@ -705,112 +813,116 @@ func (d *detector) isInMockContext(finding Finding, content string) bool {
 	return false
 }
 
-// extractLineContext extracts context lines around a specific line number
-func (d *detector) extractLineContext(content string, lineNum int, contextLines int) string {
-	lines := strings.Split(content, "\n")
-	if lineNum <= 0 || lineNum > len(lines) {
-		return ""
-	}
+// regexMatcher implements PatternMatcher using regex
+type regexMatcher struct {
+	pattern   string
+	piType    PIType
+	re        *regexp.Regexp // Compiled pattern; nil when the pattern is invalid
+	validator func(string) bool
+	extractor func(string) string // Optional function to extract the actual value from the match
 
-	start := lineNum - contextLines - 1
-	if start < 0 {
-		start = 0
-	}
+	near func(content []byte, start, end int) bool // Reports whether a keyword is close enough to the match
 
-	end := lineNum + contextLines - 1
-	if end >= len(lines) {
-		end = len(lines) - 1
-	}
+	// User-defined rules only
+	rule     *Rule
+	checksum validation.Validator // The rule's validator, used instead of the type's
+}
 
-	contextSlice := lines[start : end+1]
-	return strings.Join(contextSlice, "\n")
+// compile compiles the matcher's pattern once its settings are applied
+func (m *regexMatcher) compile() {
+	m.re, _ = regexp.Compile(m.pattern)
 }
 
-// getLineContent returns the content of a specific line
-func (d *detector) getLineContent(content string, lineNum int) string {
-	lines := strings.Split(content, "\n")
-	if lineNum <= 0 || lineNum > len(lines) {
-		return ""
+// Match finds all pattern matches in content
+func (m *regexMatcher) Match(content []byte) []PatternMatch {
+	if m.re == nil {
+		return nil
 	}
-	return lines[lineNum-1]
+
+	var matches []PatternMatch
+	for _, loc := range m.re.FindAllIndex(content, -1) {
+		if match, ok := m.accept(content, loc[0], loc[1]); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
 }
 
-// getRegexp returns a compiled regex, using cache
-func (d *detector) getRegexp(pattern string) (*regexp.Regexp, error) {
-	d.mu.RLock()
-	if re, ok := d.compiled[pattern]; ok {
-		d.mu.RUnlock()
-		return re, nil
+// accept applies the extractor and validator to the text matched between
+// start and end
+func (m *regexMatcher) accept(content []byte, start, end int) (PatternMatch, bool) {
+	if m.near != nil && !m.near(content, start, end) {
+		return PatternMatch{}, false
 	}
-	d.mu.RUnlock()
 
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	value := string(content[start:end])
 
-	// Double-check after acquiring write lock
-	if re, ok := d.compiled[pattern]; ok {
-		return re, nil
+	// Apply extractor if present
+	if m.extractor != nil {
+		value = m.extractor(value)
+		if value == "" {
+			return PatternMatch{}, false
+		}
 	}
 
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return nil, err
+	// Apply validator if present
+	if m.validator != nil && !m.validator(value) {
+		return PatternMatch{}, false
 	}
 
-	d.compiled[pattern] = re
-	return re, nil
+	return PatternMatch{
+		Value:      value,
+		StartIndex: start,
+		EndIndex:   end,
+	}, true
 }
 
-// regexMatcher implements PatternMatcher using regex
-type regexMatcher struct {
-	pattern   string
-	piType    PIType
-	d         *detector
-	validator func(string) bool
-	extractor func(string) string // Optional function to extract the actual value from the match
+// Type returns the PI type this matcher detects
+func (m *regexMatcher) Type() PIType {
+	return m.piType
 }
 
-// Match finds all pattern matches in content
-func (m *regexMatcher) Match(content []byte) []PatternMatch {
-	re, err := m.d.getRegexp(m.pattern)
-	if err != nil {
-		return nil
-	}
-
-	var matches []PatternMatch
-	allMatches := re.FindAllIndex(content, -1)
-
-	for _, match := range allMatches {
-		if len(match) >= 2 {
-			value := string(content[match[0]:match[1]])
-
-			// Apply validator if present
-			// Apply extractor if present
-			extractedValue := value
-			if m.extractor != nil {
-				extractedValue = m.extractor(value)
-				if extractedValue == "" {
-					continue
-				}
-			}
-
-			// Apply validator if present
-			if m.validator != nil && !m.validator(extractedValue) {
-				continue
-			}
+// lineIndex locates lines in content, so findings do not rescan the content
+// for their position and context
+type lineIndex struct {
+	content string
+	starts  []int // Offset of the first byte of each line
+}
 
-			matches = append(matches, PatternMatch{
-				Value:      extractedValue,
-				StartIndex: match[0],
-				EndIndex:   match[1],
-			})
+// newLineIndex indexes the lines of content
+func newLineIndex(content string) *lineIndex {
+	starts := make([]int, 1, strings.Count(content, "\n")+1)
+	for i := 0; ; {
+		n := strings.IndexByte(content[i:], '\n')
+		if n < 0 {
+			break
 		}
+		i += n + 1
+		starts = append(starts, i)
 	}
+	return &lineIndex{content: content, starts: starts}
+}
 
-	return matches
+// position returns the 1-based line and byte column of an offset
+func (l *lineIndex) position(offset int) (line, column int) {
+	line = sort.SearchInts(l.starts, offset+1)
+	return line, offset - l.starts[line-1] + 1
 }
 
-// Type returns the PI type this matcher detects
-func (m *regexMatcher) Type() PIType {
-	return m.piType
+// around returns the given line with up to contextLines lines either side,
+// or "" when the line does not exist
+func (l *lineIndex) around(lineNum int, contextLines int) string {
+	if lineNum <= 0 || lineNum > len(l.starts) {
+		return ""
+	}
+
+	start := lineNum - contextLines
+	if start < 1 {
+		start = 1
+	}
+	end := lineNum + contextLines
+	if end >= len(l.starts) {
+		return l.content[l.starts[start-1]:]
+	}
+	return l.content[l.starts[start-1] : l.starts[end]-1]
 }
@@ -366,3 +366,188 @@ func TestDetector_DetectWithValidation(t *testing.T) {
 		})
 	}
 }
+
+// identifierTest is content holding one identifier of an expected type
+type identifierTest struct {
+	name          string
+	content       string
+	expectedType  PIType
+	expectedValid bool
+}
+
+// runIdentifierTests checks each test's content yields one finding of its
+// type and validity
+func runIdentifierTests(t *testing.T, detector Detector, filename string, tests []identifierTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := detector.Detect(context.Background(), []byte(tt.content), filename)
+			require.NoError(t, err)
+			require.Len(t, findings, 1)
+
+			assert.Equal(t, tt.expectedType, findings[0].Type)
+			assert.Equal(t, tt.expectedValid, findings[0].Validated)
+		})
+	}
+}
+
+func TestDetector_HealthcareIdentifiers(t *testing.T) {
+	detector := NewDetector()
+	runIdentifierTests(t, detector, "patient.go", []identifierTest{
+		{"IHI", `patient.IHI = "8003608166690503"`, PITypeIHI, true},
+		{"IHI in groups of four", `ihi := "8003 6081 6669 0503"`, PITypeIHI, true},
+		{"IHI with dashes", `ihi := "8003-6081-6669-0503"`, PITypeIHI, true},
+		{"IHI failing Luhn", `ihi := "8003608166690504"`, PITypeIHI, false},
+		{"HPI-I", `practitioner.HPII = "8003612345678900"`, PITypeHPII, true},
+		{"HPI-O", `clinic.HPIO = "8003 6234 5678 9014"`, PITypeHPIO, true},
+	})
+
+	// Other prefixes are not healthcare identifiers
+	findings, err := detector.Detect(context.Background(), []byte(`ref := "8003638166690503"`), "patient.go")
+	require.NoError(t, err)
+	for _, f := range findings {
+		assert.NotContains(t, []PIType{PITypeIHI, PITypeHPII, PITypeHPIO}, f.Type)
+	}
+}
+
+func TestDetector_GovernmentServicesIdentifiers(t *testing.T) {
+	detector := NewDetector()
+	runIdentifierTests(t, detector, "customer.go", []identifierTest{
+		{"CRN", `customer.CRN = "204567890X"`, PITypeCRN, true},
+		{"CRN with spaces", `crn := "123 456 789C"`, PITypeCRN, true},
+		{"CRN with wrong check letter", `crn := "204567890A"`, PITypeCRN, false},
+		{"DVA file number", `veteran.DVAFileNumber = "NX123456"`, PITypeDVA, true},
+		{"DVA file number with segment", `veteranFileNo := "QKM12345B"`, PITypeDVA, true},
+		{"DVA file number with unknown war code", `dvaNo := "NZZ12345"`, PITypeDVA, false},
+		{"ImmiCard", `applicant.ImmiCardNumber = "EIK123456"`, PITypeImmiCard, true},
+	})
+
+	// DVA file numbers and ImmiCard numbers need a label near them
+	findings, err := detector.Detect(context.Background(), []byte(`build := "NX123456"
+sku := "EIK123456"`), "customer.go")
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestDetector_TypeSettings(t *testing.T) {
+	content := []byte(`validTFN := "123456782"
+invalidTFN := "123456789"
+contact := "jane.citizen@company.com.au"
+`)
+
+	findTypes := func(findings []Finding, piType PIType) []string {
+		var matches []string
+		for _, f := range findings {
+			if f.Type == piType {
+				matches = append(matches, f.Match)
+			}
+		}
+		return matches
+	}
+
+	tests := []struct {
+		name          string
+		settings      map[PIType]TypeSettings
+		expectedTFNs  []string
+		expectedEmail bool
+	}{
+		{
+			name:          "no overrides",
+			expectedTFNs:  []string{"123456782", "123456789"},
+			expectedEmail: true,
+		},
+		{
+			name: "disabled type is not detected",
+			settings: map[PIType]TypeSettings{
+				PITypeEmail: {Enabled: false},
+			},
+			expectedTFNs:  []string{"123456782", "123456789"},
+			expectedEmail: false,
+		},
+		{
+			name: "strict mode drops failed checksums",
+			settings: map[PIType]TypeSettings{
+				PITypeTFN: {Enabled: true, StrictMode: true},
+			},
+			expectedTFNs:  []string{"123456782"},
+			expectedEmail: true,
+		},
+		{
+			name: "per-type minimum confidence",
+			settings: map[PIType]TypeSettings{
+				PITypeTFN: {Enabled: true, MinConfidence: 0.9},
+			},
+			expectedTFNs:  []string{"123456782"},
+			expectedEmail: true,
+		},
+		{
+			name: "custom pattern replaces built-in pattern",
+			settings: map[PIType]TypeSettings{
+				PITypeTFN: {Enabled: true, CustomPattern: `\b123456782\b`},
+			},
+			expectedTFNs:  []string{"123456782"},
+			expectedEmail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.TypeSettings = tt.settings
+
+			findings, err := NewDetectorWithConfig(config).Detect(context.Background(), content, "payroll.go")
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, tt.expectedTFNs, findTypes(findings, PITypeTFN))
+			assert.Equal(t, tt.expectedEmail, len(findTypes(findings, PITypeEmail)) > 0)
+		})
+	}
+}
+
+func TestDetector_RiskThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds RiskThresholds
+		expected   map[PIType]RiskLevel
+	}{
+		{
+			name:       "default thresholds",
+			thresholds: DefaultRiskThresholds(),
+			expected: map[PIType]RiskLevel{
+				PITypeTFN:   RiskLevelHigh,
+				PITypeABN:   RiskLevelMedium,
+				PITypeEmail: RiskLevelLow,
+			},
+		},
+		{
+			name:       "critical threshold enabled",
+			thresholds: RiskThresholds{Critical: 0.8, High: 0.6, Medium: 0.4},
+			expected: map[PIType]RiskLevel{
+				PITypeTFN:   RiskLevelCritical,
+				PITypeABN:   RiskLevelHigh,
+				PITypeName:  RiskLevelMedium,
+				PITypeEmail: RiskLevelLow,
+			},
+		},
+		{
+			name:       "zero thresholds fall back to defaults",
+			thresholds: RiskThresholds{},
+			expected: map[PIType]RiskLevel{
+				PITypeTFN:   RiskLevelHigh,
+				PITypeEmail: RiskLevelLow,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.RiskThresholds = tt.thresholds
+			d := NewDetectorWithConfig(config).(*detector)
+
+			for piType, level := range tt.expected {
+				assert.Equal(t, level, d.calculateRiskLevel(piType), "risk level for %s", piType)
+			}
+		})
+	}
+}
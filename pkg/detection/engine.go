@@ -0,0 +1,627 @@
+package detection
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxMatchLength bounds the text verified for patterns without a fixed
+// maximum length, such as names and emails. No PI value comes close; longer
+// matches may be missed.
+const maxMatchLength = 256
+
+// engine finds the matches of every pattern matcher in a single pass over the
+// content. Each pattern is precompiled into prefilters that rule out most
+// offsets from the first few bytes there, and a regex anchored at the offsets
+// left over. The regex sees the byte before the offset, so results match
+// regexp.FindAllIndex for all matches up to maxMatchLength bytes.
+type engine struct {
+	matchers []PatternMatcher
+	patterns []*pattern // Indexed like matchers; nil where the matcher is not a regex matcher
+	byByte   [256][]int // Patterns a match can start with each byte
+	fallback []int      // Matchers run on their own over the whole content
+}
+
+// newEngine compiles the pattern matchers into an engine
+func newEngine(matchers []PatternMatcher) *engine {
+	e := &engine{
+		matchers: matchers,
+		patterns: make([]*pattern, len(matchers)),
+	}
+
+	for i, matcher := range matchers {
+		rm, ok := matcher.(*regexMatcher)
+		if !ok || rm.re == nil {
+			e.fallback = append(e.fallback, i)
+			continue
+		}
+		p, err := compilePattern(rm.pattern)
+		if err != nil {
+			e.fallback = append(e.fallback, i)
+			continue
+		}
+		e.patterns[i] = p
+		for b := range p.first {
+			if p.first[b] {
+				e.byByte[b] = append(e.byByte[b], i)
+			}
+		}
+	}
+
+	return e
+}
+
+// match returns the matches of each matcher, indexed like the matchers
+func (e *engine) match(content []byte) [][]PatternMatch {
+	results := make([][]PatternMatch, len(e.matchers))
+	for _, i := range e.fallback {
+		results[i] = e.matchers[i].Match(content)
+	}
+
+	// Per pattern state: the offset its previous match ended at, as matches do
+	// not overlap, and the next offset holding its required byte
+	next := make([]int, len(e.patterns))
+	required := make([]int, len(e.patterns))
+	for i, p := range e.patterns {
+		if p == nil || !p.hasRequired {
+			continue
+		}
+		required[i] = bytes.IndexByte(content, p.required)
+		if required[i] < 0 {
+			next[i] = len(content) // Content without the byte cannot match
+		}
+	}
+
+	for pos, c := range content {
+		candidates := e.byByte[c]
+		if len(candidates) == 0 || c >= utf8.RuneSelf && insideRune(content, pos) {
+			continue
+		}
+
+		for _, i := range candidates {
+			if pos < next[i] {
+				continue
+			}
+			p := e.patterns[i]
+
+			if p.boundary[c] && isWordByte(c) == (pos > 0 && isWordByte(content[pos-1])) {
+				continue
+			}
+			if p.hasRequired {
+				if required[i] < pos {
+					found := bytes.IndexByte(content[pos:], p.required)
+					if found < 0 {
+						next[i] = len(content)
+						continue
+					}
+					required[i] = pos + found
+				}
+				if required[i]-pos >= p.window {
+					next[i] = required[i] - p.window + 1
+					continue
+				}
+			}
+			if !p.hasOffsetBytes(content[pos:]) || p.prefixes != nil && !p.hasPrefix(content[pos:]) {
+				continue
+			}
+
+			end, ok := p.matchAt(content, pos)
+			if !ok {
+				continue
+			}
+			next[i] = end
+
+			rm := e.matchers[i].(*regexMatcher)
+			if match, ok := rm.accept(content, pos, end); ok {
+				results[i] = append(results[i], match)
+			}
+		}
+	}
+
+	return results
+}
+
+// pattern is a regex prepared for verification at candidate offsets
+type pattern struct {
+	atStart   *regexp.Regexp // Anchored at the start of the content
+	afterByte *regexp.Regexp // Anchored one byte before the offset, so \b and (?m)^ see it
+	first     [256]bool      // Bytes a match can start with
+	offsets   [][256]bool    // Bytes a match can hold at each of its first few offsets
+	boundary  [256]bool      // Bytes a match only starts with after a \b
+	prefixes  []prefix       // Literal text every match starts with, when known
+	window    int            // Bytes verified from an offset, lookahead included
+
+	required    byte // Byte every match contains, within window of its start
+	hasRequired bool
+}
+
+// prefix is literal text a match starts with
+type prefix struct {
+	text string
+	fold bool // Compared ignoring ASCII case; text is lower case
+}
+
+// compilePattern prepares a regex for the engine. Patterns that can match
+// empty text have no useful prefilter and are refused.
+func compilePattern(expr string) (*pattern, error) {
+	atStart, err := regexp.Compile(`^(?:` + expr + `)`)
+	if err != nil {
+		return nil, err
+	}
+	afterByte, err := regexp.Compile(`^(?s:.)(?:` + expr + `)`)
+	if err != nil {
+		return nil, err
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts startBytes
+	if starts.walk(re, false) {
+		return nil, fmt.Errorf("pattern %q matches empty text", expr)
+	}
+	p := &pattern{atStart: atStart, afterByte: afterByte, first: starts.any}
+	for b := range p.boundary {
+		p.boundary[b] = starts.any[b] && !starts.free[b]
+	}
+	if prefixes, ok := literalPrefixes(re); ok {
+		p.prefixes = prefixes
+	}
+
+	p.window = maxMatchLength
+	if n, bounded := maxBytes(re); bounded && n < maxMatchLength {
+		p.window = n
+	}
+	p.window += utf8.UTFMax
+	p.required, p.hasRequired = requiredByte(re)
+
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	p.offsets = offsetBytes(prog, prefilterOffsets)
+
+	return p, nil
+}
+
+// matchAt returns the end of the match starting at pos, if there is one
+func (p *pattern) matchAt(content []byte, pos int) (int, bool) {
+	start, re := pos, p.atStart
+	if pos > 0 {
+		start, re = pos-1, p.afterByte
+	}
+
+	limit := pos + p.window
+	if limit > len(content) {
+		limit = len(content)
+	}
+	loc := re.FindIndex(content[start:limit])
+	if loc != nil && start+loc[1] == limit && limit < len(content) {
+		// The match may run on past the window, or end there only because
+		// the window does
+		loc = re.FindIndex(content[start:])
+	}
+	if loc == nil || start+loc[1] == pos {
+		return 0, false
+	}
+	return start + loc[1], true
+}
+
+// hasOffsetBytes reports whether text starts with bytes a match can start with
+func (p *pattern) hasOffsetBytes(text []byte) bool {
+	if len(text) < len(p.offsets) {
+		return false
+	}
+	for i := 1; i < len(p.offsets); i++ {
+		if !p.offsets[i][text[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPrefix reports whether text starts with one of the pattern's prefixes
+func (p *pattern) hasPrefix(text []byte) bool {
+	for _, pre := range p.prefixes {
+		if len(text) < len(pre.text) {
+			continue
+		}
+		if pre.fold {
+			if equalFoldASCII(text[:len(pre.text)], pre.text) {
+				return true
+			}
+		} else if string(text[:len(pre.text)]) == pre.text {
+			return true
+		}
+	}
+	return false
+}
+
+// startBytes collects the bytes matches of a regex can start with
+type startBytes struct {
+	any  [256]bool // Bytes a match can start with
+	free [256]bool // Bytes a match can start with when no \b comes first
+}
+
+// walk marks the bytes a match of re can start with, reporting whether re can
+// match empty text; bounded is set once a \b has come first. Runes outside
+// ASCII mark the first byte of their encoding, and classes of them every byte
+// outside ASCII, which over-approximates but never misses.
+func (s *startBytes) walk(re *syntax.Regexp, bounded bool) (nullable bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if len(re.Rune) == 0 {
+			return true
+		}
+		s.markRune(re.Rune[0], bounded)
+		if re.Flags&syntax.FoldCase != 0 {
+			for r := unicode.SimpleFold(re.Rune[0]); r != re.Rune[0]; r = unicode.SimpleFold(r) {
+				s.markRune(r, bounded)
+			}
+		}
+		return false
+
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1] && r < utf8.RuneSelf; r++ {
+				s.mark(byte(r), bounded)
+			}
+			if re.Rune[i+1] >= utf8.RuneSelf {
+				// Invalid UTF-8 decodes a byte at a time
+				for b := utf8.RuneSelf; b < 0x100; b++ {
+					s.mark(byte(b), bounded)
+				}
+			}
+		}
+		return false
+
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		for b := 0; b < 0x100; b++ {
+			if b != '\n' || re.Op == syntax.OpAnyChar {
+				s.mark(byte(b), bounded)
+			}
+		}
+		return false
+
+	case syntax.OpCapture, syntax.OpPlus:
+		return s.walk(re.Sub[0], bounded)
+
+	case syntax.OpStar, syntax.OpQuest:
+		s.walk(re.Sub[0], bounded)
+		return true
+
+	case syntax.OpRepeat:
+		return s.walk(re.Sub[0], bounded) || re.Min == 0
+
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpWordBoundary {
+				bounded = true
+				continue
+			}
+			if !s.walk(sub, bounded) {
+				return false
+			}
+		}
+		return true
+
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if s.walk(sub, bounded) {
+				nullable = true
+			}
+		}
+		return nullable
+
+	case syntax.OpNoMatch:
+		return false
+	}
+
+	// Empty-width assertions and empty matches consume nothing
+	return true
+}
+
+// mark marks a byte a match can start with
+func (s *startBytes) mark(b byte, bounded bool) {
+	s.any[b] = true
+	if !bounded {
+		s.free[b] = true
+	}
+}
+
+// markRune marks the first byte of a rune's UTF-8 encoding
+func (s *startBytes) markRune(r rune, bounded bool) {
+	var buf [utf8.UTFMax]byte
+	utf8.EncodeRune(buf[:], r)
+	s.mark(buf[0], bounded)
+}
+
+// prefilterOffsets is how many leading bytes of a match offsetBytes checks
+const prefilterOffsets = 8
+
+// offsetBytes returns the bytes a match can hold at each of its first limit
+// offsets, from the program's instructions. It stops early at an offset a
+// match can end before, or once a rune outside ASCII leaves the byte offsets
+// that follow unknown.
+func offsetBytes(prog *syntax.Prog, limit int) [][256]bool {
+	var sets [][256]bool
+	states := closure(prog, []uint32{uint32(prog.Start)})
+	for len(states) > 0 && len(sets) < limit {
+		var set [256]bool
+		var next []uint32
+		multibyte := false
+		for _, pc := range states {
+			inst := &prog.Inst[pc]
+			if inst.Op == syntax.InstMatch {
+				return sets
+			}
+			for b := 0; b < utf8.RuneSelf; b++ {
+				if inst.MatchRune(rune(b)) {
+					set[b] = true
+				}
+			}
+			multibyte = multibyte || matchesMultibyte(inst)
+			next = append(next, inst.Out)
+		}
+		if multibyte {
+			// Invalid UTF-8 decodes a byte at a time
+			for b := utf8.RuneSelf; b < 0x100; b++ {
+				set[b] = true
+			}
+			return append(sets, set)
+		}
+		sets = append(sets, set)
+		states = closure(prog, next)
+	}
+	return sets
+}
+
+// closure follows the instructions that consume no text from pcs, returning
+// the instructions that consume a rune or end a match. Empty-width
+// assertions are assumed to hold.
+func closure(prog *syntax.Prog, pcs []uint32) []uint32 {
+	var states []uint32
+	seen := make(map[uint32]bool)
+	for len(pcs) > 0 {
+		pc := pcs[len(pcs)-1]
+		pcs = pcs[:len(pcs)-1]
+		if seen[pc] {
+			continue
+		}
+		seen[pc] = true
+
+		switch inst := &prog.Inst[pc]; inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			pcs = append(pcs, inst.Out, inst.Arg)
+		case syntax.InstCapture, syntax.InstNop, syntax.InstEmptyWidth:
+			pcs = append(pcs, inst.Out)
+		case syntax.InstMatch, syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			states = append(states, pc)
+		}
+	}
+	return states
+}
+
+// matchesMultibyte reports whether a rune instruction can match a rune
+// outside ASCII
+func matchesMultibyte(inst *syntax.Inst) bool {
+	switch {
+	case inst.Op == syntax.InstRuneAny || inst.Op == syntax.InstRuneAnyNotNL:
+		return true
+	case len(inst.Rune) == 0:
+		return false
+	case len(inst.Rune) == 1:
+		// A single rune, folded when the instruction ignores case
+		r := inst.Rune[0]
+		if syntax.Flags(inst.Arg)&syntax.FoldCase != 0 {
+			for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+				if f >= utf8.RuneSelf {
+					return true
+				}
+			}
+		}
+		return r >= utf8.RuneSelf
+	}
+	return inst.Rune[len(inst.Rune)-1] >= utf8.RuneSelf
+}
+
+// insideRune reports whether pos falls within a valid multibyte rune, where
+// no match can start
+func insideRune(content []byte, pos int) bool {
+	for q := pos - 1; q >= 0 && q > pos-utf8.UTFMax; q-- {
+		if utf8.RuneStart(content[q]) {
+			_, size := utf8.DecodeRune(content[q:])
+			return q+size > pos
+		}
+	}
+	return false
+}
+
+// maxPrefixes caps the literal prefixes kept for a pattern
+const maxPrefixes = 16
+
+// literalPrefixes returns literal text every match of re starts with, one
+// prefix per alternative. It fails when an alternative starts with anything
+// but ASCII literal text.
+func literalPrefixes(re *syntax.Regexp) ([]prefix, bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		fold := re.Flags&syntax.FoldCase != 0
+		text := make([]byte, 0, len(re.Rune))
+		for _, r := range re.Rune {
+			// k and s also fold to runes outside ASCII
+			if r >= utf8.RuneSelf || fold && strings.ContainsRune("kKsS", r) {
+				break
+			}
+			if fold {
+				r = unicode.ToLower(r)
+			}
+			text = append(text, byte(r))
+		}
+		if len(text) == 0 {
+			return nil, false
+		}
+		return []prefix{{text: string(text), fold: fold}}, true
+
+	case syntax.OpCapture:
+		return literalPrefixes(re.Sub[0])
+
+	case syntax.OpConcat:
+		subs := re.Sub
+		for len(subs) > 0 && isEmptyWidth(subs[0].Op) {
+			subs = subs[1:]
+		}
+		if len(subs) == 0 {
+			return nil, false
+		}
+		heads, ok := literalPrefixes(subs[0])
+		if !ok || subs[0].Op != syntax.OpLiteral || len(subs) == 1 {
+			return heads, ok
+		}
+
+		// A literal followed by more literal text, such as a factored
+		// alternation, extends into it
+		tails, ok := literalPrefixes(&syntax.Regexp{Op: syntax.OpConcat, Sub: subs[1:]})
+		if !ok || len(tails) > maxPrefixes || len(heads[0].text) != len(subs[0].Rune) {
+			return heads, true
+		}
+		var prefixes []prefix
+		for _, tail := range tails {
+			head := heads[0]
+			if tail.fold && !head.fold {
+				head = prefix{text: toLowerASCII(head.text), fold: true}
+			}
+			if head.fold && !tail.fold {
+				tail = prefix{text: toLowerASCII(tail.text), fold: true}
+			}
+			prefixes = append(prefixes, prefix{text: head.text + tail.text, fold: head.fold})
+		}
+		return prefixes, true
+
+	case syntax.OpAlternate:
+		var prefixes []prefix
+		for _, sub := range re.Sub {
+			subPrefixes, ok := literalPrefixes(sub)
+			if !ok {
+				return nil, false
+			}
+			prefixes = append(prefixes, subPrefixes...)
+		}
+		return prefixes, len(prefixes) <= maxPrefixes
+	}
+	return nil, false
+}
+
+// maxBytes returns the longest UTF-8 text a match of re can span, if that is
+// bounded
+func maxBytes(re *syntax.Regexp) (int, bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		n := 0
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 {
+				n += utf8.UTFMax
+			} else {
+				n += utf8.RuneLen(r)
+			}
+		}
+		return n, true
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return 0, true
+		}
+		return utf8.RuneLen(re.Rune[len(re.Rune)-1]), true
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return utf8.UTFMax, true
+	case syntax.OpCapture, syntax.OpQuest:
+		return maxBytes(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		return 0, false
+	case syntax.OpRepeat:
+		n, bounded := maxBytes(re.Sub[0])
+		if re.Max < 0 {
+			return 0, false
+		}
+		return n * re.Max, bounded
+	case syntax.OpConcat, syntax.OpAlternate:
+		total := 0
+		for _, sub := range re.Sub {
+			n, bounded := maxBytes(sub)
+			if !bounded {
+				return 0, false
+			}
+			if re.Op == syntax.OpConcat {
+				total += n
+			} else if n > total {
+				total = n
+			}
+		}
+		return total, true
+	}
+	return 0, true
+}
+
+// requiredByte returns an ASCII byte that every match of re contains, taken
+// from the literal text of its top level
+func requiredByte(re *syntax.Regexp) (byte, bool) {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	for _, sub := range subs {
+		if sub.Op == syntax.OpLiteral && sub.Flags&syntax.FoldCase == 0 &&
+			len(sub.Rune) > 0 && sub.Rune[0] < utf8.RuneSelf {
+			return byte(sub.Rune[0]), true
+		}
+	}
+	return 0, false
+}
+
+// isEmptyWidth reports whether an operator matches without consuming text
+func isEmptyWidth(op syntax.Op) bool {
+	switch op {
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpEmptyMatch:
+		return true
+	}
+	return false
+}
+
+// isWordByte reports whether b is an ASCII word character, as \b defines it
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// equalFoldASCII reports whether text equals lower, ignoring ASCII case
+func equalFoldASCII(text []byte, lower string) bool {
+	for i := 0; i < len(lower); i++ {
+		b := text[i]
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		if b != lower[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// toLowerASCII lower cases the ASCII letters of s
+func toLowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,88 @@
+package detection
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// engineFragments are pieces of text that start, end or break up matches of
+// the built-in patterns
+var engineFragments = []string{
+	"0", "1", "2", "4", "6", "9", "123", "456", "61", "+61", "04", "1300", "(02)", "8003", "60",
+	"NX", "EIK", "L", "dva ", "ImmiCard: ",
+	" ", " ", "-", "/", ".", "\n", "\t", "_", "@", "example.com", ".au",
+	"a", "Z", "AB", "John", "Smith", "Mary", "acn", "ACN: ", "Company ACN ",
+	"australian company number ", "findByACN(", "// acn ", "\"", "'", ":",
+	"é", "日本", "\xff", "\x80",
+}
+
+// randomContent strings fragments together into text dense with near misses
+func randomContent(r *rand.Rand, fragments int) []byte {
+	var b strings.Builder
+	for i := 0; i < fragments; i++ {
+		b.WriteString(engineFragments[r.Intn(len(engineFragments))])
+	}
+	return []byte(b.String())
+}
+
+func TestEngine_MatchesRegexp(t *testing.T) {
+	d := NewDetector().(*detector)
+	matchers := append([]PatternMatcher{}, d.matchers...)
+
+	// Patterns outside the built-ins exercise the other prefilters
+	for _, expr := range []string{
+		`(?m)^\d{4}$`,
+		`(?i)tfn[:=]\s*\d+`,
+		`[^\s]{3}\d`,
+		`\B\d{3}`,
+		`.{2}@`,
+		`日本\d`,
+		`(?:ab|cd)*\d`,
+	} {
+		m := &regexMatcher{pattern: expr, piType: PITypeTFN}
+		m.compile()
+		require.NotNil(t, m.re, expr)
+		matchers = append(matchers, m)
+	}
+
+	e := newEngine(matchers)
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 2000; n++ {
+		content := randomContent(r, 1+r.Intn(60))
+		results := e.match(content)
+		for i, matcher := range matchers {
+			assert.Equal(t, matcher.Match(content), results[i], "%s in %q", matcher.(*regexMatcher).pattern, content)
+		}
+	}
+}
+
+func TestEngine_Fallback(t *testing.T) {
+	// A pattern that matches empty text has no prefilter, so runs on its own
+	m := &regexMatcher{pattern: `\d*`, piType: PITypeTFN, validator: func(s string) bool { return s != "" }}
+	m.compile()
+
+	e := newEngine([]PatternMatcher{m})
+	assert.Nil(t, e.patterns[0])
+	assert.Equal(t, []PatternMatch{{Value: "123", StartIndex: 2, EndIndex: 5}}, e.match([]byte("a 123 b"))[0])
+}
+
+func TestLineIndex(t *testing.T) {
+	content := "one\ntwo\r\n\nfour"
+	lines := newLineIndex(content)
+
+	line, column := lines.position(0)
+	assert.Equal(t, []int{1, 1}, []int{line, column})
+	line, column = lines.position(6)
+	assert.Equal(t, []int{2, 3}, []int{line, column})
+	line, column = lines.position(len(content))
+	assert.Equal(t, []int{4, 5}, []int{line, column})
+
+	assert.Equal(t, "two\r", lines.around(2, 0))
+	assert.Equal(t, "one\ntwo\r\n", lines.around(2, 1))
+	assert.Equal(t, content, lines.around(3, 5))
+	assert.Equal(t, "", lines.around(5, 1))
+}
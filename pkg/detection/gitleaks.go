@@ -9,9 +9,11 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+	"github.com/zricethezav/gitleaks/v8/cmd/scm"
 	"github.com/zricethezav/gitleaks/v8/config"
 	"github.com/zricethezav/gitleaks/v8/detect"
 	"github.com/zricethezav/gitleaks/v8/report"
+	"github.com/zricethezav/gitleaks/v8/sources"
 )
 
 // gitleaksDetector wraps Gitleaks for PI detection
@@ -118,6 +120,19 @@ func (g *gitleaksDetector) Detect(ctx context.Context, content []byte, filename
 		FilePath: filename,
 	}
 
+	// Pass commit details through so Gitleaks reports them on its findings
+	if commit := CommitFromContext(ctx); commit != nil {
+		fragment.CommitSHA = commit.SHA
+		fragment.CommitInfo = &sources.CommitInfo{
+			SHA:         commit.SHA,
+			AuthorName:  commit.Author,
+			AuthorEmail: commit.AuthorEmail,
+			Date:        commit.Date.Format(time.RFC3339),
+			Message:     commit.Message,
+			Remote:      &sources.RemoteInfo{Platform: scm.NoPlatform},
+		}
+	}
+
 	// Run detection
 	results := g.detector.Detect(fragment)
 
@@ -149,7 +164,7 @@ func (g *gitleaksDetector) convertFinding(finding report.Finding, filename strin
 		match = finding.Match
 	}
 
-	return Finding{
+	result := Finding{
 		Type:            piType,
 		Match:           match,
 		File:            filename,
@@ -165,6 +180,20 @@ func (g *gitleaksDetector) convertFinding(finding report.Finding, filename strin
 		DetectedAt:      time.Now(),
 		DetectorName:    g.Name(),
 	}
+
+	if finding.Commit != "" {
+		result.Commit = &CommitInfo{
+			SHA:         finding.Commit,
+			Author:      finding.Author,
+			AuthorEmail: finding.Email,
+			Message:     finding.Message,
+		}
+		if date, err := time.Parse(time.RFC3339, finding.Date); err == nil {
+			result.Commit.Date = date
+		}
+	}
+
+	return result
 }
 
 // mapRuleToType maps Gitleaks rule IDs to our PI types
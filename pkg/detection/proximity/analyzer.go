@@ -3,6 +3,8 @@ package proximity
 import (
 	"regexp"
 	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/structured"
 )
 
 // ContextAnalyzer provides utilities for analyzing the context around potential PI matches
@@ -164,6 +166,11 @@ func (ca *ContextAnalyzer) AnalyzeStructure(content string, startIndex, endIndex
 		endIndex = len(content)
 	}
 
+	// Content that parses as structured data has an exact type and field
+	if analysis, ok := ca.analyzeStructuredData(content, startIndex, endIndex); ok {
+		return analysis
+	}
+
 	// Extract larger context for structure analysis
 	before, after := ca.ExtractSurroundingText(content, startIndex, endIndex, 200)
 
@@ -395,6 +402,35 @@ func (ca *ContextAnalyzer) extractWords(text string) []string {
 	return words
 }
 
+// analyzeStructuredData places a match in CSV, TSV, JSON or YAML content,
+// reporting the column header or key path of the value holding it
+func (ca *ContextAnalyzer) analyzeStructuredData(content string, startIndex, endIndex int) (StructureAnalysis, bool) {
+	data := structured.Sniff([]byte(content))
+	if data == nil {
+		return StructureAnalysis{}, false
+	}
+	field, ok := data.LookupOffset(startIndex)
+	if !ok {
+		return StructureAnalysis{}, false
+	}
+
+	analysis := StructureAnalysis{Field: field.Path}
+	switch data.Format {
+	case structured.FormatJSON:
+		analysis.Type = StructureJSON
+	case structured.FormatYAML:
+		analysis.Type = StructureYAML
+	default:
+		analysis.Type = StructureCSV
+		analysis.Field = field.Header
+		return analysis, true
+	}
+
+	before, after := ca.ExtractSurroundingText(content, startIndex, endIndex, 200)
+	analysis.NestingLevel = ca.calculateNestingLevel(before+content[startIndex:endIndex]+after, analysis.Type)
+	return analysis, true
+}
+
 // calculateNestingLevel calculates the nesting level for structured content
 func (ca *ContextAnalyzer) calculateNestingLevel(content string, structType StructureType) int {
 	switch structType {
@@ -484,6 +520,17 @@ func (ca *ContextAnalyzer) detectPITypes(context string) []string {
 		piTypes = append(piTypes, "Medicare")
 	}
 
+	// Government services indicators
+	if strings.Contains(contextLower, "crn") || strings.Contains(contextLower, "centrelink") || strings.Contains(contextLower, "customer reference") {
+		piTypes = append(piTypes, "CRN")
+	}
+	if strings.Contains(contextLower, "dva") || strings.Contains(contextLower, "veteran") {
+		piTypes = append(piTypes, "DVA")
+	}
+	if strings.Contains(contextLower, "immicard") || strings.Contains(contextLower, "immi card") {
+		piTypes = append(piTypes, "ImmiCard")
+	}
+
 	// Email indicators
 	if strings.Contains(contextLower, "email") || strings.Contains(contextLower, "@") {
 		piTypes = append(piTypes, "Email")
@@ -328,6 +328,56 @@ func TestContextAnalyzer_AnalyzeStructure(t *testing.T) {
 	}
 }
 
+func TestContextAnalyzer_AnalyzeStructure_Fields(t *testing.T) {
+	analyzer := NewContextAnalyzer()
+
+	testCases := []struct {
+		name          string
+		content       string
+		match         string
+		expectedType  StructureType
+		expectedField string
+	}{
+		{
+			name:          "JSON key path",
+			content:       `{"customers": [{"name": "Jane"}, {"name": "John", "tfn": "123456782"}]}`,
+			match:         "123456782",
+			expectedType:  StructureJSON,
+			expectedField: "$.customers[1].tfn",
+		},
+		{
+			name:          "YAML key path",
+			content:       "payroll:\n  employees:\n    - tfn: 123 456 782\n",
+			match:         "123 456 782",
+			expectedType:  StructureYAML,
+			expectedField: "$.payroll.employees[0].tfn",
+		},
+		{
+			name:          "CSV column header",
+			content:       "name,medicare_no\nJane Citizen,2123456701\n",
+			match:         "2123456701",
+			expectedType:  StructureCSV,
+			expectedField: "medicare_no",
+		},
+		{
+			name:          "Match outside a value",
+			content:       "The SSN number 123-45-6789 belongs to user",
+			match:         "123-45-6789",
+			expectedType:  StructurePlainText,
+			expectedField: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := strings.Index(tc.content, tc.match)
+			result := analyzer.AnalyzeStructure(tc.content, start, start+len(tc.match))
+			assert.Equal(t, tc.expectedType, result.Type)
+			assert.Equal(t, tc.expectedField, result.Field)
+		})
+	}
+}
+
 func TestContextAnalyzer_CalculateContextWindow(t *testing.T) {
 	analyzer := NewContextAnalyzer()
 
@@ -55,6 +55,7 @@ const (
 	StructureHTML      StructureType = "html"
 	StructureSQL       StructureType = "sql"
 	StructureYAML      StructureType = "yaml"
+	StructureCSV       StructureType = "csv"
 	StructureCode      StructureType = "code"
 	StructurePlainText StructureType = "plain_text"
 	StructureURL       StructureType = "url"
@@ -65,6 +66,7 @@ type StructureAnalysis struct {
 	Type         StructureType `json:"type"`
 	NestingLevel int           `json:"nesting_level"`
 	ElementType  string        `json:"element_type,omitempty"`
+	Field        string        `json:"field,omitempty"` // Column header or key path, when the content parses as CSV, TSV, JSON or YAML
 }
 
 // SemanticAnalysis contains semantic context information
@@ -98,6 +98,19 @@ func (pm *PatternMatcher) compilePatterns() {
 		// Passport variations
 		"Passport", "passport", "Passport Number", "passport number",
 		"Passport No", "passport no",
+
+		// Centrelink CRN variations
+		"CRN", "crn", "Customer Reference Number", "customer reference number",
+		"Customer Reference No", "customer reference no", "Centrelink", "centrelink",
+
+		// DVA file number variations
+		"DVA", "dva", "DVA File Number", "dva file number", "DVA File No", "dva file no",
+		"DVA No", "dva no", "Veteran File Number", "veteran file number",
+		"Gold Card", "gold card", "White Card", "white card",
+
+		// ImmiCard variations
+		"ImmiCard", "immicard", "ImmiCard Number", "immicard number",
+		"ImmiCard No", "immicard no", "Immi Card", "immi card",
 	}
 
 	// Sort labels by length (longest first) to ensure proper matching precedence
@@ -167,7 +180,7 @@ func (pm *PatternMatcher) compilePatterns() {
 		`(?i)export\s+\w+\s*=`,                                  // Environment variable exports
 		`(?i)\[\w+\]`,                                           // INI section headers
 		`(?i)(default|fallback|initial|config|setting)_\w+\s*=`, // Default/fallback/config values (any case)
-		`(?i)\w+_(tfn|ssn|medicare|abn|bsb)_\w+\s*=`,            // Config patterns with PI type names
+		`(?i)\w+_(tfn|ssn|medicare|abn|bsb|crn|dva)_\w+\s*=`,    // Config patterns with PI type names
 		`\{[^}]*"[^"]+"\s*:\s*"[^"]+"\s*\}`,                     // JSON object pattern
 	}, "|"))
 
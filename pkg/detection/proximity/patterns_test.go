@@ -167,6 +167,16 @@ func TestPatternMatcher_PIContextLabels(t *testing.T) {
 		{"Passport Number", "Passport Number: A1234567", []string{"Passport Number"}},
 		{"Passport No", "Passport No: A1234567", []string{"Passport No"}},
 
+		// Government services variations
+		{"CRN colon", "CRN: 204567890X", []string{"CRN"}},
+		{"Customer Reference Number", "Customer Reference Number: 204567890X", []string{"Customer Reference Number"}},
+		{"Centrelink", "Centrelink 204567890X", []string{"Centrelink"}},
+		{"DVA File Number", "DVA File Number: NX123456", []string{"DVA File Number"}},
+		{"dva no lowercase", "dva no: NX123456", []string{"dva no"}},
+		{"Gold Card", "Gold Card: NX123456", []string{"Gold Card"}},
+		{"ImmiCard", "ImmiCard: EIK123456", []string{"ImmiCard"}},
+		{"ImmiCard Number", "ImmiCard Number = EIK123456", []string{"ImmiCard Number"}},
+
 		// Multiple labels
 		{"Multiple labels", "SSN: 123-45-6789, TFN: 987654321", []string{"SSN", "TFN"}},
 		{"Different formats", "SSN = 123-45-6789 and TFN: 987654321", []string{"SSN", "TFN"}},
@@ -0,0 +1,298 @@
+package detection
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultKeywordDistance is how many bytes either side of a match are
+// searched for a rule's keywords when the rule does not say
+const DefaultKeywordDistance = 50
+
+// RulesFile is a file of user-defined detection rules
+type RulesFile struct {
+	Version string `yaml:"version"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule is a user-defined pattern, detected alongside the built-in patterns.
+// Rules are matched before the built-ins, so a rule claims values that a
+// built-in pattern such as TFN would also match.
+type Rule struct {
+	ID              string         `yaml:"id"`
+	Description     string         `yaml:"description,omitempty"`
+	Type            PIType         `yaml:"type"`                       // Reported PI type, e.g. CUSTOMER_NUMBER
+	Pattern         string         `yaml:"pattern"`                    // Go regular expression
+	Keywords        []string       `yaml:"keywords,omitempty"`         // One must appear near the match, ignoring case
+	KeywordDistance int            `yaml:"keyword_distance,omitempty"` // Bytes either side searched for keywords (0 = DefaultKeywordDistance)
+	Validator       *RuleValidator `yaml:"validator,omitempty"`        // Checksum confirming matches (nil = unvalidated)
+	RiskLevel       RiskLevel      `yaml:"risk_level,omitempty"`       // Risk of findings (empty = from the type's risk weight)
+	Context         []RuleContext  `yaml:"context,omitempty"`          // Context modifiers; the first matching entry applies
+
+	File string `yaml:"-" json:"-"` // Rules file the rule was loaded from
+}
+
+// RuleValidator selects the checksum confirming a rule's matches
+type RuleValidator struct {
+	Algorithm string `yaml:"algorithm"`         // luhn, mod11, mod97 or weights
+	Weights   []int  `yaml:"weights,omitempty"` // Weight of each digit, check digit included; mod11 and weights
+	Modulus   int    `yaml:"modulus,omitempty"` // Divisor of the weighted sum; weights
+}
+
+// RuleContext overrides the context modifier of a rule's findings in files
+// whose name matches a glob, such as exports that always hold real data
+type RuleContext struct {
+	File     string  `yaml:"file"`     // Glob matched against the file name
+	Modifier float32 `yaml:"modifier"` // 0.1 treats the file as test data, 1.0 as production
+}
+
+// LoadRules reads and validates rules files. Rule IDs must be unique across
+// all of the files.
+func LoadRules(paths ...string) ([]Rule, error) {
+	var rules []Rule
+	seen := make(map[string]string)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules file: %w", err)
+		}
+
+		var file RulesFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+		}
+
+		for i := range file.Rules {
+			rule := &file.Rules[i]
+			rule.File = path
+			rule.Type = PIType(strings.ToUpper(string(rule.Type)))
+			rule.RiskLevel = RiskLevel(strings.ToUpper(string(rule.RiskLevel)))
+
+			if err := rule.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid rules file %s: rule %d: %w", path, i+1, err)
+			}
+			if other, ok := seen[rule.ID]; ok {
+				return nil, fmt.Errorf("invalid rules file %s: rule %s is already defined in %s", path, rule.ID, other)
+			}
+			seen[rule.ID] = path
+		}
+
+		rules = append(rules, file.Rules...)
+	}
+
+	return rules, nil
+}
+
+// Validate checks that the rule is complete and its pattern and validator
+// are usable
+func (r Rule) Validate() error {
+	if strings.TrimSpace(r.ID) == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.TrimSpace(string(r.Type)) == "" {
+		return fmt.Errorf("%s: type is required", r.ID)
+	}
+
+	if r.Pattern == "" {
+		return fmt.Errorf("%s: pattern is required", r.ID)
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("%s: pattern is invalid: %w", r.ID, err)
+	}
+	if re.MatchString("") {
+		return fmt.Errorf("%s: pattern matches empty text", r.ID)
+	}
+
+	if r.KeywordDistance < 0 {
+		return fmt.Errorf("%s: keyword_distance cannot be negative", r.ID)
+	}
+	for _, keyword := range r.Keywords {
+		if strings.TrimSpace(keyword) == "" {
+			return fmt.Errorf("%s: keywords cannot be empty", r.ID)
+		}
+	}
+
+	if r.Validator != nil {
+		if err := r.Validator.validate(); err != nil {
+			return fmt.Errorf("%s: validator %w", r.ID, err)
+		}
+	}
+
+	switch r.RiskLevel {
+	case "", RiskLevelCritical, RiskLevelHigh, RiskLevelMedium, RiskLevelLow:
+	default:
+		return fmt.Errorf("%s: invalid risk_level %s (expected CRITICAL, HIGH, MEDIUM or LOW)", r.ID, r.RiskLevel)
+	}
+
+	for _, c := range r.Context {
+		if _, err := filepath.Match(c.File, ""); err != nil || c.File == "" {
+			return fmt.Errorf("%s: invalid context file pattern %q", r.ID, c.File)
+		}
+		if c.Modifier < 0 {
+			return fmt.Errorf("%s: context modifier cannot be negative", r.ID)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the algorithm and the settings it needs
+func (v RuleValidator) validate() error {
+	switch v.Algorithm {
+	case validation.ChecksumLuhn, validation.ChecksumMod97:
+		if len(v.Weights) > 0 || v.Modulus != 0 {
+			return fmt.Errorf("%s takes no weights or modulus", v.Algorithm)
+		}
+	case validation.ChecksumMod11:
+		if len(v.Weights) == 0 {
+			return fmt.Errorf("mod11 requires weights")
+		}
+		if v.Modulus != 0 && v.Modulus != 11 {
+			return fmt.Errorf("mod11 has a modulus of 11; use weights for another modulus")
+		}
+	case validation.ChecksumWeights:
+		if len(v.Weights) == 0 {
+			return fmt.Errorf("weights requires weights")
+		}
+		if v.Modulus < 2 {
+			return fmt.Errorf("weights requires a modulus of at least 2")
+		}
+	default:
+		return fmt.Errorf("algorithm %q is unknown (expected luhn, mod11, mod97 or weights)", v.Algorithm)
+	}
+	return nil
+}
+
+// checksum returns the validator for a rule's matches, or nil when they are
+// not validated
+func (r Rule) checksum() validation.Validator {
+	if r.Validator == nil {
+		return nil
+	}
+	return &validation.ChecksumValidator{
+		PIType:    string(r.Type),
+		Algorithm: r.Validator.Algorithm,
+		Weights:   r.Validator.Weights,
+		Modulus:   r.Validator.Modulus,
+	}
+}
+
+// contextModifier returns the modifier of the first context entry matching
+// the file name
+func (r Rule) contextModifier(filename string) (float32, bool) {
+	for _, c := range r.Context {
+		if matched, _ := filepath.Match(c.File, filepath.Base(filename)); matched {
+			return c.Modifier, true
+		}
+	}
+	return 0, false
+}
+
+// ruleMatcher returns a matcher for the rule
+func ruleMatcher(rule *Rule) *regexMatcher {
+	m := &regexMatcher{
+		pattern:  rule.Pattern,
+		piType:   rule.Type,
+		rule:     rule,
+		checksum: rule.checksum(),
+	}
+
+	if len(rule.Keywords) > 0 {
+		distance := rule.KeywordDistance
+		if distance == 0 {
+			distance = DefaultKeywordDistance
+		}
+		m.near = keywordsNear(rule.Keywords, distance)
+	}
+
+	return m
+}
+
+// keywordsNear returns a function reporting whether one of the keywords
+// appears within distance bytes of a match, ignoring case
+func keywordsNear(keywords []string, distance int) func(content []byte, start, end int) bool {
+	lower := make([][]byte, len(keywords))
+	for i, keyword := range keywords {
+		lower[i] = bytes.ToLower([]byte(keyword))
+	}
+	return func(content []byte, start, end int) bool {
+		return nearKeyword(content, start, end, distance, lower)
+	}
+}
+
+// nearKeyword reports whether a keyword appears within distance bytes of the
+// match between start and end, or in the match itself
+func nearKeyword(content []byte, start, end, distance int, keywords [][]byte) bool {
+	from := start - distance
+	if from < 0 {
+		from = 0
+	}
+	to := end + distance
+	if to > len(content) {
+		to = len(content)
+	}
+
+	window := bytes.ToLower(content[from:to])
+	for _, keyword := range keywords {
+		if bytes.Contains(window, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSummary describes a pattern the detector matches, for listing
+type RuleSummary struct {
+	ID        string    // Rule ID; built-in patterns use their type in lower case
+	Type      PIType    // PI type reported
+	Source    string    // "built-in", or the rules file
+	Validator string    // Checksum confirming matches ("" = none)
+	Keywords  []string  // Keywords required near matches
+	RiskLevel RiskLevel // Risk of findings in ordinary files
+}
+
+// ListRules describes the patterns a detector built from config matches, in
+// the order they claim overlapping values
+func ListRules(config *Config) []RuleSummary {
+	d := NewDetectorWithConfig(config).(*detector)
+
+	summaries := make([]RuleSummary, 0, len(d.matchers))
+	for _, matcher := range d.matchers {
+		summary := RuleSummary{
+			ID:        strings.ToLower(string(matcher.Type())),
+			Type:      matcher.Type(),
+			Source:    "built-in",
+			RiskLevel: d.calculateRiskLevel(matcher.Type()),
+		}
+
+		rm, _ := matcher.(*regexMatcher)
+		if rm != nil && rm.rule != nil {
+			summary.ID = rm.rule.ID
+			summary.Source = rm.rule.File
+			if summary.Source == "" {
+				summary.Source = "config"
+			}
+			summary.Keywords = rm.rule.Keywords
+			if rm.rule.RiskLevel != "" {
+				summary.RiskLevel = rm.rule.RiskLevel
+			}
+			if rm.rule.Validator != nil {
+				summary.Validator = rm.rule.Validator.Algorithm
+			}
+		} else if _, ok := d.validators.Get(string(matcher.Type())); ok {
+			summary.Validator = strings.ToLower(string(matcher.Type()))
+		}
+
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
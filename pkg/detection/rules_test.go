@@ -0,0 +1,180 @@
+package detection
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const memberRules = `version: "1"
+rules:
+  - id: member-number
+    description: Fund member number
+    type: MEMBER_NUMBER
+    pattern: '\b\d{9}\b'
+    keywords: [member]
+    keyword_distance: 20
+    validator:
+      algorithm: mod11
+      weights: [1, 4, 3, 7, 5, 8, 6, 9, 10]
+    risk_level: high
+    context:
+      - file: "*.csv"
+        modifier: 1.0
+      - file: "seed_*.sql"
+        modifier: 0.1
+  - id: customer-card
+    type: customer_card
+    pattern: '\bCC\d{8}\b'
+    validator:
+      algorithm: luhn
+`
+
+// writeRules writes a rules file to a temporary directory
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadRules(t *testing.T) {
+	path := writeRules(t, memberRules)
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	assert.Equal(t, "member-number", rules[0].ID)
+	assert.Equal(t, PIType("MEMBER_NUMBER"), rules[0].Type)
+	assert.Equal(t, RiskLevelHigh, rules[0].RiskLevel)
+	assert.Equal(t, []int{1, 4, 3, 7, 5, 8, 6, 9, 10}, rules[0].Validator.Weights)
+	assert.Equal(t, path, rules[0].File)
+	assert.Equal(t, PIType("CUSTOMER_CARD"), rules[1].Type, "types are matched in upper case")
+	assert.Equal(t, "luhn", rules[1].Validator.Algorithm)
+
+	_, err = LoadRules(path, writeRules(t, memberRules))
+	assert.ErrorContains(t, err, "member-number is already defined")
+
+	_, err = LoadRules(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read rules file")
+}
+
+func TestRule_Validate(t *testing.T) {
+	valid := Rule{ID: "member", Type: "MEMBER", Pattern: `\d{9}`}
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name    string
+		modify  func(r *Rule)
+		wantErr string
+	}{
+		{"missing id", func(r *Rule) { r.ID = "" }, "id is required"},
+		{"missing type", func(r *Rule) { r.Type = "" }, "type is required"},
+		{"missing pattern", func(r *Rule) { r.Pattern = "" }, "pattern is required"},
+		{"invalid pattern", func(r *Rule) { r.Pattern = `[0-9` }, "pattern is invalid"},
+		{"empty match", func(r *Rule) { r.Pattern = `\d*` }, "pattern matches empty text"},
+		{"negative distance", func(r *Rule) { r.KeywordDistance = -1 }, "keyword_distance cannot be negative"},
+		{"empty keyword", func(r *Rule) { r.Keywords = []string{" "} }, "keywords cannot be empty"},
+		{"unknown algorithm", func(r *Rule) { r.Validator = &RuleValidator{Algorithm: "crc"} }, `algorithm "crc" is unknown`},
+		{"mod11 without weights", func(r *Rule) { r.Validator = &RuleValidator{Algorithm: "mod11"} }, "mod11 requires weights"},
+		{"weights without modulus", func(r *Rule) { r.Validator = &RuleValidator{Algorithm: "weights", Weights: []int{1}} }, "modulus of at least 2"},
+		{"luhn with weights", func(r *Rule) { r.Validator = &RuleValidator{Algorithm: "luhn", Weights: []int{1}} }, "takes no weights"},
+		{"invalid risk level", func(r *Rule) { r.RiskLevel = "SEVERE" }, "invalid risk_level"},
+		{"invalid context glob", func(r *Rule) { r.Context = []RuleContext{{File: "[", Modifier: 1}} }, "invalid context file pattern"},
+		{"negative modifier", func(r *Rule) { r.Context = []RuleContext{{File: "*.sql", Modifier: -1}} }, "modifier cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := valid
+			tt.modify(&rule)
+			assert.ErrorContains(t, rule.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestDetector_Rules(t *testing.T) {
+	rules, err := LoadRules(writeRules(t, memberRules))
+	require.NoError(t, err)
+
+	config := DefaultConfig()
+	config.Rules = rules
+	detector := NewDetectorWithConfig(config)
+	ctx := context.Background()
+
+	t.Run("rule claims values near its keywords", func(t *testing.T) {
+		findings, err := detector.Detect(ctx, []byte(`memberID := "123456782"`), "members.go")
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+
+		assert.Equal(t, PIType("MEMBER_NUMBER"), findings[0].Type)
+		assert.True(t, findings[0].Validated)
+		assert.Equal(t, float32(0.95), findings[0].Confidence)
+		assert.Equal(t, RiskLevelHigh, findings[0].RiskLevel)
+	})
+
+	t.Run("built-ins match values without keywords", func(t *testing.T) {
+		findings, err := detector.Detect(ctx, []byte(`taxFileNumber := "123456782"`), "members.go")
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, PITypeTFN, findings[0].Type)
+	})
+
+	t.Run("keywords beyond the distance are ignored", func(t *testing.T) {
+		content := `member := lookup(ctx)` + "\n\n\n" + `value := "123456782"`
+		findings, err := detector.Detect(ctx, []byte(content), "members.go")
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, PITypeTFN, findings[0].Type)
+	})
+
+	t.Run("rule validator replaces the type validator", func(t *testing.T) {
+		findings, err := detector.Detect(ctx, []byte(`card := "CC12345674"`+"\n"+`other := "CC12345675"`), "cards.go")
+		require.NoError(t, err)
+		require.Len(t, findings, 2)
+
+		assert.Equal(t, PIType("CUSTOMER_CARD"), findings[0].Type)
+		assert.True(t, findings[0].Validated)
+		assert.False(t, findings[1].Validated)
+		assert.Equal(t, "Checksum validation failed", findings[1].ValidationError)
+	})
+
+	t.Run("context modifiers apply by file name", func(t *testing.T) {
+		content := []byte(`member_no,"123456782"`)
+
+		findings, err := detector.Detect(ctx, content, "seed_members.sql")
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, float32(0.1), findings[0].ContextModifier)
+
+		findings, err = detector.Detect(ctx, content, "members.csv")
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, float32(1.0), findings[0].ContextModifier)
+	})
+}
+
+func TestListRules(t *testing.T) {
+	rules, err := LoadRules(writeRules(t, memberRules))
+	require.NoError(t, err)
+
+	config := DefaultConfig()
+	config.Rules = rules
+	summaries := ListRules(config)
+	require.Len(t, summaries, len(rules)+len(NewDetector().(*detector).matchers))
+
+	assert.Equal(t, "member-number", summaries[0].ID)
+	assert.Equal(t, rules[0].File, summaries[0].Source)
+	assert.Equal(t, "mod11", summaries[0].Validator)
+	assert.Equal(t, []string{"member"}, summaries[0].Keywords)
+	assert.Equal(t, RiskLevelHigh, summaries[0].RiskLevel)
+
+	// Built-ins follow in priority order
+	assert.Equal(t, RuleSummary{ID: "ihi", Type: PITypeIHI, Source: "built-in", Validator: "ihi", RiskLevel: RiskLevelHigh}, summaries[2])
+	assert.Equal(t, "", summaries[len(summaries)-1].Validator)
+}
@@ -2,6 +2,8 @@ package detection
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -23,8 +25,25 @@ const (
 	PITypePassport      PIType = "PASSPORT"
 	PITypeAccount       PIType = "ACCOUNT"
 	PITypeIP            PIType = "IP_ADDRESS"
+	PITypeIHI           PIType = "IHI"             // Individual Healthcare Identifier
+	PITypeHPII          PIType = "HPI_I"           // Healthcare Provider Identifier - Individual
+	PITypeHPIO          PIType = "HPI_O"           // Healthcare Provider Identifier - Organisation
+	PITypeCRN           PIType = "CRN"             // Centrelink Customer Reference Number
+	PITypeDVA           PIType = "DVA_FILE_NUMBER" // Department of Veterans' Affairs file number
+	PITypeImmiCard      PIType = "IMMICARD"        // ImmiCard number
 )
 
+// PITypes returns the built-in PI types. User-defined rules may report
+// others.
+func PITypes() []PIType {
+	return []PIType{
+		PITypeTFN, PITypeMedicare, PITypeABN, PITypeACN, PITypeBSB,
+		PITypeEmail, PITypePhone, PITypeName, PITypeAddress, PITypeCreditCard,
+		PITypeDriverLicense, PITypePassport, PITypeAccount, PITypeIP,
+		PITypeIHI, PITypeHPII, PITypeHPIO, PITypeCRN, PITypeDVA, PITypeImmiCard,
+	}
+}
+
 // RiskLevel represents the severity of a finding
 type RiskLevel string
 
@@ -35,6 +54,22 @@ const (
 	RiskLevelLow      RiskLevel = "LOW"
 )
 
+// Severity ranks risk levels from 1 for low to 4 for critical; unknown levels
+// rank 0
+func (r RiskLevel) Severity() int {
+	switch r {
+	case RiskLevelCritical:
+		return 4
+	case RiskLevelHigh:
+		return 3
+	case RiskLevelMedium:
+		return 2
+	case RiskLevelLow:
+		return 1
+	}
+	return 0
+}
+
 // Finding represents a detected PI instance
 type Finding struct {
 	// Core fields
@@ -61,8 +96,106 @@ type Finding struct {
 	// Metadata
 	DetectedAt   time.Time `json:"detected_at"`
 	DetectorName string    `json:"detector_name"`
+
+	// History scans only
+	Commit *CommitInfo `json:"commit,omitempty"`
+
+	// Office documents and PDFs only; Line and Column are then positions in
+	// the extracted text, and reports show the location instead
+	Location *DocumentLocation `json:"location,omitempty"`
+
+	// CSV, TSV, JSON and YAML files only: the column header or key path of
+	// the value, such as $.customers[3].tfn, and for a CSV column of values
+	// reported as one finding, the number of rows holding them
+	Field       string `json:"field,omitempty"`
+	RecordCount int    `json:"record_count,omitempty"`
+
+	// Values found by decoding base64, URL-encoded or hex text only: the
+	// encodings undone, outermost first, and the length of the encoded text
+	// starting at Line and Column
+	EncodingChain []string `json:"encoding_chain,omitempty"`
+	EncodedLength int      `json:"encoded_length,omitempty"`
+
+	// Baseline comparison only
+	BaselineState BaselineState `json:"baseline_state,omitempty"`
+	Fingerprint   string        `json:"fingerprint,omitempty"`
+
+	// Set when the finding has been accepted by an inline comment or allowlist entry
+	Suppression *Suppression `json:"suppression,omitempty"`
+}
+
+// DocumentLocation places a finding in a spreadsheet, word processing
+// document, presentation or PDF, whose extracted text has no meaningful
+// lines and columns. Paragraphs are numbered from 1 among those with text.
+type DocumentLocation struct {
+	Sheet     string `json:"sheet,omitempty"`
+	Cell      string `json:"cell,omitempty"`
+	Slide     int    `json:"slide,omitempty"`
+	Page      int    `json:"page,omitempty"`
+	Paragraph int    `json:"paragraph,omitempty"`
 }
 
+// String formats the location as a cell reference such as Customers!B2, or
+// as the page or slide and paragraph, such as "page 3, paragraph 2"
+func (l DocumentLocation) String() string {
+	if l.Cell != "" {
+		sheet := l.Sheet
+		if strings.ContainsAny(sheet, " !'-") {
+			sheet = "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+		}
+		return sheet + "!" + l.Cell
+	}
+
+	var parts []string
+	if l.Page > 0 {
+		parts = append(parts, fmt.Sprintf("page %d", l.Page))
+	}
+	if l.Slide > 0 {
+		parts = append(parts, fmt.Sprintf("slide %d", l.Slide))
+	}
+	if l.Paragraph > 0 {
+		parts = append(parts, fmt.Sprintf("paragraph %d", l.Paragraph))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Suppression records why a finding was accepted. Suppressed findings are
+// still reported, marked as suppressed.
+type Suppression struct {
+	Source        string     `json:"source"` // SuppressionInline or SuppressionAllowlist
+	Justification string     `json:"justification,omitempty"`
+	Expires       *time.Time `json:"expires,omitempty"`
+}
+
+// Suppression sources
+const (
+	SuppressionInline    = "inline"
+	SuppressionAllowlist = "allowlist"
+)
+
+// Suppressed reports whether the finding has been accepted
+func (f Finding) Suppressed() bool {
+	return f.Suppression != nil
+}
+
+// Records returns the number of records a finding stands for: the record
+// count of a collapsed CSV column, otherwise one
+func (f Finding) Records() int {
+	if f.RecordCount > 1 {
+		return f.RecordCount
+	}
+	return 1
+}
+
+// BaselineState describes a finding relative to a baseline of accepted findings
+type BaselineState string
+
+const (
+	BaselineStateNew       BaselineState = "new"       // Not in the baseline
+	BaselineStateUnchanged BaselineState = "unchanged" // Present in the baseline and this scan
+	BaselineStateAbsent    BaselineState = "absent"    // In the baseline but no longer detected
+)
+
 // Detector is the interface for PI detection engines
 type Detector interface {
 	// Detect analyzes content and returns findings
@@ -135,6 +268,7 @@ type Config struct {
 	EnableRegex    bool     `yaml:"enable_regex"`
 	EnableGitleaks bool     `yaml:"enable_gitleaks"`
 	CustomPatterns []string `yaml:"custom_patterns"`
+	Rules          []Rule   `yaml:"rules"` // User-defined patterns, matched before the built-ins
 
 	// Validation
 	EnableValidation        bool `yaml:"enable_validation"`
@@ -150,8 +284,12 @@ type Config struct {
 	MinConfidenceThreshold float32 `yaml:"min_confidence_threshold"`
 	ContextConfidenceBoost float32 `yaml:"context_confidence_boost"`
 
+	// Per-type overrides; types without an entry use the settings above
+	TypeSettings map[PIType]TypeSettings `yaml:"type_settings"`
+
 	// Risk scoring
 	RiskWeights     map[PIType]int `yaml:"risk_weights"`
+	RiskThresholds  RiskThresholds `yaml:"risk_thresholds"`
 	ProximityWindow int            `yaml:"proximity_window"`
 
 	// Performance
@@ -160,6 +298,29 @@ type Config struct {
 	EnableCaching bool  `yaml:"enable_cache"`
 }
 
+// TypeSettings overrides detection behaviour for a single PI type
+type TypeSettings struct {
+	Enabled       bool    `yaml:"enabled"`
+	StrictMode    bool    `yaml:"strict_mode"`    // Drop findings that fail checksum validation
+	MinConfidence float32 `yaml:"min_confidence"` // Overrides MinConfidenceThreshold when > 0
+	CustomPattern string  `yaml:"custom_pattern"` // Replaces the built-in pattern when set
+}
+
+// RiskThresholds maps a normalised risk weight (weight / 100) to a risk level
+type RiskThresholds struct {
+	Critical float64 `yaml:"critical"` // 0 disables the critical level
+	High     float64 `yaml:"high"`
+	Medium   float64 `yaml:"medium"`
+}
+
+// DefaultRiskThresholds returns the default risk level thresholds
+func DefaultRiskThresholds() RiskThresholds {
+	return RiskThresholds{
+		High:   0.9,
+		Medium: 0.6,
+	}
+}
+
 // DefaultConfig returns the default detection configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -233,18 +394,25 @@ func DefaultConfig() *Config {
 		RiskWeights: map[PIType]int{
 			PITypeTFN:        100,
 			PITypeMedicare:   90,
+			PITypeIHI:        90,
 			PITypeCreditCard: 90,
 			PITypePassport:   80,
+			PITypeCRN:        80,
+			PITypeImmiCard:   80,
+			PITypeDVA:        70,
 			PITypeABN:        60,
 			PITypeACN:        60,
+			PITypeHPII:       60,
 			PITypeBSB:        50,
 			PITypeAccount:    50,
+			PITypeHPIO:       40,
 			PITypeName:       40,
 			PITypeAddress:    40,
 			PITypePhone:      30,
 			PITypeEmail:      20,
 			PITypeIP:         10,
 		},
+		RiskThresholds: DefaultRiskThresholds(),
 
 		ProximityWindow: 5,
 		MaxFileSize:     10 * 1024 * 1024, // 10MB
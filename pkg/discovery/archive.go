@@ -0,0 +1,305 @@
+package discovery
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/extract"
+)
+
+// ArchiveSeparator joins an archive's path to the path of a file inside it,
+// as in data/export.zip!/customers.csv
+const ArchiveSeparator = "!/"
+
+// ErrArchiveLimit is returned when an archive exceeds its total size or entry
+// limits. Files read before the limit was reached have already been passed on.
+var ErrArchiveLimit = errors.New("archive limit exceeded")
+
+// ArchiveLimits bounds archive expansion, so crafted archives such as zip
+// bombs cannot exhaust memory or time
+type ArchiveLimits struct {
+	MaxDepth     int   // Levels of archives expanded; 1 expands no nested archives
+	MaxEntrySize int64 // Largest decompressed file read; larger files are skipped
+	MaxTotalSize int64 // Decompressed bytes read from an archive, nested archives included
+	MaxEntries   int   // Entries read from an archive, nested archives included
+}
+
+// DefaultArchiveLimits returns the default archive expansion limits
+func DefaultArchiveLimits() ArchiveLimits {
+	return ArchiveLimits{
+		MaxDepth:     3,
+		MaxEntrySize: 10 * 1024 * 1024,  // 10MB
+		MaxTotalSize: 100 * 1024 * 1024, // 100MB
+		MaxEntries:   10000,
+	}
+}
+
+// archiveFormat returns the archive format of a file name: "zip", "tar" or
+// "tar.gz", or "" if it is not an archive
+func archiveFormat(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return "tar.gz"
+	case strings.HasSuffix(name, ".tar"):
+		return "tar"
+	}
+
+	switch path.Ext(name) {
+	case ".zip", ".jar", ".war", ".ear":
+		return "zip"
+	}
+	return ""
+}
+
+// ExpandArchive reads the files inside an archive returned by DiscoverFiles
+// and calls fn with the virtual path and content of each one that passes the
+// discovery filters, matched on its path inside the archive. Nested archives
+// are expanded up to the depth limit; Office documents and PDFs are passed on
+// undecoded when ExtractDocuments is on. Files over the entry size limit are
+// skipped and their virtual paths returned. Reading stops with
+// ErrArchiveLimit once the total size or entry limits are exceeded, and with
+// fn's error if it fails.
+func (fd *FileDiscovery) ExpandArchive(ctx context.Context, archive FileResult, fn func(path string, content []byte) error) ([]string, error) {
+	limits := fd.config.Archives
+	if limits == (ArchiveLimits{}) {
+		limits = DefaultArchiveLimits()
+	}
+
+	x := &archiveExpander{
+		fd:        fd,
+		ctx:       ctx,
+		limits:    limits,
+		remaining: limits.MaxTotalSize,
+		fn:        fn,
+	}
+
+	format := archiveFormat(archive.Path)
+	if format == "zip" {
+		r, err := zip.OpenReader(archive.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive %s: %w", archive.Path, err)
+		}
+		defer r.Close()
+		err = x.expandZip(&r.Reader, archive.Path, 1)
+		return x.skipped, err
+	}
+
+	f, err := os.Open(archive.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", archive.Path, err)
+	}
+	defer f.Close()
+	err = x.expandTar(f, format, archive.Path, 1)
+	return x.skipped, err
+}
+
+// archiveExpander walks one top-level archive and the archives nested in it,
+// sharing the size and entry limits between them
+type archiveExpander struct {
+	fd        *FileDiscovery
+	ctx       context.Context
+	limits    ArchiveLimits
+	remaining int64 // Decompressed bytes left in the total size limit
+	entries   int
+	skipped   []string
+	fn        func(path string, content []byte) error
+}
+
+// expandZip reads the files of a zip archive at the given nesting depth
+func (x *archiveExpander) expandZip(r *zip.Reader, archivePath string, depth int) error {
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if err := x.next(); err != nil {
+			return err
+		}
+
+		name := archivePath + ArchiveSeparator + strings.TrimPrefix(f.Name, "/")
+		if !x.wanted(f.Name) {
+			continue
+		}
+		// The header size is only a hint; reading is limited regardless
+		if x.limits.MaxEntrySize > 0 && f.UncompressedSize64 > uint64(x.limits.MaxEntrySize) {
+			x.skipped = append(x.skipped, name)
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		content, complete, err := x.read(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if !complete {
+			x.skipped = append(x.skipped, name)
+			continue
+		}
+
+		if err := x.handle(name, f.Name, content, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandTar reads the regular files of a tar or tar.gz stream at the given
+// nesting depth
+func (x *archiveExpander) expandTar(r io.Reader, format, archivePath string, depth int) error {
+	if format == "tar.gz" {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archivePath, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// Count everything decompressed, including the parts of skipped files
+	// the tar reader discards, against the total size limit
+	tr := tar.NewReader(&countingReader{r: r, x: x})
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archivePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := x.next(); err != nil {
+			return err
+		}
+
+		name := archivePath + ArchiveSeparator + strings.TrimPrefix(header.Name, "/")
+		if !x.wanted(header.Name) {
+			continue
+		}
+		if x.limits.MaxEntrySize > 0 && header.Size > x.limits.MaxEntrySize {
+			x.skipped = append(x.skipped, name)
+			continue
+		}
+
+		content, complete, err := x.read(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if !complete {
+			x.skipped = append(x.skipped, name)
+			continue
+		}
+
+		if err := x.handle(name, header.Name, content, depth); err != nil {
+			return err
+		}
+	}
+}
+
+// handle expands a nested archive, or passes a text file or document on
+func (x *archiveExpander) handle(name, entryPath string, content []byte, depth int) error {
+	format := archiveFormat(entryPath)
+	if format == "" {
+		if x.fd.config.ExcludeBinary && isBinaryContent(content) && !x.document(entryPath) {
+			return nil
+		}
+		return x.fn(name, content)
+	}
+
+	if depth >= x.limits.MaxDepth {
+		return nil
+	}
+	if format == "zip" {
+		r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return x.expandZip(r, name, depth+1)
+	}
+	return x.expandTar(bytes.NewReader(content), format, name, depth+1)
+}
+
+// next counts an entry against the entry limit and checks for cancellation
+func (x *archiveExpander) next() error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	x.entries++
+	if x.limits.MaxEntries > 0 && x.entries > x.limits.MaxEntries {
+		return fmt.Errorf("%w: more than %d entries", ErrArchiveLimit, x.limits.MaxEntries)
+	}
+	return nil
+}
+
+// wanted reports whether a file inside an archive passes the discovery
+// filters. Nested archives and documents are wanted unless excluded, as at the
+// top level.
+func (x *archiveExpander) wanted(entryPath string) bool {
+	entryPath = strings.TrimPrefix(entryPath, "/")
+	if archiveFormat(entryPath) != "" || x.document(entryPath) {
+		return !x.fd.isExcluded(entryPath)
+	}
+	return x.fd.MatchesPath(entryPath)
+}
+
+// document reports whether text is extracted from a file inside an archive
+func (x *archiveExpander) document(entryPath string) bool {
+	return x.fd.config.ExtractDocuments && extract.Supported(entryPath)
+}
+
+// read reads an entry up to the entry size limit, reporting whether the
+// whole entry fit. Bytes read count against the total size limit.
+func (x *archiveExpander) read(r io.Reader) ([]byte, bool, error) {
+	if x.limits.MaxEntrySize <= 0 {
+		content, err := io.ReadAll(x.count(r))
+		return content, true, err
+	}
+
+	content, err := io.ReadAll(io.LimitReader(x.count(r), x.limits.MaxEntrySize+1))
+	if err != nil {
+		return nil, false, err
+	}
+	return content, int64(len(content)) <= x.limits.MaxEntrySize, nil
+}
+
+// count wraps a zip entry reader so its bytes count against the total size
+// limit; tar streams are counted as a whole by expandTar
+func (x *archiveExpander) count(r io.Reader) io.Reader {
+	if _, ok := r.(*tar.Reader); ok {
+		return r
+	}
+	return &countingReader{r: r, x: x}
+}
+
+// countingReader fails with ErrArchiveLimit once the expander's total size
+// limit is spent
+type countingReader struct {
+	r io.Reader
+	x *archiveExpander
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if c.x.limits.MaxTotalSize <= 0 {
+		return n, err
+	}
+	c.x.remaining -= int64(n)
+	if c.x.remaining < 0 {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrArchiveLimit, c.x.limits.MaxTotalSize)
+	}
+	return n, err
+}
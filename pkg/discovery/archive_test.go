@@ -0,0 +1,215 @@
+package discovery
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveFile struct {
+	name    string
+	content []byte
+}
+
+func zipArchive(t *testing.T, files ...archiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := w.Create(f.name)
+		require.NoError(t, err)
+		_, err = fw.Write(f.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files ...archiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for _, f := range files {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}))
+		_, err := w.Write(f.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func expandAll(t *testing.T, fd *FileDiscovery, archive FileResult) (map[string]string, []string, error) {
+	t.Helper()
+	files := make(map[string]string)
+	skipped, err := fd.ExpandArchive(context.Background(), archive, func(path string, content []byte) error {
+		files[path] = string(content)
+		return nil
+	})
+	return files, skipped, err
+}
+
+func TestArchiveFormat(t *testing.T) {
+	tests := map[string]string{
+		"export.zip":         "zip",
+		"lib/app.JAR":        "zip",
+		"deploy/site.war":    "zip",
+		"backup.tar":         "tar",
+		"backup.tar.gz":      "tar.gz",
+		"backup.tgz":         "tar.gz",
+		"customers.csv":      "",
+		"archive.gz":         "",
+		"zip/customers.json": "",
+	}
+	for name, expected := range tests {
+		assert.Equal(t, expected, archiveFormat(name), name)
+	}
+}
+
+func TestDiscoverFiles_Archives(t *testing.T) {
+	root := t.TempDir()
+	archive := zipArchive(t, archiveFile{"customers.csv", []byte("name,tfn\n")})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "data"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data", "export.zip"), archive, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "lib.jar"), archive, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+
+	config := DefaultConfig()
+	results, err := NewFileDiscovery(config).DiscoverFiles(context.Background(), root)
+	require.NoError(t, err)
+
+	// Archives are found despite the include patterns, unless excluded
+	byName := make(map[string]FileResult)
+	for _, result := range results {
+		rel, _ := filepath.Rel(root, result.Path)
+		byName[filepath.ToSlash(rel)] = result
+	}
+	require.Contains(t, byName, "data/export.zip")
+	assert.True(t, byName["data/export.zip"].IsArchive)
+	assert.NotContains(t, byName, "vendor/lib.jar")
+	assert.False(t, byName["main.go"].IsArchive)
+
+	// Without expansion archives are binary files, excluded as before
+	config.ExpandArchives = false
+	results, err = NewFileDiscovery(config).DiscoverFiles(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, filepath.Join(root, "main.go"), results[0].Path)
+}
+
+func TestDiscoverFiles_Documents(t *testing.T) {
+	root := t.TempDir()
+	workbook := zipArchive(t, archiveFile{"xl/workbook.xml", []byte("<workbook/>")})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "reports"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "reports", "Q3.xlsx"), workbook, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "node_modules", "guide.pdf"), []byte("%PDF-1.4\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "export.zip"), zipArchive(t, archiveFile{"letters/welcome.docx", workbook}), 0644))
+
+	config := DefaultConfig()
+	fd := NewFileDiscovery(config)
+	results, err := fd.DiscoverFiles(context.Background(), root)
+	require.NoError(t, err)
+
+	// Documents are found despite the include patterns, unless excluded
+	require.Len(t, results, 2)
+	byName := make(map[string]FileResult)
+	for _, result := range results {
+		byName[filepath.Base(result.Path)] = result
+	}
+	assert.True(t, byName["Q3.xlsx"].IsDocument)
+	assert.False(t, byName["export.zip"].IsDocument)
+
+	// Documents inside archives are passed on despite being binary
+	files, _, err := expandAll(t, fd, byName["export.zip"])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{filepath.Join(root, "export.zip") + "!/letters/welcome.docx": string(workbook)}, files)
+
+	// Without extraction documents are binary files, excluded as before
+	config.ExtractDocuments = false
+	fd = NewFileDiscovery(config)
+	results, err = fd.DiscoverFiles(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	files, _, err = expandAll(t, fd, results[0])
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestExpandArchive(t *testing.T) {
+	root := t.TempDir()
+	inner := tarGzArchive(t,
+		archiveFile{"notes/customers.txt", []byte("jane@example.com\n")},
+		archiveFile{"logo.png", []byte("\x89PNG\r\n\x1a\n\x00\x00")},
+	)
+	path := filepath.Join(root, "export.zip")
+	require.NoError(t, os.WriteFile(path, zipArchive(t,
+		archiveFile{"customers.csv", []byte("name,tfn\nJane,123456782\n")},
+		archiveFile{"backup/old.tar.gz", inner},
+		archiveFile{"node_modules/lib/index.js", []byte("var tfn = '123456782'\n")},
+		archiveFile{"big.csv", []byte(strings.Repeat("x", 2048))},
+	), 0644))
+
+	config := DefaultConfig()
+	config.IncludePatterns = []string{"**/*.csv", "**/*.txt", "**/*.js", "**/*.png"}
+	config.Archives.MaxEntrySize = 1024
+	files, skipped, err := expandAll(t, NewFileDiscovery(config), FileResult{Path: path})
+	require.NoError(t, err)
+
+	// Nested archives are expanded; excluded, binary and oversized files are not passed on
+	assert.Equal(t, map[string]string{
+		path + "!/customers.csv":                          "name,tfn\nJane,123456782\n",
+		path + "!/backup/old.tar.gz!/notes/customers.txt": "jane@example.com\n",
+	}, files)
+	assert.Equal(t, []string{path + "!/big.csv"}, skipped)
+
+	// Nested archives beyond the depth limit are left alone
+	config.Archives.MaxDepth = 1
+	files, _, err = expandAll(t, NewFileDiscovery(config), FileResult{Path: path})
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Contains(t, files, path+"!/customers.csv")
+}
+
+func TestExpandArchive_Limits(t *testing.T) {
+	root := t.TempDir()
+
+	// A small archive that decompresses to far more than the total limit
+	bomb := filepath.Join(root, "bomb.tar.gz")
+	var entries []archiveFile
+	for i := 0; i < 8; i++ {
+		entries = append(entries, archiveFile{strings.Repeat("a", i+1) + ".txt", bytes.Repeat([]byte("0"), 512*1024)})
+	}
+	require.NoError(t, os.WriteFile(bomb, tarGzArchive(t, entries...), 0644))
+
+	config := DefaultConfig()
+	config.IncludePatterns = []string{"**/*.txt"}
+	config.Archives.MaxTotalSize = 1024 * 1024
+	files, _, err := expandAll(t, NewFileDiscovery(config), FileResult{Path: bomb})
+	assert.ErrorIs(t, err, ErrArchiveLimit)
+	assert.Len(t, files, 1, "files read before the limit are still passed on")
+
+	// Entry counts are limited too
+	config.Archives = DefaultArchiveLimits()
+	config.Archives.MaxEntries = 3
+	files, _, err = expandAll(t, NewFileDiscovery(config), FileResult{Path: bomb})
+	assert.ErrorIs(t, err, ErrArchiveLimit)
+	assert.Len(t, files, 3)
+
+	// Corrupt archives are reported
+	corrupt := filepath.Join(root, "corrupt.zip")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not a zip"), 0644))
+	_, _, err = expandAll(t, NewFileDiscovery(config), FileResult{Path: corrupt})
+	assert.Error(t, err)
+}
@@ -9,15 +9,18 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/MacAttak/pi-scanner/pkg/extract"
 	"github.com/bmatcuk/doublestar/v4"
 )
 
 // FileResult represents a discovered file with metadata
 type FileResult struct {
-	Path     string
-	Size     int64
-	IsBinary bool
-	IsHidden bool
+	Path       string
+	Size       int64
+	IsBinary   bool
+	IsHidden   bool
+	IsArchive  bool // Set when ExpandArchives is on; read its files with ExpandArchive
+	IsDocument bool // Set when ExtractDocuments is on; read its text with extract.Extract
 }
 
 // Config holds file discovery configuration
@@ -39,11 +42,23 @@ type Config struct {
 
 	// Follow symbolic links
 	FollowSymlinks bool
+
+	// Include zip, jar, tar and tar.gz archives regardless of the include
+	// patterns, so the files inside them can be scanned with ExpandArchive
+	ExpandArchives bool
+
+	// Limits on archive expansion
+	Archives ArchiveLimits
+
+	// Include Office documents and PDFs regardless of the include patterns,
+	// so their text can be scanned with extract.Extract
+	ExtractDocuments bool
 }
 
 // FileDiscovery handles file discovery with filtering
 type FileDiscovery struct {
-	config Config
+	config    Config
+	oversized []FileResult
 }
 
 // NewFileDiscovery creates a new file discovery instance
@@ -64,7 +79,8 @@ func DefaultConfig() Config {
 			"**/*.ps1", "**/*.bat", "**/*.cmd", "**/*.sql", "**/*.yaml",
 			"**/*.yml", "**/*.json", "**/*.xml", "**/*.toml", "**/*.ini",
 			"**/*.cfg", "**/*.conf", "**/*.config", "**/*.env", "**/*.properties",
-			"**/*.md", "**/*.txt", "**/*.log", "**/*.dockerfile", "**/Dockerfile",
+			"**/*.md", "**/*.txt", "**/*.log", "**/*.csv", "**/*.tsv",
+			"**/*.dockerfile", "**/Dockerfile",
 			"**/Makefile", "**/*.mk", "**/*.gradle", "**/*.maven", "**/*.pom",
 		},
 		ExcludePatterns: []string{
@@ -77,16 +93,20 @@ func DefaultConfig() Config {
 			"**/*.min.js", "**/*.min.css", "**/*.bundle.*",
 			"**/.DS_Store", "**/Thumbs.db", "**/*.tmp", "**/*.temp",
 		},
-		ExcludeBinary:  true,
-		MaxFileSize:    10 * 1024 * 1024, // 10MB
-		IncludeHidden:  true,             // Include hidden files like .env
-		FollowSymlinks: false,
+		ExcludeBinary:    true,
+		MaxFileSize:      10 * 1024 * 1024, // 10MB
+		IncludeHidden:    true,             // Include hidden files like .env
+		FollowSymlinks:   false,
+		ExpandArchives:   true,
+		Archives:         DefaultArchiveLimits(),
+		ExtractDocuments: true,
 	}
 }
 
 // DiscoverFiles discovers all files in the given directory matching the configuration
 func (fd *FileDiscovery) DiscoverFiles(ctx context.Context, rootPath string) ([]FileResult, error) {
 	var results []FileResult
+	fd.oversized = nil
 
 	// Check if root path exists
 	if _, err := os.Stat(rootPath); os.IsNotExist(err) {
@@ -123,7 +143,41 @@ func (fd *FileDiscovery) DiscoverFiles(ctx context.Context, rootPath string) ([]
 		}
 
 		// Check if file should be included
-		if fd.shouldIncludeFile(path, info, rootPath) {
+		isArchive := fd.config.ExpandArchives && archiveFormat(path) != ""
+		isDocument := fd.config.ExtractDocuments && extract.Supported(path)
+		if ((isArchive || isDocument) && fd.shouldIncludeContainer(path, rootPath)) || fd.shouldIncludeFile(path, rootPath) {
+			// Record files over the size limit so callers can report them
+			if fd.config.MaxFileSize > 0 && info.Size() > fd.config.MaxFileSize {
+				fd.oversized = append(fd.oversized, FileResult{
+					Path:     path,
+					Size:     info.Size(),
+					IsHidden: fd.isHiddenFile(path),
+				})
+				return nil
+			}
+
+			if isArchive {
+				results = append(results, FileResult{
+					Path:      path,
+					Size:      info.Size(),
+					IsBinary:  true,
+					IsHidden:  fd.isHiddenFile(path),
+					IsArchive: true,
+				})
+				return nil
+			}
+
+			if isDocument {
+				results = append(results, FileResult{
+					Path:       path,
+					Size:       info.Size(),
+					IsBinary:   true,
+					IsHidden:   fd.isHiddenFile(path),
+					IsDocument: true,
+				})
+				return nil
+			}
+
 			// Detect if file is binary
 			isBinary, err := fd.isBinaryFile(path)
 			if err != nil {
@@ -161,29 +215,42 @@ func (fd *FileDiscovery) DiscoverFiles(ctx context.Context, rootPath string) ([]
 	return results, nil
 }
 
-// shouldIncludeFile determines if a file should be included based on patterns and size
-func (fd *FileDiscovery) shouldIncludeFile(path string, info fs.FileInfo, rootPath string) bool {
-	// Check file size
-	if fd.config.MaxFileSize > 0 && info.Size() > fd.config.MaxFileSize {
-		return false
-	}
+// Oversized returns the files the last DiscoverFiles call matched but left
+// out for exceeding MaxFileSize
+func (fd *FileDiscovery) Oversized() []FileResult {
+	return fd.oversized
+}
 
-	// Check hidden files
-	if !fd.config.IncludeHidden && fd.isHiddenFile(path) {
-		return false
+// shouldIncludeFile determines if a file should be included based on patterns
+func (fd *FileDiscovery) shouldIncludeFile(path, rootPath string) bool {
+	// Get relative path for pattern matching
+	relPath, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		relPath = path
 	}
 
-	// Get relative path for pattern matching
+	return fd.MatchesPath(relPath)
+}
+
+// shouldIncludeContainer determines if an archive or document should be
+// included. Archives are expanded and documents' text extracted unless hidden
+// or excluded, whatever the include patterns.
+func (fd *FileDiscovery) shouldIncludeContainer(path, rootPath string) bool {
 	relPath, err := filepath.Rel(rootPath, path)
 	if err != nil {
 		relPath = path
 	}
 
+	return !fd.isExcluded(relPath)
+}
+
+// MatchesPath reports whether a path relative to the scan root passes the
+// hidden-file setting and the include and exclude patterns. File size and
+// binary content are not checked.
+func (fd *FileDiscovery) MatchesPath(relPath string) bool {
 	// Check exclude patterns first
-	for _, pattern := range fd.config.ExcludePatterns {
-		if fd.matchesPattern(relPath, pattern) {
-			return false
-		}
+	if fd.isExcluded(relPath) {
+		return false
 	}
 
 	// Check include patterns
@@ -200,6 +267,23 @@ func (fd *FileDiscovery) shouldIncludeFile(path string, info fs.FileInfo, rootPa
 	return false
 }
 
+// isExcluded reports whether a relative path is hidden while hidden files are
+// not included, or matches an exclude pattern
+func (fd *FileDiscovery) isExcluded(relPath string) bool {
+	// Check hidden files
+	if !fd.config.IncludeHidden && fd.isHiddenFile(relPath) {
+		return true
+	}
+
+	for _, pattern := range fd.config.ExcludePatterns {
+		if fd.matchesPattern(relPath, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // matchesPattern checks if a file path matches a glob-style pattern
 func (fd *FileDiscovery) matchesPattern(path, pattern string) bool {
 	// Convert to forward slashes for consistent matching
@@ -236,19 +320,25 @@ func (fd *FileDiscovery) isBinaryFile(path string) (bool, error) {
 		return false, err
 	}
 
-	// Truncate buffer to actual read size
-	buffer = buffer[:n]
+	return isBinaryContent(buffer[:n]), nil
+}
+
+// isBinaryContent determines if content is binary from its first 512 bytes
+func isBinaryContent(buffer []byte) bool {
+	if len(buffer) > 512 {
+		buffer = buffer[:512]
+	}
 
 	// Check for null bytes (strong indicator of binary)
 	for _, b := range buffer {
 		if b == 0 {
-			return true, nil
+			return true
 		}
 	}
 
 	// Check if content is valid UTF-8
 	if !utf8.Valid(buffer) {
-		return true, nil
+		return true
 	}
 
 	// Check for high ratio of non-printable characters
@@ -261,10 +351,10 @@ func (fd *FileDiscovery) isBinaryFile(path string) (bool, error) {
 
 	// If more than 30% non-printable, consider binary
 	if len(buffer) > 0 && float64(nonPrintable)/float64(len(buffer)) > 0.3 {
-		return true, nil
+		return true
 	}
 
-	return false, nil
+	return false
 }
 
 // GetStats returns statistics about discovered files
@@ -374,4 +374,10 @@ func TestFileDiscovery_SizeLimit(t *testing.T) {
 
 	assert.True(t, resultPaths["small.txt"], "Small files should be included")
 	assert.False(t, resultPaths["large.txt"], "Large files should be excluded")
+
+	// Excluded large files are reported
+	oversized := discovery.Oversized()
+	require.Len(t, oversized, 1)
+	assert.Equal(t, "large.txt", filepath.Base(oversized[0].Path))
+	assert.Equal(t, int64(len(largeContent)), oversized[0].Size)
 }
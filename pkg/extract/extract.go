@@ -0,0 +1,86 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// maxPartSize bounds the decompressed size of each part read from a document,
+// so a crafted document cannot exhaust memory
+const maxPartSize = 64 * 1024 * 1024 // 64MB
+
+// Document is the text extracted from an office document or PDF. Each cell,
+// paragraph or PDF text block is one line of Text, and Locations[i] places
+// line i+1 in the document.
+type Document struct {
+	Text      []byte
+	Locations []detection.DocumentLocation
+}
+
+// extractors maps file extensions to the function reading that format
+var extractors = map[string]func([]byte) (*Document, error){
+	".xlsx": extractXLSX,
+	".xlsm": extractXLSX,
+	".docx": extractDOCX,
+	".docm": extractDOCX,
+	".pptx": extractPPTX,
+	".odt":  extractODT,
+	".pdf":  extractPDF,
+}
+
+// Supported reports whether text can be extracted from a file, by its name
+func Supported(name string) bool {
+	_, ok := extractors[strings.ToLower(path.Ext(name))]
+	return ok
+}
+
+// Extract extracts the text of an Excel workbook, Word document, PowerPoint
+// presentation, OpenDocument text file or PDF, recognised by its file name
+func Extract(name string, content []byte) (doc *Document, err error) {
+	extractor, ok := extractors[strings.ToLower(path.Ext(name))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported document type: %s", name)
+	}
+
+	// Documents come from untrusted repositories, so a malformed one must not
+	// take down the scan
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, fmt.Errorf("failed to extract text from %s: %v", name, r)
+		}
+	}()
+
+	doc, err = extractor(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text from %s: %w", name, err)
+	}
+	return doc, nil
+}
+
+// builder collects the lines of a document
+type builder struct {
+	text      bytes.Buffer
+	locations []detection.DocumentLocation
+}
+
+// add appends text as one line. Whitespace runs, including line breaks, are
+// collapsed to single spaces; blank text is dropped. It reports whether a
+// line was added.
+func (b *builder) add(text string, location detection.DocumentLocation) bool {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return false
+	}
+	b.text.WriteString(text)
+	b.text.WriteByte('\n')
+	b.locations = append(b.locations, location)
+	return true
+}
+
+func (b *builder) document() *Document {
+	return &Document{Text: b.text.Bytes(), Locations: b.locations}
+}
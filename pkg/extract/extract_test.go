@@ -0,0 +1,240 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zipParts(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// lines pairs each extracted line with its location
+func lines(t *testing.T, doc *Document) map[string]string {
+	t.Helper()
+	text := strings.Split(strings.TrimSuffix(string(doc.Text), "\n"), "\n")
+	require.Len(t, doc.Locations, len(text))
+	result := make(map[string]string)
+	for i, line := range text {
+		result[doc.Locations[i].String()] = line
+	}
+	return result
+}
+
+const relsNS = `xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+func TestExtract_XLSX(t *testing.T) {
+	content := zipParts(t, map[string]string{
+		"xl/workbook.xml": `<workbook ` + relsNS + `><sheets>
+			<sheet name="Customers" sheetId="1" r:id="rId1"/>
+			<sheet name="Q3 Payroll" sheetId="2" r:id="rId2"/>
+		</sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships>
+			<Relationship Id="rId1" Target="worksheets/sheet1.xml"/>
+			<Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/>
+		</Relationships>`,
+		"xl/sharedStrings.xml": `<sst>
+			<si><t>Name</t></si>
+			<si><t>TFN</t></si>
+			<si><r><t>Jane </t></r><r><t>Citizen</t></r><rPh><t>jein</t></rPh></si>
+		</sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>123456782</v></c><c r="C2"/></row>
+		</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData>
+			<row r="4"><c t="inlineStr"><is><t>jane@example.com</t></is></c><c><v>42</v></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	doc, err := Extract("exports/Customers.XLSX", content)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Customers!A1":    "Name",
+		"Customers!B1":    "TFN",
+		"Customers!A2":    "Jane Citizen",
+		"Customers!B2":    "123456782",
+		"'Q3 Payroll'!A4": "jane@example.com",
+		"'Q3 Payroll'!B4": "42",
+	}, lines(t, doc))
+	assert.Equal(t, detection.DocumentLocation{Sheet: "Customers", Cell: "A1"}, doc.Locations[0])
+}
+
+func TestExtract_DOCX(t *testing.T) {
+	content := zipParts(t, map[string]string{
+		"word/document.xml": `<w:document xmlns:w="w"><w:body>
+			<w:p><w:r><w:t>Customer list</w:t></w:r></w:p>
+			<w:p></w:p>
+			<w:tbl><w:tr><w:tc><w:p><w:r><w:t>TFN:</w:t></w:r><w:r><w:tab/><w:t>123 456 782</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+			<w:p><w:r><w:t xml:space="preserve">Email </w:t></w:r><w:r><w:br/><w:t>jane@example.com</w:t></w:r></w:p>
+		</w:body></w:document>`,
+	})
+
+	doc, err := Extract("letter.docx", content)
+	require.NoError(t, err)
+	assert.Equal(t, "Customer list\nTFN: 123 456 782\nEmail jane@example.com\n", string(doc.Text))
+	assert.Equal(t, []detection.DocumentLocation{{Paragraph: 1}, {Paragraph: 2}, {Paragraph: 3}}, doc.Locations)
+}
+
+func TestExtract_PPTX(t *testing.T) {
+	content := zipParts(t, map[string]string{
+		"ppt/presentation.xml": `<p:presentation xmlns:p="p" ` + relsNS + `><p:sldIdLst>
+			<p:sldId id="256" r:id="rId3"/><p:sldId id="257" r:id="rId2"/>
+		</p:sldIdLst></p:presentation>`,
+		"ppt/_rels/presentation.xml.rels": `<Relationships>
+			<Relationship Id="rId2" Target="slides/slide1.xml"/>
+			<Relationship Id="rId3" Target="slides/slide2.xml"/>
+		</Relationships>`,
+		"ppt/slides/slide1.xml": `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Contact jane@example.com</a:t></a:r></a:p></p:sld>`,
+		"ppt/slides/slide2.xml": `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Agenda</a:t></a:r></a:p><a:p><a:r><a:t>Review</a:t></a:r></a:p></p:sld>`,
+	})
+
+	doc, err := Extract("deck.pptx", content)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"slide 1, paragraph 1": "Agenda",
+		"slide 1, paragraph 2": "Review",
+		"slide 2, paragraph 1": "Contact jane@example.com",
+	}, lines(t, doc))
+}
+
+func TestExtract_ODT(t *testing.T) {
+	content := zipParts(t, map[string]string{
+		"content.xml": `<office:document-content xmlns:office="o" xmlns:text="t"><office:body><office:text>
+			<text:h>Payroll</text:h>
+			<text:p>TFN<text:s/><text:span>123456782</text:span></text:p>
+		</office:text></office:body></office:document-content>`,
+	})
+
+	doc, err := Extract("payroll.odt", content)
+	require.NoError(t, err)
+	assert.Equal(t, "Payroll\nTFN 123456782\n", string(doc.Text))
+	assert.Equal(t, []detection.DocumentLocation{{Paragraph: 1}, {Paragraph: 2}}, doc.Locations)
+}
+
+// pdfDocument builds a PDF with one page per content stream, compressing the
+// streams when asked
+func pdfDocument(t *testing.T, compress bool, pages ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 3+2*i)
+	}
+	fmt.Fprintf(&buf, "1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	fmt.Fprintf(&buf, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(pages))
+	for i, page := range pages {
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>\nendobj\n", 3+2*i, 4+2*i)
+
+		data, filter := []byte(page), ""
+		if compress {
+			var z bytes.Buffer
+			w := zlib.NewWriter(&z)
+			_, err := w.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			data, filter = z.Bytes(), " /Filter /FlateDecode"
+		}
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Length %d%s >>\nstream\n", 4+2*i, len(data), filter)
+		buf.Write(data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func TestExtract_PDF(t *testing.T) {
+	page1 := "BT /F1 12 Tf 72 720 Td (Customer: Jane \\(Citizen\\)) Tj ET\n" +
+		"BT 72 700 Td (TFN:) Tj 40 0 Td <3132332034353620373832> Tj ET"
+	page2 := "q 1 0 0 1 0 0 cm BT [(ja) 20 (ne@exa) -50 (mple.com)] TJ T* [(Phone) -300 (0412 345 678)] TJ ET Q"
+
+	for _, compress := range []bool{false, true} {
+		doc, err := Extract("statement.pdf", pdfDocument(t, compress, page1, page2))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"page 1, paragraph 1": "Customer: Jane (Citizen)",
+			"page 1, paragraph 2": "TFN: 123 456 782",
+			"page 2, paragraph 1": "jane@example.com Phone 0412 345 678",
+		}, lines(t, doc), "compressed: %v", compress)
+	}
+
+	_, err := Extract("statement.pdf", []byte("%PDF-1.4\n1 0 obj\n<< /Filter /Standard >>\nendobj\ntrailer << /Encrypt 1 0 R >>"))
+	assert.Error(t, err)
+}
+
+func TestExtract_PDFObjectStreamOffsets(t *testing.T) {
+	// Object stream headers with offsets outside the stream are ignored
+	for _, header := range []string{"2 -9 ", "2 99 ", "2 9223372036854775807"} {
+		packed := header + "<< /Type /Pages /Kids [] /Count 0 >>"
+		pdf := fmt.Sprintf("%%PDF-1.5\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n"+
+			"5 0 obj\n<< /Type /ObjStm /N 1 /First %d /Length %d >>\nstream\n%s\nendstream\nendobj\n"+
+			"trailer\n<< /Root 1 0 R >>\n%%%%EOF\n", len(header), len(packed), packed)
+
+		assert.NotPanics(t, func() {
+			_, _ = Extract("statement.pdf", []byte(pdf))
+		}, "header %q", header)
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	assert.True(t, Supported("reports/Q3.xlsx"))
+	assert.True(t, Supported("scan.PDF"))
+	assert.False(t, Supported("customers.csv"))
+
+	_, err := Extract("customers.csv", []byte("name,tfn\n"))
+	assert.Error(t, err)
+
+	_, err = Extract("broken.docx", []byte("not a zip"))
+	assert.Error(t, err)
+
+	_, err = Extract("empty.xlsx", zipParts(t, map[string]string{"docProps/app.xml": "<Properties/>"}))
+	assert.Error(t, err)
+
+	_, err = Extract("fake.pdf", []byte("plain text"))
+	assert.Error(t, err)
+}
+
+func TestExtract_RecoversFromPanics(t *testing.T) {
+	extractors[".broken"] = func(content []byte) (*Document, error) {
+		var doc *Document
+		return doc, fmt.Errorf("%d", len(doc.Text))
+	}
+	defer delete(extractors, ".broken")
+
+	doc, err := Extract("report.broken", []byte("content"))
+	assert.Nil(t, doc)
+	assert.ErrorContains(t, err, "failed to extract text from report.broken")
+}
+
+func TestDocumentLocation_String(t *testing.T) {
+	tests := map[string]detection.DocumentLocation{
+		"Sheet1!B2":            {Sheet: "Sheet1", Cell: "B2"},
+		"'Q3 Payroll'!C10":     {Sheet: "Q3 Payroll", Cell: "C10"},
+		"'O''Brien'!A1":        {Sheet: "O'Brien", Cell: "A1"},
+		"page 2, paragraph 3":  {Page: 2, Paragraph: 3},
+		"slide 4, paragraph 1": {Slide: 4, Paragraph: 1},
+		"paragraph 12":         {Paragraph: 12},
+	}
+	for expected, location := range tests {
+		assert.Equal(t, expected, location.String())
+	}
+}
@@ -0,0 +1,476 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// Office Open XML (xlsx, docx, pptx) and OpenDocument files are zip archives
+// of XML parts. Only the parts holding the document text are read.
+
+// officePackage is an opened Office Open XML or OpenDocument file
+type officePackage struct {
+	files map[string]*zip.File
+}
+
+func openPackage(content []byte) (*officePackage, error) {
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid document package: %w", err)
+	}
+
+	pkg := &officePackage{files: make(map[string]*zip.File, len(r.File))}
+	for _, f := range r.File {
+		pkg.files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return pkg, nil
+}
+
+// read returns a part of the package, or nil if it does not exist
+func (p *officePackage) read(name string) ([]byte, error) {
+	f, ok := p.files[name]
+	if !ok {
+		return nil, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxPartSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(data) > maxPartSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxPartSize)
+	}
+	return data, nil
+}
+
+// relationships returns the targets of a part's relationships by ID,
+// resolved to package paths
+func (p *officePackage) relationships(part string) (map[string]string, error) {
+	dir, file := path.Split(part)
+	data, err := p.read(dir + "_rels/" + file + ".rels")
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("failed to parse relationships of %s: %w", part, err)
+	}
+
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(dir, target)
+		}
+		targets[rel.ID] = target
+	}
+	return targets, nil
+}
+
+// extractXLSX extracts the text and values of every non-empty cell, located
+// by sheet name and cell reference
+func extractXLSX(content []byte) (*Document, error) {
+	pkg, err := openPackage(content)
+	if err != nil {
+		return nil, err
+	}
+
+	workbook, err := pkg.read("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	if workbook == nil {
+		return nil, fmt.Errorf("no workbook found")
+	}
+
+	var wb struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(workbook, &wb); err != nil {
+		return nil, fmt.Errorf("failed to parse workbook: %w", err)
+	}
+
+	targets, err := pkg.relationships("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings, err := readSharedStrings(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	var b builder
+	for _, sheet := range wb.Sheets {
+		data, err := pkg.read(targets[sheet.ID])
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+		if err := readSheet(data, sheet.Name, sharedStrings, &b); err != nil {
+			return nil, fmt.Errorf("failed to parse sheet %s: %w", sheet.Name, err)
+		}
+	}
+	return b.document(), nil
+}
+
+// readSharedStrings returns the workbook's shared string table. Phonetic
+// guides (rPh) are left out.
+func readSharedStrings(pkg *officePackage) ([]string, error) {
+	data, err := pkg.read("xl/sharedStrings.xml")
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var sharedStrings []string
+	var current strings.Builder
+	inText, inPhonetic := false, false
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return sharedStrings, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse shared strings: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				inPhonetic = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				sharedStrings = append(sharedStrings, current.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhonetic = false
+			}
+		case xml.CharData:
+			if inText && !inPhonetic {
+				current.Write(t)
+			}
+		}
+	}
+}
+
+// readSheet adds the cells of a worksheet to the document
+func readSheet(data []byte, sheetName string, sharedStrings []string, b *builder) error {
+	var cellRef, cellType string
+	var value strings.Builder
+	inValue := false
+	row, column := 0, 0
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row++
+				if r, err := strconv.Atoi(attr(t, "r")); err == nil {
+					row = r
+				}
+				column = 0
+			case "c":
+				column++
+				cellRef, cellType = attr(t, "r"), attr(t, "t")
+				if cellRef == "" {
+					cellRef = columnName(column) + strconv.Itoa(row)
+				} else if c := columnNumber(cellRef); c > 0 {
+					column = c
+				}
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				text := value.String()
+				if cellType == "s" {
+					text = ""
+					if i, err := strconv.Atoi(strings.TrimSpace(value.String())); err == nil && i >= 0 && i < len(sharedStrings) {
+						text = sharedStrings[i]
+					}
+				}
+				b.add(text, detection.DocumentLocation{Sheet: sheetName, Cell: cellRef})
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+}
+
+// columnName converts a 1-based column number to letters, such as 28 to AB
+func columnName(n int) string {
+	name := ""
+	for n > 0 {
+		n--
+		name = string(rune('A'+n%26)) + name
+		n /= 26
+	}
+	return name
+}
+
+// columnNumber returns the 1-based column of a cell reference such as AB12
+func columnNumber(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n
+}
+
+// extractDOCX extracts the paragraphs of a Word document's body, including
+// paragraphs in tables and text boxes
+func extractDOCX(content []byte) (*Document, error) {
+	pkg, err := openPackage(content)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pkg.read("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("no document body found")
+	}
+
+	paragraphs, err := xmlParagraphs(data, map[string]bool{"p": true}, map[string]bool{"t": true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	var b builder
+	for _, text := range paragraphs {
+		b.add(text, detection.DocumentLocation{Paragraph: len(b.locations) + 1})
+	}
+	return b.document(), nil
+}
+
+var slideName = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+// extractPPTX extracts the paragraphs of each slide, in presentation order
+func extractPPTX(content []byte) (*Document, error) {
+	pkg, err := openPackage(content)
+	if err != nil {
+		return nil, err
+	}
+
+	slides, err := slideParts(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	var b builder
+	for i, slide := range slides {
+		data, err := pkg.read(slide)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+
+		paragraphs, err := xmlParagraphs(data, map[string]bool{"p": true}, map[string]bool{"t": true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse slide %d: %w", i+1, err)
+		}
+
+		n := 0
+		for _, text := range paragraphs {
+			if b.add(text, detection.DocumentLocation{Slide: i + 1, Paragraph: n + 1}) {
+				n++
+			}
+		}
+	}
+	return b.document(), nil
+}
+
+// slideParts returns the slide parts in presentation order, falling back to
+// the order of their file names
+func slideParts(pkg *officePackage) ([]string, error) {
+	presentation, err := pkg.read("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	if presentation != nil {
+		var p struct {
+			Slides []struct {
+				ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+			} `xml:"sldIdLst>sldId"`
+		}
+		if err := xml.Unmarshal(presentation, &p); err != nil {
+			return nil, fmt.Errorf("failed to parse presentation: %w", err)
+		}
+		targets, err := pkg.relationships("ppt/presentation.xml")
+		if err != nil {
+			return nil, err
+		}
+
+		var slides []string
+		for _, slide := range p.Slides {
+			if target, ok := targets[slide.ID]; ok {
+				slides = append(slides, target)
+			}
+		}
+		if len(slides) > 0 {
+			return slides, nil
+		}
+	}
+
+	var slides []string
+	numbers := make(map[string]int)
+	for name := range pkg.files {
+		if m := slideName.FindStringSubmatch(name); m != nil {
+			numbers[name], _ = strconv.Atoi(m[1])
+			slides = append(slides, name)
+		}
+	}
+	sort.Slice(slides, func(i, j int) bool { return numbers[slides[i]] < numbers[slides[j]] })
+	return slides, nil
+}
+
+// extractODT extracts the paragraphs and headings of an OpenDocument text file
+func extractODT(content []byte) (*Document, error) {
+	pkg, err := openPackage(content)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pkg.read("content.xml")
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("no document content found")
+	}
+
+	// OpenDocument holds text directly in paragraphs and their spans
+	paragraphs, err := xmlParagraphs(data, map[string]bool{"p": true, "h": true}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	var b builder
+	for _, text := range paragraphs {
+		b.add(text, detection.DocumentLocation{Paragraph: len(b.locations) + 1})
+	}
+	return b.document(), nil
+}
+
+// breakElements separate words within a paragraph: tabs, line breaks and
+// OpenDocument space runs
+var breakElements = map[string]bool{"tab": true, "br": true, "cr": true, "line-break": true, "s": true}
+
+// xmlParagraphs returns the text of each paragraph element of an XML part in
+// document order. Text is taken from character data inside the text elements,
+// or anywhere in the paragraph when textElements is nil. A paragraph nested in
+// another, as in a text box, is returned on its own before the rest of the
+// outer paragraph.
+func xmlParagraphs(data []byte, paragraphElements, textElements map[string]bool) ([]string, error) {
+	var paragraphs []string
+	var open []*strings.Builder
+	inText := 0
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return paragraphs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case paragraphElements[t.Name.Local]:
+				open = append(open, &strings.Builder{})
+			case textElements[t.Name.Local]:
+				inText++
+			case breakElements[t.Name.Local] && len(open) > 0:
+				open[len(open)-1].WriteByte(' ')
+			}
+		case xml.EndElement:
+			switch {
+			case paragraphElements[t.Name.Local] && len(open) > 0:
+				paragraphs = append(paragraphs, open[len(open)-1].String())
+				open = open[:len(open)-1]
+			case textElements[t.Name.Local] && inText > 0:
+				inText--
+			}
+		case xml.CharData:
+			if len(open) > 0 && (textElements == nil || inText > 0) {
+				open[len(open)-1].Write(t)
+			}
+		}
+	}
+}
+
+// attr returns the value of an element's attribute by local name
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
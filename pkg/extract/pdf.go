@@ -0,0 +1,578 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// PDF text is read from the content streams of each page. This covers PDFs
+// produced by office suites and report generators with simple or standard
+// font encodings; text drawn with embedded CID fonts, scanned images and
+// encrypted PDFs are not readable without a full PDF renderer.
+
+var (
+	pdfObjectStart  = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfReference    = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	pdfTypePage     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfTypeCatalog  = regexp.MustCompile(`/Type\s*/Catalog\b`)
+	pdfTypeObjStm   = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	pdfLength       = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfPagesRef     = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R\b`)
+	pdfKids         = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	pdfContentsRef  = regexp.MustCompile(`/Contents\s+(\d+)\s+\d+\s+R\b`)
+	pdfContentsList = regexp.MustCompile(`/Contents\s*\[([^\]]*)\]`)
+	pdfFilter       = regexp.MustCompile(`/Filter\s*(\[[^\]]*\]|/\w+)`)
+	pdfInteger      = regexp.MustCompile(`/(N|First)\s+(\d+)`)
+)
+
+// pdfObject is an object of a PDF file: its dictionary or other value, and
+// its raw stream data if it has one
+type pdfObject struct {
+	value  []byte
+	stream []byte
+}
+
+// pdfFile is the set of objects of a PDF file, by object number
+type pdfFile struct {
+	objects map[int]*pdfObject
+}
+
+// extractPDF extracts the text blocks of each page, located by page and
+// paragraph
+func extractPDF(content []byte) (*Document, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(content, "\x00\t\n\r "), []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF file")
+	}
+	if bytes.Contains(content, []byte("/Encrypt")) {
+		return nil, fmt.Errorf("encrypted PDFs are not supported")
+	}
+
+	pdf := parsePDF(content)
+
+	var b builder
+	for i, page := range pdf.pages() {
+		data := pdf.pageContent(page)
+		n := 0
+		for _, text := range pdfTextBlocks(data) {
+			if b.add(text, detection.DocumentLocation{Page: i + 1, Paragraph: n + 1}) {
+				n++
+			}
+		}
+	}
+	return b.document(), nil
+}
+
+// parsePDF reads every object of a PDF file, including objects packed in
+// object streams. Later definitions of an object, from incremental updates,
+// replace earlier ones.
+func parsePDF(content []byte) *pdfFile {
+	pdf := &pdfFile{objects: make(map[int]*pdfObject)}
+
+	for _, m := range pdfObjectStart.FindAllSubmatchIndex(content, -1) {
+		number, err := strconv.Atoi(string(content[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		if obj := parsePDFObject(content[m[1]:]); obj != nil {
+			pdf.objects[number] = obj
+		}
+	}
+
+	for _, obj := range pdf.objects {
+		if obj.stream != nil && pdfTypeObjStm.Match(obj.value) {
+			pdf.unpackObjectStream(obj)
+		}
+	}
+	return pdf
+}
+
+// parsePDFObject reads an object's value and stream from just after its
+// "obj" keyword
+func parsePDFObject(data []byte) *pdfObject {
+	end := bytes.Index(data, []byte("endobj"))
+	body := data
+	if end >= 0 {
+		body = data[:end]
+	}
+
+	trimmed := bytes.TrimLeft(body, "\x00\t\n\r\f ")
+	if !bytes.HasPrefix(trimmed, []byte("<<")) {
+		return &pdfObject{value: bytes.TrimSpace(body)}
+	}
+
+	dictEnd := matchDict(trimmed)
+	if dictEnd < 0 {
+		return nil
+	}
+	obj := &pdfObject{value: trimmed[:dictEnd]}
+
+	rest := bytes.TrimLeft(trimmed[dictEnd:], "\x00\t\n\r\f ")
+	if !bytes.HasPrefix(rest, []byte("stream")) {
+		return obj
+	}
+
+	// Stream data starts after the end of line following the keyword. It
+	// may itself contain "endobj", so the data is taken from the original
+	// bytes rather than the object body.
+	start := len(body) - len(rest) + len("stream")
+	if bytes.HasPrefix(data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(data) && (data[start] == '\n' || data[start] == '\r') {
+		start++
+	}
+
+	if m := pdfLength.FindSubmatch(obj.value); m != nil && m[2] == nil {
+		length, err := strconv.Atoi(string(m[1]))
+		if err == nil && length >= 0 && start+length <= len(data) &&
+			bytes.HasPrefix(bytes.TrimLeft(data[start+length:], "\r\n "), []byte("endstream")) {
+			obj.stream = data[start : start+length]
+			return obj
+		}
+	}
+
+	// Without a usable direct length, the data runs to the endstream keyword
+	streamEnd := bytes.Index(data[start:], []byte("endstream"))
+	if streamEnd < 0 {
+		return obj
+	}
+	obj.stream = bytes.TrimRight(data[start:start+streamEnd], "\r\n")
+	return obj
+}
+
+// matchDict returns the end offset of the dictionary at the start of data,
+// or -1 if it is not closed
+func matchDict(data []byte) int {
+	depth := 0
+	for i := 0; i < len(data)-1; i++ {
+		switch {
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		case data[i] == '(':
+			i = skipLiteralString(data, i) - 1
+		}
+	}
+	return -1
+}
+
+// unpackObjectStream adds the objects packed in an object stream, unless an
+// object of the same number is defined directly
+func (pdf *pdfFile) unpackObjectStream(stream *pdfObject) {
+	var count, first int
+	for _, m := range pdfInteger.FindAllSubmatch(stream.value, -1) {
+		n, _ := strconv.Atoi(string(m[2]))
+		if string(m[1]) == "N" {
+			count = n
+		} else {
+			first = n
+		}
+	}
+
+	data := decodeStream(stream)
+	if data == nil || first > len(data) {
+		return
+	}
+
+	header := strings.Fields(string(data[:first]))
+	type entry struct{ number, offset int }
+	var entries []entry
+	for i := 0; i+1 < len(header) && len(entries) < count; i += 2 {
+		number, err1 := strconv.Atoi(header[i])
+		offset, err2 := strconv.Atoi(header[i+1])
+		if err1 != nil || err2 != nil || offset < 0 || first+offset < 0 || first+offset > len(data) {
+			return
+		}
+		entries = append(entries, entry{number, first + offset})
+	}
+
+	for i, e := range entries {
+		end := len(data)
+		if i+1 < len(entries) && entries[i+1].offset >= e.offset {
+			end = entries[i+1].offset
+		}
+		if _, ok := pdf.objects[e.number]; !ok {
+			pdf.objects[e.number] = &pdfObject{value: bytes.TrimSpace(data[e.offset:end])}
+		}
+	}
+}
+
+// decodeStream returns the decoded data of a stream, or nil if it uses a
+// filter other than FlateDecode or is corrupt
+func decodeStream(obj *pdfObject) []byte {
+	m := pdfFilter.FindSubmatch(obj.value)
+	if m == nil {
+		return obj.stream
+	}
+
+	filters := strings.Fields(strings.NewReplacer("[", " ", "]", " ", "/", " /").Replace(string(m[1])))
+	data := obj.stream
+	for _, filter := range filters {
+		if filter != "/FlateDecode" && filter != "/Fl" {
+			return nil
+		}
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil
+		}
+		// Truncated streams are common; keep whatever decompressed cleanly
+		decoded, err := io.ReadAll(io.LimitReader(r, maxPartSize))
+		r.Close()
+		if err != nil && len(decoded) == 0 {
+			return nil
+		}
+		data = decoded
+	}
+	return data
+}
+
+// pages returns the page objects in document order, following the page tree
+// from the catalog, or every page object by number if there is no usable tree
+func (pdf *pdfFile) pages() []*pdfObject {
+	var pages []*pdfObject
+	visited := make(map[int]bool)
+
+	var walk func(number int)
+	walk = func(number int) {
+		obj, ok := pdf.objects[number]
+		if !ok || visited[number] {
+			return
+		}
+		visited[number] = true
+
+		if m := pdfKids.FindSubmatch(obj.value); m != nil {
+			for _, ref := range pdfReference.FindAllSubmatch(m[1], -1) {
+				kid, _ := strconv.Atoi(string(ref[1]))
+				walk(kid)
+			}
+			return
+		}
+		if pdfTypePage.Match(obj.value) {
+			pages = append(pages, obj)
+		}
+	}
+
+	for _, obj := range pdf.objects {
+		if !pdfTypeCatalog.Match(obj.value) {
+			continue
+		}
+		if m := pdfPagesRef.FindSubmatch(obj.value); m != nil {
+			root, _ := strconv.Atoi(string(m[1]))
+			walk(root)
+		}
+		break
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	var numbers []int
+	for number, obj := range pdf.objects {
+		if pdfTypePage.Match(obj.value) {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		pages = append(pages, pdf.objects[number])
+	}
+	return pages
+}
+
+// pageContent returns the decoded content streams of a page, joined
+func (pdf *pdfFile) pageContent(page *pdfObject) []byte {
+	var refs [][][]byte
+	if m := pdfContentsRef.FindSubmatch(page.value); m != nil {
+		refs = [][][]byte{m}
+		// The reference may be to an array of streams
+		if number, _ := strconv.Atoi(string(m[1])); pdf.objects[number] != nil && pdf.objects[number].stream == nil {
+			refs = pdfReference.FindAllSubmatch(pdf.objects[number].value, -1)
+		}
+	} else if m := pdfContentsList.FindSubmatch(page.value); m != nil {
+		refs = pdfReference.FindAllSubmatch(m[1], -1)
+	}
+
+	var content bytes.Buffer
+	for _, ref := range refs {
+		number, _ := strconv.Atoi(string(ref[1]))
+		obj, ok := pdf.objects[number]
+		if !ok || obj.stream == nil {
+			continue
+		}
+		content.Write(decodeStream(obj))
+		content.WriteByte('\n')
+	}
+	return content.Bytes()
+}
+
+// pdfToken is an operand in a content stream
+type pdfToken struct {
+	text   string // Decoded string operand
+	number float64
+	kind   byte // 's' string, 'n' number, 'a' array, 'o' other
+	array  []pdfToken
+}
+
+// pdfTextBlocks returns the text of each BT/ET text object of a page's
+// content stream. Text positioning operators and large TJ kerning gaps
+// become spaces.
+func pdfTextBlocks(data []byte) []string {
+	var blocks []string
+	var current strings.Builder
+	var operands []pdfToken
+	var arrays [][]pdfToken // Arrays being read, innermost last
+
+	space := func() {
+		if current.Len() > 0 && !strings.HasSuffix(current.String(), " ") {
+			current.WriteByte(' ')
+		}
+	}
+	flush := func() {
+		if current.Len() > 0 {
+			blocks = append(blocks, current.String())
+			current.Reset()
+		}
+	}
+	push := func(token pdfToken) {
+		if len(arrays) > 0 {
+			arrays[len(arrays)-1] = append(arrays[len(arrays)-1], token)
+		} else {
+			operands = append(operands, token)
+		}
+	}
+	lastString := func() string {
+		if len(operands) > 0 && operands[len(operands)-1].kind == 's' {
+			return operands[len(operands)-1].text
+		}
+		return ""
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case isPDFSpace(c):
+			i++
+		case c == '%':
+			for i < len(data) && data[i] != '\n' && data[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			end := skipLiteralString(data, i)
+			push(pdfToken{kind: 's', text: decodePDFString(unescapeLiteral(data[i+1 : max(i+1, end-1)]))})
+			i = end
+		case c == '<' && i+1 < len(data) && data[i+1] == '<':
+			push(pdfToken{kind: 'o'})
+			i += 2
+		case c == '>' && i+1 < len(data) && data[i+1] == '>':
+			i += 2
+		case c == '<':
+			end := bytes.IndexByte(data[i:], '>')
+			if end < 0 {
+				end = len(data) - i
+			}
+			push(pdfToken{kind: 's', text: decodePDFString(unhex(data[i+1 : i+end]))})
+			i += end + 1
+		case c == '[':
+			arrays = append(arrays, nil)
+			i++
+		case c == ']':
+			if len(arrays) > 0 {
+				array := arrays[len(arrays)-1]
+				arrays = arrays[:len(arrays)-1]
+				push(pdfToken{kind: 'a', array: array})
+			}
+			i++
+		default:
+			start := i
+			i++
+			for i < len(data) && !isPDFSpace(data[i]) && !isPDFDelimiter(data[i]) {
+				i++
+			}
+			word := string(data[start:i])
+
+			if c == '/' {
+				push(pdfToken{kind: 'o'})
+				continue
+			}
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				push(pdfToken{kind: 'n', number: n})
+				continue
+			}
+			if len(arrays) > 0 {
+				push(pdfToken{kind: 'o'})
+				continue
+			}
+
+			switch word {
+			case "BT":
+				flush()
+			case "ET":
+				flush()
+			case "Tj":
+				current.WriteString(lastString())
+			case "'", "\"":
+				space()
+				current.WriteString(lastString())
+			case "TJ":
+				if len(operands) > 0 && operands[len(operands)-1].kind == 'a' {
+					for _, t := range operands[len(operands)-1].array {
+						if t.kind == 's' {
+							current.WriteString(t.text)
+						} else if t.kind == 'n' && t.number < -200 {
+							space()
+						}
+					}
+				}
+			case "Td", "TD", "T*", "Tm":
+				space()
+			case "BI":
+				// Inline image data runs to the EI operator
+				if end := bytes.Index(data[i:], []byte("EI")); end >= 0 {
+					i += end + 2
+				} else {
+					i = len(data)
+				}
+			}
+			operands = operands[:0]
+		}
+	}
+	flush()
+	return blocks
+}
+
+// skipLiteralString returns the offset just past the literal string starting
+// at data[start], which is '('
+func skipLiteralString(data []byte, start int) int {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(data)
+}
+
+// unescapeLiteral decodes the escape sequences of a literal string's contents
+func unescapeLiteral(s []byte) []byte {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			out = append(out, s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case '\r':
+			// Line continuation
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+		case '\n':
+		default:
+			if c >= '0' && c <= '7' {
+				n := 0
+				for j := 0; j < 3 && i < len(s) && s[i] >= '0' && s[i] <= '7'; j++ {
+					n = n*8 + int(s[i]-'0')
+					i++
+				}
+				i--
+				out = append(out, byte(n))
+			} else {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// unhex decodes a hex string's contents, ignoring whitespace; an odd final
+// digit is followed by 0
+func unhex(s []byte) []byte {
+	var digits []byte
+	for _, c := range s {
+		if _, ok := hexValue(c); ok {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		hi, _ := hexValue(digits[2*i])
+		lo, _ := hexValue(digits[2*i+1])
+		out[i] = hi<<4 | lo
+	}
+	return out
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// decodePDFString decodes UTF-16BE strings marked with a byte order mark, and
+// otherwise treats each byte as a Latin-1 character
+func decodePDFString(s []byte) string {
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	runes := make([]rune, len(s))
+	for i, c := range s {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -0,0 +1,161 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// Policy decides whether scan findings should fail a build. Suppressed
+// findings and findings already accepted in a baseline are never counted.
+type Policy struct {
+	FailOn        []detection.RiskLevel    // Any finding at these risk levels violates the policy
+	MaxCounts     map[detection.PIType]int // Maximum allowed findings per PI type
+	ValidatedOnly bool                     // Only count findings that passed checksum validation
+}
+
+// Violation describes one way the findings broke the policy
+type Violation struct {
+	Rule    string `json:"rule"`
+	Count   int    `json:"count"`
+	Limit   int    `json:"limit"`
+	Message string `json:"message"`
+}
+
+// Enabled reports whether the policy has any rules
+func (p *Policy) Enabled() bool {
+	return p != nil && (len(p.FailOn) > 0 || len(p.MaxCounts) > 0)
+}
+
+// Evaluate checks findings against the policy
+func (p *Policy) Evaluate(findings []detection.Finding) []Violation {
+	if !p.Enabled() {
+		return nil
+	}
+
+	byRisk := make(map[detection.RiskLevel]int)
+	byType := make(map[detection.PIType]int)
+	for _, f := range findings {
+		if !p.counts(f) {
+			continue
+		}
+		// A collapsed CSV column counts every row it stands for
+		byRisk[f.RiskLevel] += f.Records()
+		byType[f.Type] += f.Records()
+	}
+
+	qualifier := ""
+	if p.ValidatedOnly {
+		qualifier = "validated "
+	}
+
+	var violations []Violation
+	for _, level := range p.FailOn {
+		if count := byRisk[level]; count > 0 {
+			violations = append(violations, Violation{
+				Rule:    "fail-on:" + strings.ToLower(string(level)),
+				Count:   count,
+				Message: fmt.Sprintf("%d %s%s findings", count, qualifier, level),
+			})
+		}
+	}
+
+	types := make([]string, 0, len(p.MaxCounts))
+	for piType := range p.MaxCounts {
+		types = append(types, string(piType))
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		piType := detection.PIType(t)
+		limit := p.MaxCounts[piType]
+		if count := byType[piType]; count > limit {
+			violations = append(violations, Violation{
+				Rule:    "max-count:" + t,
+				Count:   count,
+				Limit:   limit,
+				Message: fmt.Sprintf("%d %s%s findings exceed the maximum of %d", count, qualifier, t, limit),
+			})
+		}
+	}
+
+	return violations
+}
+
+// counts reports whether a finding is subject to the policy
+func (p *Policy) counts(f detection.Finding) bool {
+	if f.Suppressed() {
+		return false
+	}
+	if f.BaselineState == detection.BaselineStateUnchanged || f.BaselineState == detection.BaselineStateAbsent {
+		return false
+	}
+	if p.ValidatedOnly && !f.Validated {
+		return false
+	}
+	return true
+}
+
+// ParseRiskLevels parses a comma separated list such as "critical,high"
+func ParseRiskLevels(value string) ([]detection.RiskLevel, error) {
+	valid := map[string]detection.RiskLevel{
+		"critical": detection.RiskLevelCritical,
+		"high":     detection.RiskLevelHigh,
+		"medium":   detection.RiskLevelMedium,
+		"low":      detection.RiskLevelLow,
+	}
+
+	var levels []detection.RiskLevel
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		level, ok := valid[part]
+		if !ok {
+			return nil, fmt.Errorf("invalid risk level %q (expected critical, high, medium or low)", part)
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// ParseMaxCounts parses per-type limits such as "TFN=0,EMAIL=10". Every type
+// must be one of known, so a misspelt type cannot silently disable its limit.
+func ParseMaxCounts(value string, known []detection.PIType) (map[detection.PIType]int, error) {
+	types := make(map[detection.PIType]bool, len(known))
+	for _, piType := range known {
+		types[piType] = true
+	}
+
+	counts := make(map[detection.PIType]int)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, limit, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid max count %q (expected TYPE=N)", part)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid max count %q: limit must be a non-negative integer", part)
+		}
+
+		piType := detection.PIType(strings.ToUpper(strings.TrimSpace(name)))
+		if !types[piType] {
+			return nil, fmt.Errorf("invalid max count %q: unknown PI type %s (see pi-scanner rules list)", part, piType)
+		}
+
+		counts[piType] = n
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFindings() []detection.Finding {
+	return []detection.Finding{
+		{Type: detection.PITypeTFN, RiskLevel: detection.RiskLevelCritical, Validated: true},
+		{Type: detection.PITypeTFN, RiskLevel: detection.RiskLevelHigh, Validated: false},
+		{Type: detection.PITypeEmail, RiskLevel: detection.RiskLevelLow},
+		{Type: detection.PITypeEmail, RiskLevel: detection.RiskLevelLow},
+		{Type: detection.PITypeMedicare, RiskLevel: detection.RiskLevelCritical, Validated: true,
+			Suppression: &detection.Suppression{Source: detection.SuppressionInline}},
+		{Type: detection.PITypeMedicare, RiskLevel: detection.RiskLevelCritical, Validated: true,
+			BaselineState: detection.BaselineStateUnchanged},
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+		rules  []string
+	}{
+		{
+			name:   "no policy",
+			policy: nil,
+		},
+		{
+			name:   "fail on critical and high",
+			policy: &Policy{FailOn: []detection.RiskLevel{detection.RiskLevelCritical, detection.RiskLevelHigh}},
+			rules:  []string{"fail-on:critical", "fail-on:high"},
+		},
+		{
+			name:   "validated only ignores unvalidated findings",
+			policy: &Policy{FailOn: []detection.RiskLevel{detection.RiskLevelHigh}, ValidatedOnly: true},
+		},
+		{
+			name:   "max counts",
+			policy: &Policy{MaxCounts: map[detection.PIType]int{detection.PITypeEmail: 1, detection.PITypeTFN: 2, detection.PITypeMedicare: 0}},
+			rules:  []string{"max-count:EMAIL"},
+		},
+		{
+			name:   "validated max count",
+			policy: &Policy{MaxCounts: map[detection.PIType]int{detection.PITypeTFN: 0}, ValidatedOnly: true},
+			rules:  []string{"max-count:TFN"},
+		},
+		{
+			name:   "suppressed and baselined findings are not counted",
+			policy: &Policy{MaxCounts: map[detection.PIType]int{detection.PITypeMedicare: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tt.policy.Evaluate(testFindings())
+
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.Rule)
+				assert.NotEmpty(t, v.Message)
+			}
+			assert.Equal(t, tt.rules, rules)
+		})
+	}
+}
+
+func TestPolicy_EvaluateRecordCounts(t *testing.T) {
+	// A collapsed CSV column counts as every row it stands for
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, RiskLevel: detection.RiskLevelHigh, Validated: true, RecordCount: 12},
+		{Type: detection.PITypeTFN, RiskLevel: detection.RiskLevelHigh},
+	}
+
+	p := &Policy{
+		FailOn:    []detection.RiskLevel{detection.RiskLevelHigh},
+		MaxCounts: map[detection.PIType]int{detection.PITypeTFN: 10},
+	}
+	violations := p.Evaluate(findings)
+	require.Len(t, violations, 2)
+	assert.Equal(t, 13, violations[0].Count)
+	assert.Equal(t, "max-count:TFN", violations[1].Rule)
+	assert.Equal(t, 13, violations[1].Count)
+
+	p.ValidatedOnly = true
+	violations = p.Evaluate(findings)
+	require.Len(t, violations, 2)
+	assert.Equal(t, 12, violations[1].Count)
+}
+
+func TestParseRiskLevels(t *testing.T) {
+	levels, err := ParseRiskLevels("Critical, high,")
+	require.NoError(t, err)
+	assert.Equal(t, []detection.RiskLevel{detection.RiskLevelCritical, detection.RiskLevelHigh}, levels)
+
+	_, err = ParseRiskLevels("critical,severe")
+	assert.ErrorContains(t, err, "severe")
+}
+
+func TestParseMaxCounts(t *testing.T) {
+	known := append(detection.PITypes(), "MEMBER_NUMBER")
+
+	counts, err := ParseMaxCounts("tfn=0, EMAIL=10, member_number=2", known)
+	require.NoError(t, err)
+	assert.Equal(t, map[detection.PIType]int{detection.PITypeTFN: 0, detection.PITypeEmail: 10, "MEMBER_NUMBER": 2}, counts)
+
+	for _, invalid := range []string{"TFN", "TFN=-1", "TFN=many"} {
+		_, err := ParseMaxCounts(invalid, known)
+		assert.Error(t, err, invalid)
+	}
+
+	// A misspelt type is an error rather than a limit that never applies
+	_, err = ParseMaxCounts("TNF=0", known)
+	assert.ErrorContains(t, err, "unknown PI type TNF")
+}
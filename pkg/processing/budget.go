@@ -0,0 +1,60 @@
+package processing
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryBudget limits the bytes of file content held by queued and running
+// jobs. Acquire blocks until enough content has been released, which applies
+// backpressure to whoever is reading files.
+type memoryBudget struct {
+	mu       sync.Mutex
+	limit    int64
+	used     int64
+	released chan struct{} // Closed and replaced whenever content is released
+}
+
+// newMemoryBudget creates a budget of limit bytes. A limit of zero or less
+// means no limit.
+func newMemoryBudget(limit int64) *memoryBudget {
+	return &memoryBudget{limit: limit, released: make(chan struct{})}
+}
+
+// acquire reserves n bytes, waiting while the budget is spent. Content larger
+// than the whole budget is admitted once nothing else is held, so a single
+// large file cannot stall the pipeline.
+func (b *memoryBudget) acquire(ctx context.Context, n int64) error {
+	for {
+		b.mu.Lock()
+		if b.limit <= 0 || b.used == 0 || b.used+n <= b.limit {
+			b.used += n
+			b.mu.Unlock()
+			return nil
+		}
+		released := b.released
+		b.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release returns n bytes to the budget and wakes waiting acquirers
+func (b *memoryBudget) release(n int64) {
+	b.mu.Lock()
+	b.used -= n
+	close(b.released)
+	b.released = make(chan struct{})
+	b.mu.Unlock()
+}
+
+// inUse returns the bytes currently reserved
+func (b *memoryBudget) inUse() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
@@ -0,0 +1,49 @@
+package processing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	budget := newMemoryBudget(10)
+	ctx := context.Background()
+
+	require.NoError(t, budget.acquire(ctx, 6))
+	require.NoError(t, budget.acquire(ctx, 4))
+	assert.Equal(t, int64(10), budget.inUse())
+
+	// A full budget blocks until content is released
+	acquired := make(chan error, 1)
+	go func() { acquired <- budget.acquire(ctx, 5) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire should wait for the budget")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	budget.release(6)
+	require.NoError(t, <-acquired)
+	assert.Equal(t, int64(9), budget.inUse())
+
+	// Cancellation gives up waiting
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, budget.acquire(cancelled, 5), context.Canceled)
+
+	// Content larger than the budget is admitted once nothing else is held
+	budget.release(9)
+	require.NoError(t, budget.acquire(ctx, 50))
+	assert.Equal(t, int64(50), budget.inUse())
+}
+
+func TestMemoryBudget_Unlimited(t *testing.T) {
+	budget := newMemoryBudget(0)
+	require.NoError(t, budget.acquire(context.Background(), 1<<40))
+	require.NoError(t, budget.acquire(context.Background(), 1<<40))
+}
@@ -0,0 +1,126 @@
+package processing
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// DefaultChunkOverlap is the number of bytes each window of a chunked file
+// shares with the next one. Matches shorter than the overlap that cross a
+// window boundary are found whole in the earlier window.
+const DefaultChunkOverlap = 4 * 1024
+
+// Chunk places a window of a large file's content within the file. Findings
+// are reported in window coordinates by the detectors and translated back to
+// file coordinates by the processor.
+type Chunk struct {
+	Index  int   // Position of the window in the file, from 0
+	Offset int64 // Byte offset of the window in the file
+	Line   int   // File line the window starts on
+	Column int   // File column the window starts at
+
+	// Window position at which the next window takes over. Findings that
+	// start there or later fall in the overlap and are left to the next
+	// window, so each match is reported once. Zero for the last window.
+	endLine   int
+	endColumn int
+}
+
+// owns reports whether a finding, in window coordinates, belongs to this
+// window rather than the next
+func (c *Chunk) owns(f detection.Finding) bool {
+	if c.endLine == 0 {
+		return true
+	}
+	return f.Line < c.endLine || (f.Line == c.endLine && f.Column < c.endColumn)
+}
+
+// translate moves a finding from window coordinates to file coordinates
+func (c *Chunk) translate(f *detection.Finding) {
+	if f.Line == 1 {
+		f.Column += c.Column - 1
+	}
+	f.Line += c.Line - 1
+}
+
+// ReadChunks reads r in overlapping windows of at most size bytes and calls
+// emit with each window in order. Windows end on a line break where one falls
+// in the second half of the window, or failing that on a space, so matches are
+// rarely cut at the start of a window. A new buffer is passed to each call.
+func ReadChunks(r io.Reader, size, overlap int, emit func(content []byte, chunk Chunk) error) error {
+	if size <= 0 {
+		return fmt.Errorf("chunk size must be positive: %d", size)
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap > size/2 {
+		overlap = size / 2
+	}
+	step := size - overlap
+
+	chunk := Chunk{Line: 1, Column: 1}
+	var pending []byte
+	for {
+		buf := make([]byte, size)
+		n := copy(buf, pending)
+		read, err := io.ReadFull(r, buf[n:])
+		buf = buf[:n+read]
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if len(buf) == 0 && chunk.Index > 0 {
+				return nil
+			}
+			chunk.endLine, chunk.endColumn = 0, 0
+			return emit(buf, chunk)
+		}
+		if err != nil {
+			return err
+		}
+
+		boundary := windowBoundary(buf, step)
+		chunk.endLine, chunk.endColumn = advance(1, 1, buf[:boundary])
+		if err := emit(buf, chunk); err != nil {
+			return err
+		}
+
+		chunk.Line, chunk.Column = advance(chunk.Line, chunk.Column, buf[:boundary])
+		chunk.Offset += int64(boundary)
+		chunk.Index++
+		pending = buf[boundary:]
+	}
+}
+
+// windowBoundary picks where the next window starts: after the last line
+// break before step, else after the last space or tab, else at the last
+// UTF-8 character start. Breaks in the first half of the window are ignored
+// so windows stay close to their full size.
+func windowBoundary(buf []byte, step int) int {
+	for _, separators := range []string{"\n", " \t"} {
+		if i := bytes.LastIndexAny(buf[:step], separators); i >= step/2 {
+			return i + 1
+		}
+	}
+
+	for i := step; i > 0 && i > step-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			return i
+		}
+	}
+	return step
+}
+
+// advance returns the line and column reached after content, counting
+// columns in bytes as the detectors do
+func advance(line, column int, content []byte) (int, int) {
+	if lines := bytes.Count(content, []byte{'\n'}); lines > 0 {
+		line += lines
+		column = 1
+		content = content[bytes.LastIndexByte(content, '\n')+1:]
+	}
+	return line, column + len(content)
+}
@@ -0,0 +1,156 @@
+package processing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// position returns the 1-based line and byte column of an offset
+func position(content []byte, offset int64) (line, column int) {
+	line, column = 1, 1
+	for _, b := range content[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+func readChunks(t *testing.T, content []byte, size, overlap int) ([][]byte, []Chunk) {
+	t.Helper()
+	var windows [][]byte
+	var chunks []Chunk
+	err := ReadChunks(bytes.NewReader(content), size, overlap, func(window []byte, chunk Chunk) error {
+		windows = append(windows, window)
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	return windows, chunks
+}
+
+func TestReadChunks(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "row %d,customer %d\n", i, i*7)
+	}
+	content := []byte(b.String())
+
+	windows, chunks := readChunks(t, content, 256, 64)
+	require.Greater(t, len(windows), 1)
+
+	for i, window := range windows {
+		chunk := chunks[i]
+		assert.Equal(t, i, chunk.Index)
+		assert.LessOrEqual(t, len(window), 256)
+		assert.Equal(t, content[chunk.Offset:chunk.Offset+int64(len(window))], window)
+
+		line, column := position(content, chunk.Offset)
+		assert.Equal(t, line, chunk.Line, "window %d line", i)
+		assert.Equal(t, column, chunk.Column, "window %d column", i)
+
+		if i == 0 {
+			continue
+		}
+
+		// Windows start on a line and overlap the previous one
+		previous := chunks[i-1]
+		assert.Equal(t, byte('\n'), content[chunk.Offset-1])
+		assert.Less(t, chunk.Offset, previous.Offset+int64(len(windows[i-1])))
+		assert.GreaterOrEqual(t, previous.Offset+int64(len(windows[i-1]))-chunk.Offset, int64(64))
+	}
+
+	last := len(windows) - 1
+	assert.Equal(t, int64(len(content)), chunks[last].Offset+int64(len(windows[last])))
+	assert.Zero(t, chunks[last].endLine, "the last window owns all of its findings")
+}
+
+func TestReadChunks_LongLine(t *testing.T) {
+	// Without line breaks windows start after a space
+	content := []byte(strings.Repeat("customer 123456782 ", 100))
+	_, chunks := readChunks(t, content, 128, 32)
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks[1:] {
+		assert.Equal(t, byte(' '), content[chunk.Offset-1])
+		assert.Equal(t, 1, chunk.Line)
+		assert.Equal(t, int(chunk.Offset)+1, chunk.Column)
+	}
+
+	// Without spaces windows start on a character boundary
+	content = []byte(strings.Repeat("é", 300))
+	windows, _ := readChunks(t, content, 101, 10)
+	for _, window := range windows {
+		assert.True(t, bytes.HasPrefix(window, []byte("é")))
+	}
+}
+
+func TestReadChunks_Small(t *testing.T) {
+	windows, chunks := readChunks(t, []byte("jane@example.com\n"), 1024, 64)
+	require.Len(t, windows, 1)
+	assert.Equal(t, Chunk{Line: 1, Column: 1}, chunks[0])
+
+	windows, _ = readChunks(t, nil, 1024, 64)
+	require.Len(t, windows, 1)
+	assert.Empty(t, windows[0])
+
+	assert.Error(t, ReadChunks(bytes.NewReader(nil), 0, 0, func([]byte, Chunk) error { return nil }))
+}
+
+func TestFileProcessor_Chunks(t *testing.T) {
+	// Findings cross window boundaries both between lines and within a long line
+	var b strings.Builder
+	for i := 0; i < 120; i++ {
+		fmt.Fprintf(&b, "employee %d tfn: 123 456 782\n", i)
+	}
+	b.WriteString(strings.Repeat("tfn 123456782, ", 60))
+	content := []byte(b.String())
+
+	detectors := []detection.Detector{detection.NewDetector()}
+	config := DefaultProcessorConfig()
+	config.NumWorkers = 2
+
+	key := func(f detection.Finding) string {
+		return fmt.Sprintf("%s %d:%d %s", f.Type, f.Line, f.Column, f.Match)
+	}
+	collect := func(results []ProcessingResult) []string {
+		var keys []string
+		for _, result := range results {
+			require.NoError(t, result.Error)
+			for _, f := range result.Findings {
+				assert.Equal(t, "/repo/payroll.sql", f.File)
+				keys = append(keys, key(f))
+			}
+		}
+		return keys
+	}
+
+	whole, err := NewBatchProcessor(NewFileProcessor(config, detectors), 10).ProcessFiles(context.Background(),
+		[]FileJob{{FilePath: "/repo/payroll.sql", Content: content}})
+	require.NoError(t, err)
+	expected := collect(whole)
+	require.NotEmpty(t, expected)
+
+	var jobs []FileJob
+	err = ReadChunks(bytes.NewReader(content), 300, 64, func(window []byte, chunk Chunk) error {
+		jobs = append(jobs, FileJob{FilePath: "/repo/payroll.sql", Content: window, Chunk: &chunk})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Greater(t, len(jobs), 5)
+
+	chunked, err := NewBatchProcessor(NewFileProcessor(config, detectors), 10).ProcessFiles(context.Background(), jobs)
+	require.NoError(t, err)
+
+	// Every finding is reported once, at its position in the file
+	assert.ElementsMatch(t, expected, collect(chunked))
+}
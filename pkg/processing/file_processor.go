@@ -1,15 +1,20 @@
 package processing
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"path/filepath"
 	"runtime"
 	"sync"
+	"time"
 
+	"github.com/MacAttak/pi-scanner/pkg/cache"
 	contextval "github.com/MacAttak/pi-scanner/pkg/context"
+	"github.com/MacAttak/pi-scanner/pkg/decode"
 	"github.com/MacAttak/pi-scanner/pkg/detection"
 	"github.com/MacAttak/pi-scanner/pkg/discovery"
+	"github.com/MacAttak/pi-scanner/pkg/structured"
 )
 
 // FileJob represents a file to be processed through the detection pipeline
@@ -17,11 +22,18 @@ type FileJob struct {
 	FilePath string
 	Content  []byte
 	FileInfo discovery.FileResult
+	Commit   *detection.CommitInfo // Set when scanning content from git history
+	Chunk    *Chunk                // Set when Content is one window of a larger file
+
+	// Set when Content is text extracted from a document; Locations[i]
+	// places line i+1 of the text in the document
+	Locations []detection.DocumentLocation
 }
 
 // ProcessingResult represents the result of processing a file
 type ProcessingResult struct {
 	FilePath string
+	Chunk    *Chunk // The window scanned, for chunked files
 	Findings []detection.Finding
 	Error    error
 	Stats    ProcessingStats
@@ -38,11 +50,15 @@ type ProcessingStats struct {
 type FileProcessor struct {
 	detectors        []detection.Detector
 	contextValidator *contextval.ContextValidator
+	cache            *cache.Cache
+	budget           *memoryBudget
+	decoding         decode.Limits
 	numWorkers       int
 	jobQueue         chan FileJob
 	resultQueue      chan ProcessingResult
 	workers          []*FileWorker
 	wg               sync.WaitGroup
+	submitting       sync.WaitGroup
 	ctx              context.Context
 	cancel           context.CancelFunc
 	started          bool
@@ -65,16 +81,21 @@ type ProcessorConfig struct {
 	MaxFileSize    int64
 	EnablePatterns bool
 	EnableGitleaks bool
+	MemoryBudget   int64         // Maximum bytes of file content queued or being scanned (0 = unlimited)
+	Cache          *cache.Cache  // Reuse detector findings for unchanged content (nil = no cache)
+	Decoding       decode.Limits // Scan the text decoded from base64, URL-encoded and hex runs (zero MaxDepth = off)
 }
 
 // DefaultProcessorConfig returns sensible defaults
 func DefaultProcessorConfig() ProcessorConfig {
 	return ProcessorConfig{
 		NumWorkers:     runtime.NumCPU(),
-		QueueSize:      10000,            // Support very large repositories
-		MaxFileSize:    10 * 1024 * 1024, // 10MB
+		QueueSize:      10000,             // Support very large repositories
+		MaxFileSize:    10 * 1024 * 1024,  // 10MB
+		MemoryBudget:   256 * 1024 * 1024, // 256MB
 		EnablePatterns: true,
 		EnableGitleaks: true,
+		Decoding:       decode.DefaultLimits(),
 	}
 }
 
@@ -90,6 +111,9 @@ func NewFileProcessor(config ProcessorConfig, detectors []detection.Detector) *F
 	return &FileProcessor{
 		detectors:        detectors,
 		contextValidator: contextval.NewContextValidator(),
+		cache:            config.Cache,
+		budget:           newMemoryBudget(config.MemoryBudget),
+		decoding:         config.Decoding,
 		numWorkers:       config.NumWorkers,
 		jobQueue:         make(chan FileJob, config.QueueSize),
 		resultQueue:      make(chan ProcessingResult, config.QueueSize),
@@ -126,23 +150,77 @@ func (fp *FileProcessor) Start(ctx context.Context) error {
 	return nil
 }
 
-// Submit adds a file to the processing queue
+// Submit adds a file to the processing queue. It blocks while the queue is
+// full or the memory budget is spent, until workers catch up or the processor
+// is stopped.
 func (fp *FileProcessor) Submit(job FileJob) error {
 	fp.mu.RLock()
-	defer fp.mu.RUnlock()
-
 	if !fp.started {
+		fp.mu.RUnlock()
 		return fmt.Errorf("file processor not started")
 	}
+	ctx := fp.ctx
+	fp.submitting.Add(1)
+	fp.mu.RUnlock()
+	defer fp.submitting.Done()
+
+	size := int64(len(job.Content))
+	if err := fp.budget.acquire(ctx, size); err != nil {
+		return err
+	}
 
 	select {
 	case fp.jobQueue <- job:
 		return nil
-	case <-fp.ctx.Done():
-		return fp.ctx.Err()
-	default:
-		return fmt.Errorf("job queue is full")
+	case <-ctx.Done():
+		fp.budget.release(size)
+		return ctx.Err()
+	}
+}
+
+// Stream starts the processor, submits the jobs produce generates and passes
+// each result to handle as it completes, so results are collected while files
+// are still being read. produce runs in its own goroutine and its submit
+// function blocks while the processor is saturated; handle runs in the
+// caller's goroutine.
+func (fp *FileProcessor) Stream(ctx context.Context, produce func(submit func(FileJob) error) error, handle func(ProcessingResult)) error {
+	if err := fp.Start(ctx); err != nil {
+		return err
+	}
+	defer fp.Stop()
+
+	submitted := 0
+	produced := make(chan error, 1)
+	go func() {
+		produced <- produce(func(job FileJob) error {
+			if err := fp.Submit(job); err != nil {
+				return fmt.Errorf("failed to submit job %s: %w", job.FilePath, err)
+			}
+			submitted++
+			return nil
+		})
+	}()
+
+	// submitted is only read once produce has returned
+	received := 0
+	for produced != nil || received < submitted {
+		select {
+		case result := <-fp.Results():
+			handle(result)
+			received++
+
+		case err := <-produced:
+			if err != nil {
+				return err
+			}
+			produced = nil
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
+
+	return nil
 }
 
 // Results returns a channel for receiving processing results
@@ -159,13 +237,14 @@ func (fp *FileProcessor) Stop() {
 		return
 	}
 
-	// Close job queue to signal workers to stop
-	close(fp.jobQueue)
-
-	// Cancel context
+	// Cancel context, releasing blocked submissions before the job queue closes
 	if fp.cancel != nil {
 		fp.cancel()
 	}
+	fp.submitting.Wait()
+
+	// Close job queue to signal workers to stop
+	close(fp.jobQueue)
 
 	// Wait for all workers to finish
 	fp.wg.Wait()
@@ -209,10 +288,18 @@ func (w *FileWorker) start() {
 				return
 			}
 
-			// Process the file
+			// Process the file; its content is no longer needed afterwards
 			result := w.processFile(job)
+			w.processor.budget.release(int64(len(job.Content)))
+
+			// Deliver the result if there is room, even once cancelled, so
+			// callers see why in-flight files stopped
+			select {
+			case w.resultQueue <- result:
+				continue
+			default:
+			}
 
-			// Send result (non-blocking)
 			select {
 			case w.resultQueue <- result:
 				// Result sent successfully
@@ -232,6 +319,7 @@ func (w *FileWorker) start() {
 func (w *FileWorker) processFile(job FileJob) ProcessingResult {
 	result := ProcessingResult{
 		FilePath: job.FilePath,
+		Chunk:    job.Chunk,
 		Findings: []detection.Finding{},
 		Stats: ProcessingStats{
 			BytesProcessed: int64(len(job.Content)),
@@ -262,42 +350,151 @@ func (w *FileWorker) processFile(job FileJob) ProcessingResult {
 	}
 
 	// Run all detectors on the file content
+	ctx := detection.WithCommit(w.ctx, job.Commit)
+	findings, err := w.processor.detect(ctx, job)
+	result.Error = err
+
+	// Update file path in findings and apply context validation
+	for _, finding := range findings {
+		// Matches in the overlap with the next window are reported by that window
+		if job.Chunk != nil && !job.Chunk.owns(finding) {
+			continue
+		}
+
+		// Create a copy of the finding to avoid race conditions
+		f := finding
+		f.File = job.FilePath
+
+		// Apply context validation to reduce false positives
+		validationResult, err := w.processor.contextValidator.Validate(ctx, f, string(job.Content))
+		if err == nil {
+			if !validationResult.IsValid {
+				// Skip invalid findings
+				continue
+			}
+			// Update confidence based on context validation
+			f.Confidence = float32(validationResult.Confidence)
+			f.Suppression = validationResult.Suppression
+		}
+
+		if job.Chunk != nil {
+			job.Chunk.translate(&f)
+		}
+		if f.Line >= 1 && f.Line <= len(job.Locations) {
+			location := job.Locations[f.Line-1]
+			f.Location = &location
+		}
+
+		result.Findings = append(result.Findings, f)
+	}
+
+	// Structured data is parsed whole, so windows of chunked files and
+	// extracted document text are left as they are
+	if job.Chunk == nil && job.Locations == nil {
+		result.Findings = structured.Annotate(job.FilePath, job.Content, result.Findings)
+	}
+
+	return result
+}
+
+// detect runs every detector over a job's content and returns the first
+// detector error, if any. Working tree content is looked up in the cache
+// first, and results are only cached when every detector succeeded.
+func (fp *FileProcessor) detect(ctx context.Context, job FileJob) ([]detection.Finding, error) {
 	filename := filepath.Base(job.FilePath)
-	for _, detector := range w.processor.detectors {
-		findings, err := detector.Detect(w.ctx, job.Content, filename)
+
+	// History findings carry their commit, so only working tree content is cached
+	useCache := fp.cache != nil && job.Commit == nil
+	var blobHash string
+	if useCache {
+		blobHash = cache.BlobHash(job.Content)
+		if findings, ok := fp.cache.Get(blobHash, filename); ok {
+			now := time.Now()
+			for i := range findings {
+				findings[i].DetectedAt = now
+			}
+			return findings, nil
+		}
+	}
+
+	findings, firstErr := fp.runDetectors(ctx, job.Content, filename)
+	decoded, err := fp.detectDecoded(ctx, job.Content, filename)
+	findings = append(findings, decoded...)
+	if firstErr == nil {
+		firstErr = err
+	}
+
+	if useCache && firstErr == nil {
+		// A failed write only means the content is scanned again next time
+		_ = fp.cache.Put(blobHash, filename, findings)
+	}
+
+	return findings, firstErr
+}
+
+// runDetectors runs every detector over content, returning the first
+// detector error along with the findings of the others
+func (fp *FileProcessor) runDetectors(ctx context.Context, content []byte, filename string) ([]detection.Finding, error) {
+	var findings []detection.Finding
+	var firstErr error
+	for _, detector := range fp.detectors {
+		detected, err := detector.Detect(ctx, content, filename)
 		if err != nil {
 			// Log error but continue with other detectors
-			if result.Error == nil {
-				result.Error = fmt.Errorf("detector %s failed: %w", detector.Name(), err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("detector %s failed: %w", detector.Name(), err)
 			}
 			continue
 		}
+		findings = append(findings, detected...)
+	}
+	return findings, firstErr
+}
 
-		// Update file path in findings and apply context validation
-		var validFindings []detection.Finding
-		for _, finding := range findings {
-			// Create a copy of the finding to avoid race conditions
-			f := finding
-			f.File = job.FilePath
-
-			// Apply context validation to reduce false positives
-			validationResult, err := w.processor.contextValidator.Validate(w.ctx, f, string(job.Content))
-			if err == nil {
-				if !validationResult.IsValid {
-					// Skip invalid findings
-					continue
-				}
-				// Update confidence based on context validation
-				f.Confidence = float32(validationResult.Confidence)
-			}
+// detectDecoded runs the detectors over the text decoded from base64,
+// URL-encoded and hex runs in content. Findings are placed at the start of
+// the outermost encoded run and carry the encodings undone. Values readable
+// without decoding are left to the scan of the content itself.
+func (fp *FileProcessor) detectDecoded(ctx context.Context, content []byte, filename string) ([]detection.Finding, error) {
+	var findings []detection.Finding
+	var firstErr error
+
+	// A value can be reached through more than one chain, as when a
+	// URL-encoded run holds base64 text that is also found directly
+	type key struct {
+		piType detection.PIType
+		start  int
+		match  string
+	}
+	seen := make(map[key]bool)
 
-			validFindings = append(validFindings, f)
+	for _, payload := range decode.Find(content, fp.decoding) {
+		detected, err := fp.runDetectors(ctx, payload.Text, filename)
+		if err != nil && firstErr == nil {
+			firstErr = err
 		}
 
-		result.Findings = append(result.Findings, validFindings...)
-	}
+		encoded := content[payload.Start:payload.End]
+		line, column := advance(1, 1, content[:payload.Start])
+		chain := make([]string, len(payload.Chain))
+		for i, encoding := range payload.Chain {
+			chain[i] = string(encoding)
+		}
 
-	return result
+		for _, f := range detected {
+			k := key{piType: f.Type, start: payload.Start, match: f.Match}
+			if seen[k] || bytes.Contains(encoded, []byte(f.Match)) {
+				continue
+			}
+			seen[k] = true
+
+			f.Line, f.Column = line, column
+			f.EncodingChain = chain
+			f.EncodedLength = len(encoded)
+			findings = append(findings, f)
+		}
+	}
+	return findings, firstErr
 }
 
 // BatchProcessor handles processing multiple files efficiently
@@ -325,43 +522,19 @@ func (bp *BatchProcessor) ProcessFiles(ctx context.Context, jobs []FileJob) ([]P
 		return []ProcessingResult{}, nil
 	}
 
-	// Start the processor
-	err := bp.processor.Start(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer bp.processor.Stop()
-
-	// Submit all jobs
-	for _, job := range jobs {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-			err := bp.processor.Submit(job)
-			if err != nil {
-				return nil, fmt.Errorf("failed to submit job %s: %w", job.FilePath, err)
-			}
-		}
-	}
-
-	// Collect results
 	var results []ProcessingResult
-	resultsReceived := 0
-	totalJobs := len(jobs)
-
-	for resultsReceived < totalJobs {
-		select {
-		case result := <-bp.processor.Results():
-			results = append(results, result)
-			resultsReceived++
-
-		case <-ctx.Done():
-			return results, ctx.Err()
+	err := bp.processor.Stream(ctx, func(submit func(FileJob) error) error {
+		for _, job := range jobs {
+			if err := submit(job); err != nil {
+				return err
+			}
 		}
-	}
+		return nil
+	}, func(result ProcessingResult) {
+		results = append(results, result)
+	})
 
-	return results, nil
+	return results, err
 }
 
 // Pipeline represents a configurable processing pipeline
@@ -2,8 +2,10 @@ package processing
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -12,6 +14,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MacAttak/pi-scanner/pkg/cache"
+	"github.com/MacAttak/pi-scanner/pkg/decode"
 	"github.com/MacAttak/pi-scanner/pkg/detection"
 	"github.com/MacAttak/pi-scanner/pkg/discovery"
 )
@@ -22,6 +26,7 @@ type MockDetector struct {
 	findings []detection.Finding
 	delay    time.Duration
 	failures int32 // Counter for simulating failures
+	calls    int32 // Number of Detect calls
 }
 
 func NewMockDetector(name string, findings []detection.Finding) *MockDetector {
@@ -36,6 +41,8 @@ func (m *MockDetector) Name() string {
 }
 
 func (m *MockDetector) Detect(ctx context.Context, content []byte, filename string) ([]detection.Finding, error) {
+	atomic.AddInt32(&m.calls, 1)
+
 	// Simulate processing delay
 	if m.delay > 0 {
 		select {
@@ -345,13 +352,13 @@ func TestFileProcessor_QueueCapacity(t *testing.T) {
 	config.QueueSize = queueSize
 	processor := NewFileProcessor(config, []detection.Detector{detector})
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
 	err := processor.Start(ctx)
 	require.NoError(t, err)
 	defer processor.Stop()
 
-	// Fill queue capacity
-	for i := 0; i < queueSize; i++ {
+	// Fill queue capacity behind the job the worker is processing
+	for i := 0; i <= queueSize; i++ {
 		job := FileJob{
 			FilePath: fmt.Sprintf("/test/file%d.txt", i),
 			Content:  []byte("test"),
@@ -362,16 +369,25 @@ func TestFileProcessor_QueueCapacity(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	// This should fail as queue is full
+	// A full queue applies backpressure: submission waits for the worker
 	overflowJob := FileJob{
 		FilePath: "/test/overflow.txt",
 		Content:  []byte("overflow"),
 		FileInfo: discovery.FileResult{Path: "/test/overflow.txt"},
 	}
 
+	start := time.Now()
 	err = processor.Submit(overflowJob)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "job queue is full")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Cancellation releases a blocked submission
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	err = processor.Submit(overflowJob)
+	assert.ErrorIs(t, err, context.Canceled)
 }
 
 func TestBatchProcessor_ProcessFiles(t *testing.T) {
@@ -510,6 +526,93 @@ func TestFileProcessor_EmptyContent(t *testing.T) {
 	}
 }
 
+func TestBatchProcessor_Cache(t *testing.T) {
+	detector := NewMockDetector("cached-detector", []detection.Finding{
+		{Type: detection.PITypeEmail, Match: "jane@example.com", File: "customers.csv", Line: 1, DetectorName: "cached-detector"},
+	})
+
+	fileCache, err := cache.Open(t.TempDir(), "rules")
+	require.NoError(t, err)
+
+	jobs := []FileJob{
+		{FilePath: "/repo/customers.csv", Content: []byte("jane@example.com\n")},
+		{FilePath: "/repo/copy/customers.csv", Content: []byte("jane@example.com\n")},
+		{FilePath: "/repo/history.csv", Content: []byte("jane@example.com\n"), Commit: &detection.CommitInfo{SHA: "abc123"}},
+	}
+
+	scan := func() []ProcessingResult {
+		config := DefaultProcessorConfig()
+		config.NumWorkers = 1
+		config.Cache = fileCache
+		results, err := NewBatchProcessor(NewFileProcessor(config, []detection.Detector{detector}), 10).ProcessFiles(context.Background(), jobs)
+		require.NoError(t, err)
+		return results
+	}
+
+	// The copy with the same name and content is a cache hit; history content is never cached
+	results := scan()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&detector.calls))
+
+	results = append(results, scan()...)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&detector.calls))
+
+	for _, result := range results {
+		require.Len(t, result.Findings, 1)
+		assert.Equal(t, result.FilePath, result.Findings[0].File, "cached findings take the file's own path")
+	}
+
+	hits, _ := fileCache.Stats()
+	assert.Equal(t, 3, hits)
+}
+
+func TestBatchProcessor_DecodedContent(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte(`{"email":"jane.citizen@example.com"}`))
+	escaped := url.QueryEscape("reply to john.smith@example.com")
+	content := "apiVersion: v1\nkind: Secret\ndata:\n  customer: " + secret + "\n  contact: " + escaped +
+		"\n  owner: ops@example.com%2C%20billing\n"
+	jobs := []FileJob{{FilePath: "/repo/deploy/secret.yaml", Content: []byte(content)}}
+
+	process := func(config ProcessorConfig) map[string]detection.Finding {
+		config.NumWorkers = 1
+		results, err := NewBatchProcessor(NewFileProcessor(config, []detection.Detector{detection.NewDetector()}), 10).ProcessFiles(context.Background(), jobs)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		emails := make(map[string]detection.Finding)
+		for _, f := range results[0].Findings {
+			if f.Type == detection.PITypeEmail {
+				emails[f.Match] = f
+			}
+		}
+		return emails
+	}
+
+	emails := process(DefaultProcessorConfig())
+	require.Len(t, emails, 3)
+
+	// Decoded values are reported over the encoded text holding them
+	jane := emails["jane.citizen@example.com"]
+	assert.Equal(t, []string{"base64"}, jane.EncodingChain)
+	assert.Equal(t, 4, jane.Line)
+	assert.Equal(t, 13, jane.Column)
+	assert.Equal(t, len(secret), jane.EncodedLength)
+
+	john := emails["john.smith@example.com"]
+	assert.Equal(t, []string{"url"}, john.EncodingChain)
+	assert.Equal(t, 5, john.Line)
+	assert.Equal(t, 12, john.Column)
+
+	// A value readable without decoding is only reported by the plain scan
+	assert.Nil(t, emails["ops@example.com"].EncodingChain)
+
+	// Without decoding only the plain value is found
+	config := DefaultProcessorConfig()
+	config.Decoding = decode.Limits{}
+	emails = process(config)
+	assert.Len(t, emails, 1)
+	assert.Contains(t, emails, "ops@example.com")
+}
+
 // Benchmark tests
 func BenchmarkFileProcessor_SingleFile(b *testing.B) {
 	detector := NewMockDetector("bench-detector", []detection.Finding{
@@ -73,6 +73,7 @@ type CSVRecord struct {
 	FilePath      string
 	LineNumber    int
 	ColumnNumber  int
+	Location      string // Sheet and cell or page and paragraph, for documents in place of line and column
 	PIType        string
 	PITypeDisplay string
 	Match         string
@@ -99,6 +100,10 @@ type CSVRecord struct {
 	PrivacyActIssue  bool
 	NotifiableBreach bool
 
+	// Suppression
+	Suppressed               bool
+	SuppressionJustification string
+
 	// Metadata
 	ScanID       string
 	ScanDuration time.Duration
@@ -188,6 +193,8 @@ func (e *CSVExporter) getHeaders() []string {
 		"APRA Relevant",
 		"Privacy Act Issue",
 		"Notifiable Breach",
+		"Suppressed",
+		"Suppression Justification",
 	)
 
 	if e.includeMetadata {
@@ -199,18 +206,26 @@ func (e *CSVExporter) getHeaders() []string {
 		)
 	}
 
+	// Appended last so existing columns keep their positions
+	headers = append(headers, "Location")
+
 	return headers
 }
 
 // recordToRow converts a CSVRecord to a CSV row
 func (e *CSVExporter) recordToRow(record CSVRecord) []string {
+	line, column := strconv.Itoa(record.LineNumber), strconv.Itoa(record.ColumnNumber)
+	if record.Location != "" {
+		line, column = "", ""
+	}
+
 	row := []string{
 		record.Timestamp.Format(e.dateFormat),
 		record.Repository,
 		record.Branch,
 		record.FilePath,
-		strconv.Itoa(record.LineNumber),
-		strconv.Itoa(record.ColumnNumber),
+		line,
+		column,
 		record.PIType,
 		record.PITypeDisplay,
 		strconv.FormatBool(record.Validated),
@@ -240,6 +255,8 @@ func (e *CSVExporter) recordToRow(record CSVRecord) []string {
 		strconv.FormatBool(record.APRARelevant),
 		strconv.FormatBool(record.PrivacyActIssue),
 		strconv.FormatBool(record.NotifiableBreach),
+		strconv.FormatBool(record.Suppressed),
+		record.SuppressionJustification,
 	)
 
 	if e.includeMetadata {
@@ -251,6 +268,8 @@ func (e *CSVExporter) recordToRow(record CSVRecord) []string {
 		)
 	}
 
+	row = append(row, record.Location)
+
 	return row
 }
 
@@ -274,9 +293,18 @@ func (e *CSVExporter) findingToRecord(finding detection.Finding, metadata Export
 		ToolVersion:   metadata.ToolVersion,
 	}
 
+	if finding.Location != nil {
+		record.Location = finding.Location.String()
+	}
+
 	// Mask the match value
 	record.MaskedMatch = maskSensitiveData(finding.Match, string(finding.Type))
 
+	if finding.Suppression != nil {
+		record.Suppressed = true
+		record.SuppressionJustification = finding.Suppression.Justification
+	}
+
 	// Add placeholder values for fields that would come from scoring
 	// In a real implementation, these would be populated from the risk assessment
 	record.ConfidenceScore = 0.0
@@ -287,6 +315,11 @@ func (e *CSVExporter) findingToRecord(finding detection.Finding, metadata Export
 	return record
 }
 
+// GetPITypeDisplay returns a human-readable display name for a PI type
+func GetPITypeDisplay(piType detection.PIType) string {
+	return getPITypeDisplay(piType)
+}
+
 // getPITypeDisplay returns a human-readable display name for PI types
 func getPITypeDisplay(piType detection.PIType) string {
 	displays := map[detection.PIType]string{
@@ -302,6 +335,12 @@ func getPITypeDisplay(piType detection.PIType) string {
 		detection.PITypePassport:      "Passport Number",
 		detection.PITypeDriverLicense: "Driver License",
 		detection.PITypeIP:            "IP Address",
+		detection.PITypeIHI:           "Individual Healthcare Identifier",
+		detection.PITypeHPII:          "Healthcare Provider Identifier (Individual)",
+		detection.PITypeHPIO:          "Healthcare Provider Identifier (Organisation)",
+		detection.PITypeCRN:           "Centrelink Customer Reference Number",
+		detection.PITypeDVA:           "DVA File Number",
+		detection.PITypeImmiCard:      "ImmiCard Number",
 	}
 
 	if display, exists := displays[piType]; exists {
@@ -287,6 +287,53 @@ func TestCSVExporter_ExportFindings(t *testing.T) {
 	assert.Equal(t, "TFN", firstFinding[typeIndex])
 }
 
+func TestCSVExporter_Suppressed(t *testing.T) {
+	findings := []detection.Finding{
+		{
+			Type:        detection.PITypeABN,
+			Match:       "51824753556",
+			File:        "src/company.go",
+			Line:        3,
+			Suppression: &detection.Suppression{Source: detection.SuppressionAllowlist, Justification: "Company ABN"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewCSVExporter().ExportFindings(&buf, findings, ExportMetadata{Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	headers := records[0]
+	assert.Equal(t, "true", records[1][indexOf(headers, "Suppressed")])
+	assert.Equal(t, "Company ABN", records[1][indexOf(headers, "Suppression Justification")])
+}
+
+func TestCSVExporter_DocumentLocations(t *testing.T) {
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "exports/customers.xlsx", Line: 7, Column: 1,
+			Location: &detection.DocumentLocation{Sheet: "Customers", Cell: "B4"}},
+		{Type: detection.PITypeEmail, Match: "jane@example.com", File: "src/config.go", Line: 12, Column: 4},
+	}
+
+	var buf bytes.Buffer
+	err := NewCSVExporter().ExportFindings(&buf, findings, ExportMetadata{Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	// Documents report their location in place of the extracted text's line and column
+	headers := records[0]
+	line, column, location := indexOf(headers, "Line"), indexOf(headers, "Column"), indexOf(headers, "Location")
+	assert.Equal(t, len(headers)-1, location, "new columns are appended")
+	assert.Equal(t, []string{"", "", "Customers!B4"}, []string{records[1][line], records[1][column], records[1][location]})
+	assert.Equal(t, []string{"12", "4", ""}, []string{records[2][line], records[2][column], records[2][location]})
+}
+
 func TestCSVSummaryExporter_ExportSummary(t *testing.T) {
 	summary := ScanSummary{
 		TotalFindings:  100,
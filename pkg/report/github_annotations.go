@@ -0,0 +1,100 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// GitHubAnnotationExporter writes findings as GitHub Actions workflow
+// commands. When printed by a workflow step GitHub shows them as annotations
+// on the affected lines of a pull request.
+type GitHubAnnotationExporter struct {
+	baseURI string
+}
+
+// NewGitHubAnnotationExporter creates a new GitHub annotation exporter
+func NewGitHubAnnotationExporter() *GitHubAnnotationExporter {
+	return &GitHubAnnotationExporter{}
+}
+
+// SetBaseURI sets the directory file paths are made relative to
+func (e *GitHubAnnotationExporter) SetBaseURI(baseURI string) {
+	e.baseURI = strings.TrimSuffix(strings.ReplaceAll(baseURI, "\\", "/"), "/")
+}
+
+// Export writes one annotation per finding. Suppressed findings and findings
+// already accepted in a baseline are left out.
+func (e *GitHubAnnotationExporter) Export(w io.Writer, records []IntegrationRecord) error {
+	for _, ir := range records {
+		f := ir.Finding
+		if f.Suppressed() || f.BaselineState == detection.BaselineStateUnchanged || f.BaselineState == detection.BaselineStateAbsent {
+			continue
+		}
+
+		riskLevel := string(f.RiskLevel)
+		if ir.RiskAssessment != nil {
+			riskLevel = string(ir.RiskAssessment.RiskLevel)
+		}
+
+		// Document findings annotate the whole file, naming the cell or page
+		properties := []string{"file=" + escapeAnnotationProperty(e.relativePath(f.File))}
+		if f.Location == nil {
+			properties = append(properties, fmt.Sprintf("line=%d", f.Line))
+		}
+		if f.Column > 0 && f.Location == nil {
+			properties = append(properties,
+				fmt.Sprintf("col=%d", f.Column),
+				fmt.Sprintf("endColumn=%d", f.Column+len(f.Match)))
+		}
+		properties = append(properties, "title="+escapeAnnotationProperty(getPITypeDisplay(f.Type)+" detected"))
+
+		message := fmt.Sprintf("%s detected: %s (%s risk", getPITypeDisplay(f.Type), maskSensitiveData(f.Match, string(f.Type)), riskLevel)
+		if f.Validated {
+			message += ", checksum validated"
+		}
+		message += ")"
+		if f.Location != nil {
+			message += " at " + f.Location.String()
+		}
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", annotationLevel(riskLevel), strings.Join(properties, ","), escapeAnnotationData(message)); err != nil {
+			return fmt.Errorf("failed to write annotation: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// relativePath makes a finding path relative to the base URI
+func (e *GitHubAnnotationExporter) relativePath(file string) string {
+	file = strings.ReplaceAll(file, "\\", "/")
+	if e.baseURI != "" {
+		file = strings.TrimPrefix(file, e.baseURI+"/")
+	}
+	return file
+}
+
+// annotationLevel maps a risk level to a workflow command
+func annotationLevel(riskLevel string) string {
+	switch strings.ToUpper(riskLevel) {
+	case string(detection.RiskLevelCritical), string(detection.RiskLevelHigh):
+		return "error"
+	case string(detection.RiskLevelMedium):
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeAnnotationData escapes a workflow command message
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
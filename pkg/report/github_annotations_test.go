@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubAnnotationExporter_Export(t *testing.T) {
+	records := []IntegrationRecord{
+		{Finding: detection.Finding{Type: detection.PITypeTFN, Match: "123456782", File: "/repo/src/pay,roll.go", Line: 3, Column: 8,
+			RiskLevel: detection.RiskLevelHigh, Validated: true}},
+		{Finding: detection.Finding{Type: detection.PITypeEmail, Match: "jane@example.com", File: "/repo/config.yaml", Line: 5,
+			RiskLevel: detection.RiskLevelLow},
+			RiskAssessment: &scoring.RiskAssessment{RiskLevel: scoring.RiskLevelMedium}},
+		{Finding: detection.Finding{Type: detection.PITypeTFN, Match: "876543210", File: "/repo/a.go", Line: 1,
+			Suppression: &detection.Suppression{Source: detection.SuppressionInline}}},
+		{Finding: detection.Finding{Type: detection.PITypeTFN, Match: "876543210", File: "/repo/b.go", Line: 1,
+			BaselineState: detection.BaselineStateUnchanged}},
+		{Finding: detection.Finding{Type: detection.PITypeTFN, Match: "123456782", File: "/repo/exports/customers.xlsx", Line: 4, Column: 1,
+			RiskLevel: detection.RiskLevelHigh, Location: &detection.DocumentLocation{Sheet: "Customers", Cell: "B2"}}},
+	}
+
+	exporter := NewGitHubAnnotationExporter()
+	exporter.SetBaseURI("/repo/")
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, records))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Equal(t, "::error file=src/pay%2Croll.go,line=3,col=8,endColumn=17,title=Tax File Number detected::Tax File Number detected: 123****82 (HIGH risk, checksum validated)", string(lines[0]))
+	assert.Equal(t, "::warning file=config.yaml,line=5,title=Email Address detected::Email Address detected: "+MaskSensitiveData("jane@example.com", "EMAIL")+" (MEDIUM risk)", string(lines[1]))
+	assert.Equal(t, "::error file=exports/customers.xlsx,title=Tax File Number detected::Tax File Number detected: 123****82 (HIGH risk) at Customers!B2", string(lines[2]))
+	assert.NotContains(t, buf.String(), "123456782")
+}
+
+func TestEscapeAnnotation(t *testing.T) {
+	assert.Equal(t, "50%25%0Anext", escapeAnnotationData("50%\nnext"))
+	assert.Equal(t, "a%3Ab%2Cc", escapeAnnotationProperty("a:b,c"))
+}
@@ -78,6 +78,8 @@ type Finding struct {
 	Context         string             `json:"context"`
 	Validated       bool               `json:"validated"`
 	IsTestData      bool               `json:"is_test_data"`
+	Suppressed      bool               `json:"suppressed"`
+	Suppression     string             `json:"suppression,omitempty"` // Justification for suppressed findings
 	RiskAssessment  RiskAssessmentInfo `json:"risk_assessment"`
 	Mitigations     []Mitigation       `json:"mitigations"`
 }
@@ -170,6 +172,9 @@ func GetTemplateFuncMap() template.FuncMap {
 			return t.Format("2 Jan 2006 15:04:05 MST")
 		},
 		"formatDate": func(t time.Time) string {
+			if t.IsZero() {
+				return "Unknown"
+			}
 			return t.Format("2 Jan 2006")
 		},
 		"formatPercent": func(val float64) string {
@@ -277,6 +282,11 @@ func GetHTMLTemplate() (*template.Template, error) {
 	return tmpl, nil
 }
 
+// MaskSensitiveData masks a PI value for display in reports
+func MaskSensitiveData(value string, piType string) string {
+	return maskSensitiveData(value, piType)
+}
+
 // maskSensitiveData masks PI data for display
 func maskSensitiveData(value string, piType string) string {
 	if len(value) == 0 {
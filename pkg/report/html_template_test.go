@@ -269,6 +269,12 @@ func TestHTMLTemplateFunctions(t *testing.T) {
 			data:     time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC),
 			expected: "15 Jan 2024",
 		},
+		{
+			name:     "formatDate unknown",
+			template: `{{formatDate .}}`,
+			data:     time.Time{},
+			expected: "Unknown",
+		},
 		{
 			name:     "formatPercent",
 			template: `{{formatPercent .}}`,
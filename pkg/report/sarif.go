@@ -87,10 +87,20 @@ type SARIFResult struct {
 	Fingerprints        map[string]string      `json:"fingerprints,omitempty"`
 	CodeFlows           []SARIFCodeFlow        `json:"codeFlows,omitempty"`
 	Fixes               []SARIFFix             `json:"fixes,omitempty"`
+	BaselineState       string                 `json:"baselineState,omitempty"`
+	Suppressions        []SARIFSuppression     `json:"suppressions,omitempty"`
 	Properties          map[string]interface{} `json:"properties,omitempty"`
 	Rank                float64                `json:"rank,omitempty"`
 }
 
+// SARIFSuppression records that a result was accepted
+type SARIFSuppression struct {
+	Kind          string                 `json:"kind"` // inSource or external
+	Status        string                 `json:"status,omitempty"`
+	Justification string                 `json:"justification,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
+}
+
 // SARIFMessage contains the result message
 type SARIFMessage struct {
 	Text      string   `json:"text,omitempty"`
@@ -102,7 +112,7 @@ type SARIFMessage struct {
 // SARIFLocation represents a location in code
 type SARIFLocation struct {
 	PhysicalLocation SARIFPhysicalLocation  `json:"physicalLocation,omitempty"`
-	LogicalLocation  SARIFLogicalLocation   `json:"logicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
 	Message          *SARIFMessage          `json:"message,omitempty"`
 	Annotations      []SARIFAnnotation      `json:"annotations,omitempty"`
 	Properties       map[string]interface{} `json:"properties,omitempty"`
@@ -111,7 +121,7 @@ type SARIFLocation struct {
 // SARIFPhysicalLocation represents a physical location in a file
 type SARIFPhysicalLocation struct {
 	ArtifactLocation SARIFArtifactLocation  `json:"artifactLocation"`
-	Region           SARIFRegion            `json:"region,omitempty"`
+	Region           *SARIFRegion           `json:"region,omitempty"`
 	ContextRegion    SARIFRegion            `json:"contextRegion,omitempty"`
 	Properties       map[string]interface{} `json:"properties,omitempty"`
 }
@@ -562,44 +572,91 @@ func (e *SARIFExporter) convertFindings(findings []detection.Finding) []SARIFRes
 				ArtifactLocation: SARIFArtifactLocation{
 					URI: e.normalizeURI(finding.File),
 				},
-				Region: SARIFRegion{
-					StartLine:   finding.Line,
-					StartColumn: finding.Column,
-					EndLine:     finding.Line,
-					EndColumn:   finding.Column + len(finding.Match),
-				},
 			},
 		}
 
-		// Add code snippet if available
-		if finding.Context != "" {
-			location.PhysicalLocation.Region.Snippet = &SARIFContent{
-				Text: finding.Context,
+		// Documents have no lines to point at, so their findings are placed
+		// by sheet and cell or page and paragraph instead
+		where := ""
+		if finding.Location != nil {
+			location.LogicalLocations = []SARIFLogicalLocation{{
+				Name: finding.Location.String(),
+				Kind: "element",
+			}}
+			location.Properties = map[string]interface{}{
+				"documentLocation": finding.Location,
+			}
+			where = " at " + finding.Location.String()
+		} else {
+			location.PhysicalLocation.Region = &SARIFRegion{
+				StartLine:   finding.Line,
+				StartColumn: finding.Column,
+				EndLine:     finding.Line,
+				EndColumn:   finding.Column + len(finding.Match),
+			}
+
+			// Decoded values are shown over the encoded text holding them
+			if finding.EncodedLength > 0 {
+				location.PhysicalLocation.Region.EndColumn = finding.Column + finding.EncodedLength
+				where = fmt.Sprintf(" (decoded from %s)", strings.Join(finding.EncodingChain, ", "))
+			}
+
+			// Add code snippet if available
+			if finding.Context != "" {
+				location.PhysicalLocation.Region.Snippet = &SARIFContent{
+					Text: finding.Context,
+				}
 			}
 		}
 
-		// Create fingerprints for deduplication
+		// Create fingerprints for deduplication. The value hash ignores the
+		// line so a finding keeps its identity when a later push moves it.
 		fingerprints := map[string]string{
 			"primaryLocationLineHash": e.createFingerprint(finding),
+			"piValueHash/v1":          ValueFingerprint(finding, e.baseURI),
+		}
+
+		// Message and properties never expose the value; baseline entries
+		// for absent findings only hold it masked already
+		match := maskSensitiveData(finding.Match, string(finding.Type))
+		if finding.BaselineState == detection.BaselineStateAbsent {
+			match = finding.Match
 		}
 
 		results[i] = SARIFResult{
 			RuleID: ruleID,
 			Level:  e.getDefaultLevel(finding.Type),
 			Message: SARIFMessage{
-				Text: fmt.Sprintf("%s detected: %s",
+				Text: fmt.Sprintf("%s detected: %s%s",
 					getPITypeDisplay(finding.Type),
-					maskSensitiveData(finding.Match, string(finding.Type))),
+					match, where),
 			},
 			Locations:           []SARIFLocation{location},
 			PartialFingerprints: fingerprints,
+			BaselineState:       string(finding.BaselineState),
 			Properties: map[string]interface{}{
 				"piType":    string(finding.Type),
 				"validated": finding.Validated,
-				"match":     maskSensitiveData(finding.Match, string(finding.Type)),
+				"match":     match,
 			},
 		}
 
+		if finding.Suppression != nil {
+			results[i].Suppressions = []SARIFSuppression{e.createSuppression(finding.Suppression)}
+		}
+
+		// Structured data findings name the field holding the value, and a
+		// CSV column of values reported once counts its rows
+		if finding.Field != "" {
+			results[i].Properties["field"] = finding.Field
+		}
+		if finding.RecordCount > 0 {
+			results[i].Properties["recordCount"] = finding.RecordCount
+		}
+		if len(finding.EncodingChain) > 0 {
+			results[i].Properties["encodingChain"] = finding.EncodingChain
+		}
+
 		// Add rule index for efficiency
 		if idx := e.getRuleIndex(finding.Type); idx >= 0 {
 			results[i].RuleIndex = idx
@@ -609,10 +666,37 @@ func (e *SARIFExporter) convertFindings(findings []detection.Finding) []SARIFRes
 	return results
 }
 
+// createSuppression maps an inline comment or allowlist entry to a SARIF suppression
+func (e *SARIFExporter) createSuppression(suppression *detection.Suppression) SARIFSuppression {
+	result := SARIFSuppression{
+		Kind:          "external",
+		Status:        "accepted",
+		Justification: suppression.Justification,
+	}
+
+	if suppression.Source == detection.SuppressionInline {
+		result.Kind = "inSource"
+	}
+
+	if suppression.Expires != nil {
+		result.Properties = map[string]interface{}{
+			"expires": suppression.Expires.Format("2006-01-02"),
+		}
+	}
+
+	return result
+}
+
 // createFixes creates SARIF fixes from mitigations
 func (e *SARIFExporter) createFixes(finding detection.Finding, mitigations []scoring.Mitigation) []SARIFFix {
 	fixes := make([]SARIFFix, 0, len(mitigations))
 
+	// Document text cannot be redacted with a text replacement, and replacing
+	// the decoded match would leave the rest of an encoded value behind
+	if finding.Location != nil || finding.EncodedLength > 0 {
+		return fixes
+	}
+
 	for _, mitigation := range mitigations {
 		if mitigation.Priority == "CRITICAL" || mitigation.Priority == "HIGH" {
 			fix := SARIFFix{
@@ -758,13 +842,42 @@ func (e *SARIFExporter) normalizeURI(filePath string) string {
 }
 
 func (e *SARIFExporter) createFingerprint(finding detection.Finding) string {
+	// Findings compared against a baseline carry the fingerprint they were matched on
+	if finding.Fingerprint != "" {
+		return finding.Fingerprint
+	}
+	return Fingerprint(finding, e.baseURI)
+}
+
+// Fingerprint returns a stable identifier for a finding. The file path is made
+// relative to baseURI so the same finding in different checkouts of a
+// repository has the same fingerprint.
+func Fingerprint(finding detection.Finding, baseURI string) string {
+	file := strings.ReplaceAll(finding.File, "\\", "/")
+	if baseURI != "" {
+		base := strings.TrimSuffix(strings.ReplaceAll(baseURI, "\\", "/"), "/") + "/"
+		file = strings.TrimPrefix(file, base)
+	}
+
 	// Create a unique fingerprint for the finding
 	data := fmt.Sprintf("%s:%s:%d:%s",
-		finding.File,
+		file,
 		finding.Type,
 		finding.Line,
 		finding.Match)
 
-	// Simple hash for demonstration - in production use crypto hash
+	return fmt.Sprintf("%x", uuid.NewSHA1(uuid.NameSpaceURL, []byte(data)))
+}
+
+// ValueFingerprint identifies a PI value within a file regardless of the line
+// it is on, so results can be tracked across pushes that move code around
+func ValueFingerprint(finding detection.Finding, baseURI string) string {
+	file := strings.ReplaceAll(finding.File, "\\", "/")
+	if baseURI != "" {
+		base := strings.TrimSuffix(strings.ReplaceAll(baseURI, "\\", "/"), "/") + "/"
+		file = strings.TrimPrefix(file, base)
+	}
+
+	data := fmt.Sprintf("%s:%s:%s", file, finding.Type, finding.Match)
 	return fmt.Sprintf("%x", uuid.NewSHA1(uuid.NameSpaceURL, []byte(data)))
 }
@@ -353,7 +353,7 @@ func TestSARIFReport_ValidJSON(t *testing.T) {
 									ArtifactLocation: SARIFArtifactLocation{
 										URI: "test.go",
 									},
-									Region: SARIFRegion{
+									Region: &SARIFRegion{
 										StartLine: 1,
 									},
 								},
@@ -456,6 +456,191 @@ func TestSARIFExporter_CreateFingerprint(t *testing.T) {
 	assert.Equal(t, fp1, fp3)
 }
 
+func TestFingerprint_RelativeToBaseURI(t *testing.T) {
+	finding := detection.Finding{Type: detection.PITypeTFN, Match: "123456782", File: "/tmp/clone-1/src/app.go", Line: 7}
+	moved := finding
+	moved.File = "/tmp/clone-2/src/app.go"
+
+	assert.Equal(t, Fingerprint(finding, "/tmp/clone-1"), Fingerprint(moved, "/tmp/clone-2/"))
+	assert.NotEqual(t, Fingerprint(finding, ""), Fingerprint(moved, ""))
+
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	exporter.SetBaseURI("/tmp/clone-1")
+	assert.Equal(t, Fingerprint(finding, "/tmp/clone-1"), exporter.createFingerprint(finding))
+}
+
+func TestSARIFExporter_Suppressions(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	expires := time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC)
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "a.go", Line: 1,
+			Suppression: &detection.Suppression{Source: detection.SuppressionInline, Justification: "ATO published value"}},
+		{Type: detection.PITypeABN, Match: "51824753556", File: "b.go", Line: 2,
+			Suppression: &detection.Suppression{Source: detection.SuppressionAllowlist, Justification: "Company ABN", Expires: &expires}},
+		{Type: detection.PITypeTFN, Match: "876543210", File: "c.go", Line: 3},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, findings, ExportMetadata{Timestamp: time.Now()}))
+
+	var report SARIFReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 3)
+
+	require.Len(t, results[0].Suppressions, 1)
+	assert.Equal(t, "inSource", results[0].Suppressions[0].Kind)
+	assert.Equal(t, "accepted", results[0].Suppressions[0].Status)
+	assert.Equal(t, "ATO published value", results[0].Suppressions[0].Justification)
+
+	require.Len(t, results[1].Suppressions, 1)
+	assert.Equal(t, "external", results[1].Suppressions[0].Kind)
+	assert.Equal(t, "2030-06-30", results[1].Suppressions[0].Properties["expires"])
+
+	assert.Empty(t, results[2].Suppressions)
+}
+
+func TestSARIFExporter_DocumentLocations(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "exports/customers.xlsx", Line: 7, Column: 1,
+			Location: &detection.DocumentLocation{Sheet: "Q3 Payroll", Cell: "B4"}},
+		{Type: detection.PITypeEmail, Match: "jane@example.com", File: "letters/welcome.pdf", Line: 3, Column: 10,
+			Location: &detection.DocumentLocation{Page: 2, Paragraph: 1}},
+		{Type: detection.PITypeTFN, Match: "876543210", File: "c.go", Line: 3, Column: 5},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, findings, ExportMetadata{Timestamp: time.Now()}))
+
+	var report SARIFReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 3)
+
+	// Document findings have no region, only a logical location
+	location := results[0].Locations[0]
+	assert.Equal(t, "exports/customers.xlsx", location.PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, location.PhysicalLocation.Region)
+	require.Len(t, location.LogicalLocations, 1)
+	assert.Equal(t, "'Q3 Payroll'!B4", location.LogicalLocations[0].Name)
+	assert.Equal(t, map[string]interface{}{"sheet": "Q3 Payroll", "cell": "B4"}, location.Properties["documentLocation"])
+	assert.Contains(t, results[0].Message.Text, "at 'Q3 Payroll'!B4")
+
+	assert.Equal(t, "page 2, paragraph 1", results[1].Locations[0].LogicalLocations[0].Name)
+
+	require.NotNil(t, results[2].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, 3, results[2].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Empty(t, results[2].Locations[0].LogicalLocations)
+}
+
+func TestSARIFExporter_StructuredFields(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "exports/payroll.csv", Line: 2, Column: 12,
+			Field: "tfn", RecordCount: 10000},
+		{Type: detection.PITypeEmail, Match: "jane@example.com", File: "customers.json", Line: 4, Column: 18,
+			Field: "$.customers[3].email"},
+		{Type: detection.PITypeTFN, Match: "876543210", File: "c.go", Line: 3, Column: 5},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, findings, ExportMetadata{Timestamp: time.Now()}))
+
+	var report SARIFReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "tfn", results[0].Properties["field"])
+	assert.Equal(t, float64(10000), results[0].Properties["recordCount"])
+	assert.Equal(t, "$.customers[3].email", results[1].Properties["field"])
+	assert.NotContains(t, results[1].Properties, "recordCount")
+	assert.NotContains(t, results[2].Properties, "field")
+}
+
+func TestSARIFExporter_DecodedValues(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	findings := []detection.Finding{
+		{Type: detection.PITypeEmail, Match: "jane@example.com", File: "deploy/secret.yaml", Line: 4, Column: 13,
+			EncodingChain: []string{"url", "base64"}, EncodedLength: 48},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, findings, ExportMetadata{Timestamp: time.Now()}))
+
+	var report SARIFReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	result := report.Runs[0].Results[0]
+	region := result.Locations[0].PhysicalLocation.Region
+	require.NotNil(t, region)
+	assert.Equal(t, 13, region.StartColumn)
+	assert.Equal(t, 61, region.EndColumn)
+	assert.Contains(t, result.Message.Text, "(decoded from url, base64)")
+	assert.Equal(t, []interface{}{"url", "base64"}, result.Properties["encodingChain"])
+}
+
+func TestSARIFExporter_CreateFixes(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	mitigations := []scoring.Mitigation{{Title: "Remove hardcoded value", Description: "Move it to a secret store", Priority: "HIGH"}}
+
+	tests := []struct {
+		name    string
+		finding detection.Finding
+		fixes   int
+	}{
+		{
+			name:    "source file",
+			finding: detection.Finding{Type: detection.PITypeTFN, Match: "123456782", File: "src/payroll.go", Line: 3, Column: 7},
+			fixes:   1,
+		},
+		{
+			name: "document",
+			finding: detection.Finding{Type: detection.PITypeTFN, Match: "123456782", File: "exports/payroll.xlsx", Line: 3, Column: 7,
+				Location: &detection.DocumentLocation{Sheet: "Payroll", Cell: "B2"}},
+		},
+		{
+			name: "decoded value",
+			finding: detection.Finding{Type: detection.PITypeEmail, Match: "jane@example.com", File: "deploy/secret.yaml", Line: 4, Column: 13,
+				EncodingChain: []string{"base64"}, EncodedLength: 24},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, exporter.createFixes(tt.finding, mitigations), tt.fixes)
+		})
+	}
+}
+
+func TestSARIFExporter_BaselineState(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "a.go", Line: 1, BaselineState: detection.BaselineStateNew},
+		{Type: detection.PITypeTFN, Match: "123****82", File: "b.go", Line: 2, BaselineState: detection.BaselineStateAbsent, Fingerprint: "abc123"},
+		{Type: detection.PITypeTFN, Match: "876543210", File: "c.go", Line: 3},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, findings, ExportMetadata{Timestamp: time.Now()}))
+
+	var report SARIFReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.Equal(t, "new", results[0].BaselineState)
+	assert.Equal(t, "absent", results[1].BaselineState)
+	assert.Equal(t, "abc123", results[1].PartialFingerprints["primaryLocationLineHash"])
+	assert.Equal(t, "123****82", results[1].Properties["match"])
+	assert.Empty(t, results[2].BaselineState)
+	assert.NotContains(t, buf.String(), `"baselineState": ""`)
+}
+
 func TestSARIFExporter_PropertiesAndTags(t *testing.T) {
 	findings := []detection.Finding{
 		{
@@ -612,3 +797,25 @@ func BenchmarkSARIFExporter_ExportWithRiskAssessment(b *testing.B) {
 		}
 	}
 }
+
+func TestSARIFExporter_ValueFingerprint(t *testing.T) {
+	exporter := NewSARIFExporter("Test", "1.0", "")
+	exporter.SetBaseURI("/repo")
+
+	moved := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "/repo/a.go", Line: 3},
+		{Type: detection.PITypeTFN, Match: "123456782", File: "/repo/a.go", Line: 9},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(&buf, moved, ExportMetadata{Timestamp: time.Now()}))
+
+	var report SARIFReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 2)
+	assert.NotEqual(t, results[0].PartialFingerprints["primaryLocationLineHash"], results[1].PartialFingerprints["primaryLocationLineHash"])
+	assert.Equal(t, results[0].PartialFingerprints["piValueHash/v1"], results[1].PartialFingerprints["piValueHash/v1"])
+	assert.Equal(t, ValueFingerprint(moved[0], "/repo"), ValueFingerprint(detection.Finding{Type: detection.PITypeTFN, Match: "123456782", File: "/checkout/a.go"}, "/checkout"))
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// StagedChanges returns the lines added in the index of a local repository,
+// as shown by `git diff --cached`. Line numbers refer to the staged version of
+// each file. Deleted and binary files contribute no added lines.
+func StagedChanges(ctx context.Context, localPath string) ([]FileChange, error) {
+	return diffChanges(ctx, localPath, "--cached")
+}
+
+// ReadStagedFile returns the content of a file in the index
+func ReadStagedFile(ctx context.Context, localPath, path string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", localPath, "show", ":"+path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staged %s: %w", path, err)
+	}
+	return output, nil
+}
+
+// DiffRange is a parsed revision range such as "main..feature"
+type DiffRange struct {
+	Base      string
+	Head      string
+	MergeBase bool // "base...head": compare head with its merge base with base
+}
+
+// ParseDiffRange parses "base..head" or "base...head". An empty head means HEAD.
+func ParseDiffRange(spec string) (DiffRange, error) {
+	separator := ".."
+	if strings.Contains(spec, "...") {
+		separator = "..."
+	}
+
+	base, head, ok := strings.Cut(spec, separator)
+	if !ok || base == "" {
+		return DiffRange{}, fmt.Errorf("invalid diff range %q (expected base..head)", spec)
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+
+	return DiffRange{Base: base, Head: head, MergeBase: separator == "..."}, nil
+}
+
+// DiffChanges returns the lines head adds relative to base. Line numbers
+// refer to the file at head. With mergeBase set head is compared with its
+// merge base with base, as in a pull request.
+func DiffChanges(ctx context.Context, localPath, base, head string, mergeBase bool) ([]FileChange, error) {
+	if mergeBase {
+		return diffChanges(ctx, localPath, base+"..."+head)
+	}
+	return diffChanges(ctx, localPath, base, head)
+}
+
+// diffChanges runs git diff with the given arguments and returns the added lines
+func diffChanges(ctx context.Context, localPath string, args ...string) ([]FileChange, error) {
+	gitArgs := append([]string{
+		"-C", localPath, "-c", "core.quotepath=off",
+		"diff", "--no-color", "--no-ext-diff", "--unified=0", "--diff-filter=d",
+	}, args...)
+	gitArgs = append(gitArgs, "--")
+
+	cmd := exec.CommandContext(ctx, "git", gitArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w (output: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var parser diffParser
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), len(output)+1)
+	for scanner.Scan() {
+		parser.parseLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git diff: %w", err)
+	}
+
+	return parser.finish(), nil
+}
+
+// HooksDir returns the directory git runs hooks from for a local repository,
+// honouring core.hooksPath
+func HooksDir(ctx context.Context, localPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", localPath, "rev-parse", "--git-path", "hooks")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git hooks directory: %w", err)
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(localPath, dir)
+	}
+	return dir, nil
+}
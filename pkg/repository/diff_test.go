@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagedChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+
+	git("init", "-q")
+
+	// Staged changes work before the first commit
+	write("config.go", "package config\n\nconst a = 1\nconst b = 2\n")
+	git("add", ".")
+	changes, err := StagedChanges(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Len(t, changes[0].Added, 4)
+
+	git("commit", "-q", "-m", "Add config")
+
+	write("config.go", "package config\n\nconst a = 1\nconst tfn = \"123456782\"\nconst b = 2\n")
+	write("constants.go", "package config\n")
+	git("add", ".")
+
+	// Unstaged edits are not reported
+	write("config.go", "package config\n\nconst a = 1\nconst tfn = \"123456782\"\nconst b = 2\nconst unstaged = 3\n")
+
+	changes, err = StagedChanges(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "config.go", changes[0].Path)
+	assert.Equal(t, []AddedLine{{Number: 4, Content: "const tfn = \"123456782\""}}, changes[0].Added)
+	assert.Equal(t, "constants.go", changes[1].Path)
+
+	dir, err := HooksDir(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".git", "hooks"), dir)
+}
+
+func TestParseDiffRange(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    DiffRange
+		wantErr bool
+	}{
+		{spec: "main..feature", want: DiffRange{Base: "main", Head: "feature"}},
+		{spec: "origin/main...HEAD", want: DiffRange{Base: "origin/main", Head: "HEAD", MergeBase: true}},
+		{spec: "v1.0..", want: DiffRange{Base: "v1.0", Head: "HEAD"}},
+		{spec: "main", wantErr: true},
+		{spec: "..feature", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseDiffRange(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiffChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return string(output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+
+	git("init", "-q", "-b", "main")
+	write("config.go", "package config\n\nconst a = 1\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add config")
+
+	git("checkout", "-q", "-b", "feature")
+	write("config.go", "package config\n\nconst a = 1\nconst tfn = \"123456782\"\n")
+	git("commit", "-q", "-am", "Add tfn")
+
+	// A later change on main is not part of the merge-base diff
+	git("checkout", "-q", "main")
+	write("readme.md", "# Config\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add readme")
+
+	changes, err := DiffChanges(context.Background(), root, "main", "feature", true)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "config.go", changes[0].Path)
+	assert.Equal(t, []AddedLine{{Number: 4, Content: "const tfn = \"123456782\""}}, changes[0].Added)
+
+	// A two-dot diff compares the trees directly, so the readme shows as deleted
+	// and is skipped
+	changes, err = DiffChanges(context.Background(), root, "main", "feature", false)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "config.go", changes[0].Path)
+
+	head := git("rev-parse", "feature")
+	resolved, err := NewGitHubManager(DefaultGitHubConfig()).ResolveRefs(context.Background(), root, "feature")
+	require.NoError(t, err)
+	assert.Equal(t, []string{strings.TrimSpace(head)}, resolved)
+}
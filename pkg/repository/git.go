@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ciConfigPaths are the files and directories that configure CI/CD pipelines
+// on the common hosting services and build servers
+var ciConfigPaths = []string{
+	".github/workflows",
+	".gitlab-ci.yml",
+	"azure-pipelines.yml",
+	"bitbucket-pipelines.yml",
+	"Jenkinsfile",
+	".circleci/config.yml",
+	".travis.yml",
+	".drone.yml",
+	".buildkite",
+	"cloudbuild.yaml",
+}
+
+// GetRemoteURL returns the origin remote URL of a local git checkout. Any
+// credentials embedded in an HTTP(S) remote are removed.
+func GetRemoteURL(localPath string) (string, error) {
+	cmd := exec.Command("git", "-C", localPath, "remote", "get-url", "origin")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read origin remote: %w", err)
+	}
+
+	remote := strings.TrimSpace(string(output))
+	if u, err := url.Parse(remote); err == nil && u.User != nil && (u.Scheme == "http" || u.Scheme == "https") {
+		u.User = nil
+		remote = u.String()
+	}
+
+	return remote, nil
+}
+
+// CollectGitMetadata sets the default branch, contributor count, last commit
+// time and CI/CD detection of a local checkout. Contributors are the distinct
+// author emails in the local history, so shallow clones undercount them.
+func CollectGitMetadata(ctx context.Context, info *RepositoryInfo) error {
+	info.HasCICD = HasCIConfig(info.LocalPath)
+
+	if branch, err := defaultBranch(ctx, info.LocalPath); err == nil {
+		info.DefaultBranch = branch
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", info.LocalPath, "log", "--format=%aE%x00%cI")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read commit log: %w", err)
+	}
+
+	authors := make(map[string]bool)
+	for i, line := range bytes.Split(bytes.TrimSpace(output), []byte("\n")) {
+		email, date, ok := bytes.Cut(line, []byte{0})
+		if !ok {
+			continue
+		}
+		authors[strings.ToLower(string(email))] = true
+
+		// Newest first
+		if i == 0 {
+			if t, err := time.Parse(time.RFC3339, string(date)); err == nil {
+				info.LastCommit = t
+			}
+		}
+	}
+	info.Contributors = len(authors)
+
+	return nil
+}
+
+// HasCIConfig reports whether a directory contains CI/CD pipeline configuration
+func HasCIConfig(root string) bool {
+	for _, path := range ciConfigPaths {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(path))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultBranch returns the branch origin/HEAD points at, or the checked-out
+// branch when the remote HEAD is unknown
+func defaultBranch(ctx context.Context, localPath string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", localPath, "symbolic-ref", "--short", "refs/remotes/origin/HEAD").Output()
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(output)), "origin/"), nil
+	}
+
+	output, err = exec.CommandContext(ctx, "git", "-C", localPath, "symbolic-ref", "--short", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read default branch: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// HeadCommit returns the commit SHA checked out in a local repository
+func HeadCommit(ctx context.Context, localPath string) (string, error) {
+	return revParse(ctx, localPath, "HEAD^{commit}")
+}
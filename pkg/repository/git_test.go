@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectGitMetadata(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(author string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com",
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com",
+			"GIT_COMMITTER_DATE=2024-06-01T10:00:00Z")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("jane", "init", "-q", "-b", "trunk")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+	git("jane", "add", ".")
+	git("jane", "commit", "-q", "-m", "Initial commit")
+
+	info := &RepositoryInfo{LocalPath: root}
+	require.NoError(t, CollectGitMetadata(context.Background(), info))
+	assert.Equal(t, "trunk", info.DefaultBranch)
+	assert.Equal(t, 1, info.Contributors)
+	assert.Equal(t, "2024-06-01T10:00:00Z", info.LastCommit.UTC().Format("2006-01-02T15:04:05Z"))
+	assert.False(t, info.HasCICD)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github", "workflows"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "workflows", "ci.yml"), []byte("on: push\n"), 0644))
+	git("raj", "add", ".")
+	git("raj", "commit", "-q", "-m", "Add CI")
+	git("JANE", "commit", "-q", "--allow-empty", "-m", "Empty")
+
+	manager := NewGitHubManager(DefaultGitHubConfig())
+	info, err := manager.GetRepositoryInfo(root)
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Contributors, "author emails are compared case-insensitively")
+	assert.True(t, info.HasCICD)
+}
+
+func TestHasCIConfig(t *testing.T) {
+	for _, path := range []string{".gitlab-ci.yml", "azure-pipelines.yml", "Jenkinsfile", ".circleci/config.yml"} {
+		t.Run(path, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte("steps: []\n"), 0644))
+			assert.True(t, HasCIConfig(root))
+		})
+	}
+
+	assert.False(t, HasCIConfig(t.TempDir()))
+}
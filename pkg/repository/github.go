@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +21,7 @@ type GitHubManager interface {
 	CloneRepository(ctx context.Context, repoURL string) (*RepositoryInfo, error)
 	GetRepositoryInfo(localPath string) (*RepositoryInfo, error)
 	CleanupRepository(localPath string) error
+	ResolveRefs(ctx context.Context, localPath string, refs ...string) ([]string, error)
 
 	// URL parsing
 	ParseRepositoryURL(url string) (owner, repo string, err error)
@@ -37,6 +37,18 @@ type RepositoryInfo struct {
 	FileCount int       `json:"file_count"` // Number of files (excluding .git)
 	ClonedAt  time.Time `json:"cloned_at"`
 	IsShallow bool      `json:"is_shallow"` // Whether it's a shallow clone
+
+	// Hosting details, from the provider's API
+	Provider   ProviderKind `json:"provider,omitempty"`
+	Visibility string       `json:"visibility,omitempty"` // public, internal or private, when known
+	Stars      int          `json:"stars,omitempty"`
+	Forks      int          `json:"forks,omitempty"`
+
+	// Git details
+	DefaultBranch string    `json:"default_branch,omitempty"`
+	Contributors  int       `json:"contributors,omitempty"` // Distinct commit authors in the cloned history
+	LastCommit    time.Time `json:"last_commit,omitempty"`
+	HasCICD       bool      `json:"has_cicd,omitempty"` // CI/CD configuration files are present
 }
 
 // GitHubConfig configures GitHub repository operations
@@ -56,6 +68,9 @@ type GitHubConfig struct {
 
 	// Cleanup
 	AutoCleanup bool // Automatically cleanup on errors
+
+	// Other hosting services
+	Providers ProviderConfig
 }
 
 // DefaultGitHubConfig returns sensible defaults
@@ -68,19 +83,23 @@ func DefaultGitHubConfig() GitHubConfig {
 		MaxRepositorySize: 1024 * 1024 * 1024, // 1GB
 		MaxFileCount:      50000,              // 50k files
 		AutoCleanup:       true,
+		Providers:         DefaultProviderConfig(),
 	}
 }
 
-// gitHubManager implements GitHubManager
+// gitHubManager implements GitHubManager. Repositories on other hosting
+// services are cloned through their provider.
 type gitHubManager struct {
 	config     GitHubConfig
-	gitCommand func(ctx context.Context, args ...string) error
+	providers  *ProviderRegistry
+	gitCommand func(ctx context.Context, env []string, args ...string) error
 }
 
 // NewGitHubManager creates a new GitHub repository manager
 func NewGitHubManager(config GitHubConfig) GitHubManager {
 	manager := &gitHubManager{
-		config: config,
+		config:    config,
+		providers: NewProviderRegistry(config),
 	}
 
 	// Set up git command function
@@ -91,79 +110,23 @@ func NewGitHubManager(config GitHubConfig) GitHubManager {
 
 // CheckAuthentication verifies GitHub authentication
 func (g *gitHubManager) CheckAuthentication(ctx context.Context) error {
-	if g.config.UseGitHubCLI {
-		// Check GitHub CLI authentication
-		cmd := exec.CommandContext(ctx, "gh", "auth", "status")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			if strings.Contains(string(output), "not logged into") {
-				return fmt.Errorf("not authenticated with GitHub CLI: run 'gh auth login'")
-			}
-			if strings.Contains(err.Error(), "not found") {
-				return fmt.Errorf("gh CLI not found: install from https://cli.github.com/")
-			}
-			return fmt.Errorf("GitHub CLI authentication check failed: %w", err)
-		}
-		return nil
-	}
-
-	if g.config.PersonalToken != "" {
-		// TODO: Validate personal token by making an API call
-		return nil
-	}
-
-	// Check for GITHUB_TOKEN environment variable as fallback
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		return nil
-	}
-
-	// In test environment, allow operation without authentication
-	if os.Getenv("CI") != "" || os.Getenv("GITHUB_ACTIONS") != "" {
-		return nil
-	}
-
-	return fmt.Errorf("no authentication method configured")
+	return g.providers.Provider(ProviderGitHub).CheckAuthentication(ctx, nil)
 }
 
 // ParseRepositoryURL parses various GitHub URL formats
 func (g *gitHubManager) ParseRepositoryURL(url string) (owner, repo string, err error) {
-	if url == "" {
-		return "", "", fmt.Errorf("empty repository URL")
-	}
-
-	// Remove .git suffix if present
-	url = strings.TrimSuffix(url, ".git")
-
-	// GitHub CLI format: owner/repo
-	if matched, _ := regexp.MatchString(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?/[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`, url); matched {
-		parts := strings.Split(url, "/")
-		if len(parts) == 2 {
-			return parts[0], parts[1], nil
-		}
-	}
-
-	// HTTPS URL: https://github.com/owner/repo
-	httpsRegex := regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)(?:/.*)?$`)
-	if matches := httpsRegex.FindStringSubmatch(url); len(matches) == 3 {
-		return matches[1], matches[2], nil
-	}
-
-	// SSH URL: git@github.com:owner/repo.git
-	sshRegex := regexp.MustCompile(`^git@github\.com:([^/]+)/([^/]+)(?:\.git)?$`)
-	if matches := sshRegex.FindStringSubmatch(url); len(matches) == 3 {
-		return matches[1], matches[2], nil
-	}
-
-	return "", "", fmt.Errorf("invalid GitHub repository URL: %s", url)
+	return parseGitHubURL(url)
 }
 
-// CloneRepository clones a GitHub repository to a temporary directory
+// CloneRepository clones a repository to a temporary directory. HTTPS URLs
+// on other hosting services are cloned with the provider's token.
 func (g *gitHubManager) CloneRepository(ctx context.Context, repoURL string) (*RepositoryInfo, error) {
 	// Parse repository URL
-	owner, repo, err := g.ParseRepositoryURL(repoURL)
+	provider, ref, err := g.providers.Resolve(repoURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse repository URL: %w", err)
 	}
+	repo := ref.Name
 
 	// Create temporary directory
 	tempDir := filepath.Join(g.config.TempDir, fmt.Sprintf("pi-scanner-%d", time.Now().UnixNano()))
@@ -176,19 +139,32 @@ func (g *gitHubManager) CloneRepository(ctx context.Context, repoURL string) (*R
 	}
 
 	// Prepare clone arguments
-	var cloneArgs []string
+	var cloneArgs, cloneEnv []string
+	cloneURL := repoURL
+
+	switch {
+	case provider.Kind() == ProviderGitHub:
+		// Use GitHub CLI format or full URL
+		if g.config.UseGitHubCLI {
+			cloneURL = provider.CloneURL(ref)
+		}
+	case strings.HasPrefix(repoURL, "https://") || strings.HasPrefix(repoURL, "http://"):
+		// Web URLs are normalised to clone URLs; the token is passed as a
+		// header so it is not stored in the clone's remote, and through the
+		// environment so it does not show up in the process list
+		cloneURL = provider.CloneURL(ref)
+		if header := provider.GitAuthHeader(); header != "" {
+			cloneEnv = gitAuthEnv(header)
+		}
+	}
+
 	cloneArgs = append(cloneArgs, "clone")
 
 	if g.config.ShallowClone {
 		cloneArgs = append(cloneArgs, "--depth", "1")
 	}
 
-	// Use GitHub CLI format or full URL
-	if g.config.UseGitHubCLI {
-		cloneArgs = append(cloneArgs, fmt.Sprintf("https://github.com/%s/%s.git", owner, repo))
-	} else {
-		cloneArgs = append(cloneArgs, repoURL)
-	}
+	cloneArgs = append(cloneArgs, cloneURL)
 
 	cloneArgs = append(cloneArgs, cloneDir)
 
@@ -201,7 +177,7 @@ func (g *gitHubManager) CloneRepository(ctx context.Context, repoURL string) (*R
 	}
 
 	// Execute clone
-	err = g.gitCommand(cloneCtx, cloneArgs...)
+	err = g.gitCommand(cloneCtx, cloneEnv, cloneArgs...)
 	if err != nil {
 		// Cleanup on failure if auto-cleanup is enabled
 		if g.config.AutoCleanup {
@@ -222,7 +198,9 @@ func (g *gitHubManager) CloneRepository(ctx context.Context, repoURL string) (*R
 
 	// Update repository info with clone details
 	repoInfo.URL = repoURL
-	repoInfo.Owner = owner
+	repoInfo.Owner = ref.Owner
+	repoInfo.Name = ref.Name
+	repoInfo.Provider = ref.Provider
 	repoInfo.ClonedAt = time.Now()
 	repoInfo.IsShallow = g.config.ShallowClone
 
@@ -287,6 +265,11 @@ func (g *gitHubManager) GetRepositoryInfo(localPath string) (*RepositoryInfo, er
 	info.Size = totalSize
 	info.FileCount = fileCount
 
+	// Git details are best effort: an empty repository has no log
+	if _, err := os.Stat(filepath.Join(localPath, ".git")); err == nil {
+		_ = CollectGitMetadata(context.Background(), info)
+	}
+
 	return info, nil
 }
 
@@ -329,9 +312,50 @@ func (g *gitHubManager) CleanupRepository(localPath string) error {
 	return nil
 }
 
+// ResolveRefs resolves refs to commit SHAs in a local clone. Refs the clone
+// does not have, such as the base branch of a shallow single-branch clone or
+// a pull request head, are fetched from origin first.
+func (g *gitHubManager) ResolveRefs(ctx context.Context, localPath string, refs ...string) ([]string, error) {
+	shas := make([]string, len(refs))
+	for i, ref := range refs {
+		sha, err := revParse(ctx, localPath, ref+"^{commit}")
+		if err != nil {
+			if err := g.gitCommand(ctx, nil, "-C", localPath, "fetch", "--no-tags", "origin", ref); err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", ref, err)
+			}
+			if sha, err = revParse(ctx, localPath, "FETCH_HEAD^{commit}"); err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+			}
+		}
+		shas[i] = sha
+	}
+	return shas, nil
+}
+
+// gitAuthEnv returns the environment that sets an HTTP header for git
+func gitAuthEnv(header string) []string {
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=" + header,
+	}
+}
+
+// revParse resolves a revision to a commit SHA
+func revParse(ctx context.Context, localPath, revision string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", localPath, "rev-parse", "--verify", "--quiet", revision).Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s", revision)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // executeGitCommand executes a git command with the given arguments
-func (g *gitHubManager) executeGitCommand(ctx context.Context, args ...string) error {
+func (g *gitHubManager) executeGitCommand(ctx context.Context, env []string, args ...string) error {
 	cmd := exec.CommandContext(ctx, "git", args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	// Set working directory if needed
 	// cmd.Dir = workingDir
@@ -348,6 +372,7 @@ func (g *gitHubManager) executeGitCommand(ctx context.Context, args ...string) e
 // RepositoryManager provides high-level repository management
 type RepositoryManager struct {
 	github      GitHubManager
+	providers   *ProviderRegistry
 	activeRepos map[string]*RepositoryInfo // Track active repositories
 	mu          sync.RWMutex               // Protect activeRepos map
 }
@@ -356,6 +381,7 @@ type RepositoryManager struct {
 func NewRepositoryManager(githubConfig GitHubConfig) *RepositoryManager {
 	return &RepositoryManager{
 		github:      NewGitHubManager(githubConfig),
+		providers:   NewProviderRegistry(githubConfig),
 		activeRepos: make(map[string]*RepositoryInfo),
 	}
 }
@@ -375,6 +401,23 @@ func (rm *RepositoryManager) CloneAndTrack(ctx context.Context, repoURL string)
 	return repoInfo, nil
 }
 
+// Cleanup removes a single tracked repository and stops tracking it
+func (rm *RepositoryManager) Cleanup(localPath string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if _, ok := rm.activeRepos[localPath]; !ok {
+		return nil
+	}
+
+	delete(rm.activeRepos, localPath)
+	if err := rm.github.CleanupRepository(localPath); err != nil {
+		return fmt.Errorf("failed to cleanup %s: %w", localPath, err)
+	}
+
+	return nil
+}
+
 // CleanupAll cleans up all tracked repositories
 func (rm *RepositoryManager) CleanupAll() error {
 	rm.mu.Lock()
@@ -413,3 +456,56 @@ func (rm *RepositoryManager) GetActiveRepositories() map[string]*RepositoryInfo
 func (rm *RepositoryManager) CheckAuthentication(ctx context.Context) error {
 	return rm.github.CheckAuthentication(ctx)
 }
+
+// CheckRepositoryAuthentication checks authentication with every hosting
+// service the repositories are on. Each host is checked once; URLs that cannot
+// be parsed are left for the clone to report.
+func (rm *RepositoryManager) CheckRepositoryAuthentication(ctx context.Context, repoURLs ...string) error {
+	checked := make(map[string]bool)
+	for _, repoURL := range repoURLs {
+		provider, ref, err := rm.providers.Resolve(repoURL)
+		if err != nil {
+			continue
+		}
+
+		key := string(ref.Provider) + " " + ref.BaseURL
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		if provider.Kind() == ProviderGitHub {
+			err = rm.github.CheckAuthentication(ctx)
+		} else {
+			err = provider.CheckAuthentication(ctx, ref)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LookupMetadata sets the visibility, stars and forks of a repository from its
+// hosting service's API. The API's default branch is preferred over the one
+// read from git.
+func (rm *RepositoryManager) LookupMetadata(ctx context.Context, repoInfo *RepositoryInfo) error {
+	provider, ref, err := rm.providers.Resolve(repoInfo.URL)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := provider.Metadata(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to look up repository metadata: %w", err)
+	}
+
+	repoInfo.Provider = ref.Provider
+	repoInfo.Visibility = metadata.Visibility
+	repoInfo.Stars = metadata.Stars
+	repoInfo.Forks = metadata.Forks
+	if metadata.DefaultBranch != "" {
+		repoInfo.DefaultBranch = metadata.DefaultBranch
+	}
+	return nil
+}
@@ -122,7 +122,7 @@ func TestGitHubManager_CloneRepository_MockSuccess(t *testing.T) {
 	}()
 
 	// Mock successful git clone
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		// Simulate git clone by creating a basic repo structure
 		if len(args) > 0 && args[0] == "clone" {
 			// Extract target directory from args
@@ -189,7 +189,7 @@ func TestGitHubManager_CloneRepository_Error(t *testing.T) {
 		manager.(*gitHubManager).gitCommand = originalGitCmd
 	}()
 
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		return assert.AnError // Simulate git command failure
 	}
 
@@ -211,7 +211,7 @@ func TestGitHubManager_CloneRepository_Cancellation(t *testing.T) {
 		manager.(*gitHubManager).gitCommand = originalGitCmd
 	}()
 
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		// Simulate long-running clone operation
 		select {
 		case <-time.After(2 * time.Second):
@@ -321,7 +321,7 @@ func TestGitHubManager_ShallowClone(t *testing.T) {
 		manager.(*gitHubManager).gitCommand = originalGitCmd
 	}()
 
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		gitCommands = append(gitCommands, args)
 
 		// Simulate successful clone
@@ -364,7 +364,7 @@ func TestGitHubManager_MultipleRepositories(t *testing.T) {
 	}()
 
 	cloneCount := 0
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			cloneCount++
 			targetDir := args[len(args)-1]
@@ -430,7 +430,7 @@ func TestGitHubManager_LargeRepository_SizeLimit(t *testing.T) {
 		manager.(*gitHubManager).gitCommand = originalGitCmd
 	}()
 
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 			err := os.MkdirAll(filepath.Join(targetDir, ".git"), 0755)
@@ -492,7 +492,7 @@ func TestGitHubManager_ConcurrentOperations(t *testing.T) {
 	}()
 
 	var cloneCount int64
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			// Simulate concurrent clone operations
 			time.Sleep(10 * time.Millisecond)
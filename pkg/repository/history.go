@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryOptions selects which commits a history walk visits
+type HistoryOptions struct {
+	Range string // Revision range such as "v1.0..HEAD"; empty walks every branch
+	Since string // Only visit commits newer than this date (any format git log --since accepts)
+}
+
+// Commit is a single commit together with the lines it added
+type Commit struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	Date        time.Time
+	Message     string
+	Files       []FileChange
+}
+
+// FileChange lists the lines a commit added to one file
+type FileChange struct {
+	Path  string // Path relative to the repository root
+	Added []AddedLine
+}
+
+// AddedLine is a line added by a commit, numbered as in the new version of the file
+type AddedLine struct {
+	Number  int
+	Content string
+}
+
+// commitMarker prefixes the header line git log prints for each commit
+const commitMarker = "\x1ecommit\x00"
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// WalkHistory streams the commits of a local repository, newest first, calling
+// fn with the lines each commit added. Deleted files, binary files and merge
+// commits contribute no added lines.
+func WalkHistory(ctx context.Context, localPath string, opts HistoryOptions, fn func(*Commit) error) error {
+	args := []string{
+		"-C", localPath, "-c", "core.quotepath=off",
+		"log", "--no-color", "-p", "--unified=0", "--diff-filter=d",
+		"--format=" + strings.ReplaceAll(commitMarker, "\x00", "%x00") + "%H%x00%an%x00%ae%x00%aI%x00%s",
+	}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Range != "" {
+		args = append(args, opts.Range)
+	} else {
+		args = append(args, "--all")
+	}
+	args = append(args, "--")
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to read git history: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	parseErr := parseHistory(stdout, fn)
+	if parseErr != nil {
+		// Stop git early; its exit status is no longer interesting
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+		cmd.Wait()
+		return parseErr
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git log failed: %w (output: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// parseHistory parses `git log -p --unified=0` output produced by WalkHistory
+func parseHistory(r io.Reader, fn func(*Commit) error) error {
+	reader := bufio.NewReader(r)
+
+	var (
+		commit *Commit
+		diff   diffParser
+	)
+
+	flushCommit := func() error {
+		files := diff.finish()
+		if commit == nil {
+			return nil
+		}
+		c := commit
+		c.Files = files
+		commit = nil
+		return fn(c)
+	}
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("failed to read git history: %w", readErr)
+		}
+		if line == "" && readErr == io.EOF {
+			break
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		if strings.HasPrefix(line, commitMarker) {
+			if err := flushCommit(); err != nil {
+				return err
+			}
+			commit = parseCommitHeader(strings.TrimPrefix(line, commitMarker))
+		} else {
+			diff.parseLine(line)
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return flushCommit()
+}
+
+// diffParser collects the added lines of each file in `git diff --unified=0`
+// style output
+type diffParser struct {
+	files   []FileChange
+	file    *FileChange
+	inHunk  bool
+	newLine int
+}
+
+// parseLine processes one line of diff output
+func (p *diffParser) parseLine(line string) {
+	switch {
+	case strings.HasPrefix(line, "diff --git "):
+		p.flushFile()
+		p.inHunk = false
+
+	case !p.inHunk && strings.HasPrefix(line, "+++ "):
+		path := strings.TrimPrefix(line, "+++ ")
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+		if path == "/dev/null" {
+			p.file = nil
+			return
+		}
+		p.file = &FileChange{Path: strings.TrimPrefix(path, "b/")}
+
+	case strings.HasPrefix(line, "@@ "):
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			p.newLine, _ = strconv.Atoi(m[1])
+			p.inHunk = true
+		}
+
+	case p.inHunk && p.file != nil && strings.HasPrefix(line, "+"):
+		p.file.Added = append(p.file.Added, AddedLine{
+			Number:  p.newLine,
+			Content: strings.TrimSuffix(line[1:], "\r"),
+		})
+		p.newLine++
+
+	case p.inHunk && strings.HasPrefix(line, " "):
+		p.newLine++
+	}
+}
+
+// flushFile records the current file if it has added lines
+func (p *diffParser) flushFile() {
+	if p.file != nil && len(p.file.Added) > 0 {
+		p.files = append(p.files, *p.file)
+	}
+	p.file = nil
+}
+
+// finish returns the files parsed so far and resets the parser
+func (p *diffParser) finish() []FileChange {
+	p.flushFile()
+	files := p.files
+	*p = diffParser{}
+	return files
+}
+
+// parseCommitHeader parses the NUL-separated commit header fields
+func parseCommitHeader(header string) *Commit {
+	fields := strings.SplitN(header, "\x00", 5)
+	for len(fields) < 5 {
+		fields = append(fields, "")
+	}
+
+	commit := &Commit{
+		SHA:         fields[0],
+		Author:      fields[1],
+		AuthorEmail: fields[2],
+		Message:     fields[4],
+	}
+	if date, err := time.Parse(time.RFC3339, fields[3]); err == nil {
+		commit.Date = date
+	}
+	return commit
+}
+
+// ReadFileAtRevision returns the content of a file at the given revision
+func ReadFileAtRevision(ctx context.Context, localPath, revision, path string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", localPath, "show", revision+":"+path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, revision, err)
+	}
+	return output, nil
+}
+
+// IsShallowRepository reports whether a local checkout has truncated history
+func IsShallowRepository(localPath string) bool {
+	output, err := exec.Command("git", "-C", localPath, "rev-parse", "--is-shallow-repository").Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "true"
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHistory(t *testing.T) {
+	log := strings.Join([]string{
+		commitMarker + "bbbb\x00Jane Citizen\x00jane@example.com\x002024-03-02T10:00:00+11:00\x00Add payroll data",
+		"",
+		"diff --git a/payroll.csv b/payroll.csv",
+		"index 1111111..2222222 100644",
+		"--- a/payroll.csv",
+		"+++ b/payroll.csv",
+		"@@ -1,0 +2,2 @@ header",
+		"+name,tfn",
+		"+Jane,123456782",
+		"@@ -10 +12 @@",
+		"-old line",
+		"+new line",
+		"diff --git a/removed.txt b/removed.txt",
+		"--- a/removed.txt",
+		"+++ /dev/null",
+		"@@ -1 +0,0 @@",
+		"-gone",
+		commitMarker + "aaaa\x00John Smith\x00john@example.com\x002024-03-01T09:00:00Z\x00Initial commit",
+		"",
+		"diff --git \"a/dir/with space.go\" \"b/dir/with space.go\"",
+		"new file mode 100644",
+		"--- /dev/null",
+		"+++ \"b/dir/with space.go\"",
+		"@@ -0,0 +1 @@",
+		"+package main",
+		"",
+	}, "\n")
+
+	var commits []*Commit
+	err := parseHistory(strings.NewReader(log), func(c *Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	first := commits[0]
+	assert.Equal(t, "bbbb", first.SHA)
+	assert.Equal(t, "Jane Citizen", first.Author)
+	assert.Equal(t, "jane@example.com", first.AuthorEmail)
+	assert.Equal(t, "Add payroll data", first.Message)
+	assert.Equal(t, 2024, first.Date.Year())
+	require.Len(t, first.Files, 1)
+	assert.Equal(t, "payroll.csv", first.Files[0].Path)
+	assert.Equal(t, []AddedLine{
+		{Number: 2, Content: "name,tfn"},
+		{Number: 3, Content: "Jane,123456782"},
+		{Number: 12, Content: "new line"},
+	}, first.Files[0].Added)
+
+	second := commits[1]
+	assert.Equal(t, "aaaa", second.SHA)
+	require.Len(t, second.Files, 1)
+	assert.Equal(t, "dir/with space.go", second.Files[0].Path)
+	assert.Equal(t, []AddedLine{{Number: 1, Content: "package main"}}, second.Files[0].Added)
+}
+
+func TestWalkHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config.go"), []byte("package config\n\nconst tfn = \"123456782\"\n"), 0644))
+	git("add", ".")
+	git("commit", "-q", "-m", "Add config")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config.go"), []byte("package config\n"), 0644))
+	git("commit", "-q", "-am", "Remove TFN")
+
+	var commits []*Commit
+	err := WalkHistory(context.Background(), root, HistoryOptions{}, func(c *Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	// Newest first; the removal adds no lines
+	assert.Equal(t, "Remove TFN", commits[0].Message)
+	assert.Empty(t, commits[0].Files)
+
+	assert.Equal(t, "Add config", commits[1].Message)
+	assert.Equal(t, "Jane Citizen", commits[1].Author)
+	require.Len(t, commits[1].Files, 1)
+	assert.Equal(t, AddedLine{Number: 3, Content: "const tfn = \"123456782\""}, commits[1].Files[0].Added[2])
+
+	// A range restricts the walk
+	commits = nil
+	err = WalkHistory(context.Background(), root, HistoryOptions{Range: "HEAD~1..HEAD"}, func(c *Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, commits, 1)
+
+	assert.False(t, IsShallowRepository(root))
+
+	content, err := ReadFileAtRevision(context.Background(), root, "HEAD~1", "config.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "123456782")
+}
@@ -35,7 +35,7 @@ func TestRepositoryManager_CleanupOnInterrupt(t *testing.T) {
 	var createdDirs []string
 	var mutex sync.Mutex
 
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 
@@ -114,7 +114,7 @@ func TestRepositoryManager_MultipleRepositories_CleanupAll(t *testing.T) {
 	}()
 
 	var createdDirs []string
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 
@@ -181,7 +181,7 @@ func TestRepositoryManager_PartialCleanupFailure(t *testing.T) {
 	}()
 
 	var createdDirs []string
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 
@@ -238,7 +238,7 @@ func TestRepositoryManager_ConcurrentOperations(t *testing.T) {
 	}()
 
 	cloneDelay := 50 * time.Millisecond
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			// Add delay to test concurrency
 			time.Sleep(cloneDelay)
@@ -352,7 +352,7 @@ func TestGitHubManager_LargeRepositoryHandling(t *testing.T) {
 		manager.(*gitHubManager).gitCommand = originalGitCmd
 	}()
 
-	manager.(*gitHubManager).gitCommand = func(ctx context.Context, args ...string) error {
+	manager.(*gitHubManager).gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 
@@ -403,7 +403,7 @@ func TestRepositoryManager_GracefulShutdown(t *testing.T) {
 		githubManager.gitCommand = originalGitCmd
 	}()
 
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 
@@ -472,7 +472,7 @@ func BenchmarkRepositoryManager_CloneAndCleanup(b *testing.B) {
 		githubManager.gitCommand = originalGitCmd
 	}()
 
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			targetDir := args[len(args)-1]
 
@@ -515,3 +515,42 @@ func BenchmarkRepositoryManager_CloneAndCleanup(b *testing.B) {
 		}
 	}
 }
+
+func TestRepositoryManager_CleanupSingleRepository(t *testing.T) {
+	config := DefaultGitHubConfig()
+	config.UseGitHubCLI = false
+	config.TempDir = t.TempDir()
+	manager := NewRepositoryManager(config)
+
+	githubManager := manager.github.(*gitHubManager)
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
+		if len(args) > 0 && args[0] == "clone" {
+			targetDir := args[len(args)-1]
+			if err := os.MkdirAll(targetDir, 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(targetDir, "README.md"), []byte("Test repo"), 0644)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	first, err := manager.CloneAndTrack(ctx, "https://github.com/test/first")
+	require.NoError(t, err)
+	second, err := manager.CloneAndTrack(ctx, "https://github.com/test/second")
+	require.NoError(t, err)
+
+	require.NoError(t, manager.Cleanup(first.LocalPath))
+	assert.NoDirExists(t, first.LocalPath)
+	assert.DirExists(t, second.LocalPath)
+
+	active := manager.GetActiveRepositories()
+	assert.Len(t, active, 1)
+	assert.Contains(t, active, second.LocalPath)
+
+	// Cleaning up an untracked path is a no-op
+	assert.NoError(t, manager.Cleanup(first.LocalPath))
+
+	require.NoError(t, manager.CleanupAll())
+	assert.NoDirExists(t, second.LocalPath)
+}
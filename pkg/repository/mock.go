@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -142,6 +143,12 @@ func (m *MockGitHubManager) CleanupRepository(localPath string) error {
 	return os.RemoveAll(localPath)
 }
 
+// ResolveRefs returns the refs unchanged as mock commit SHAs
+func (m *MockGitHubManager) ResolveRefs(ctx context.Context, localPath string, refs ...string) ([]string, error) {
+	m.CallLog = append(m.CallLog, fmt.Sprintf("ResolveRefs(%s)", strings.Join(refs, ", ")))
+	return refs, nil
+}
+
 // ParseRepositoryURL parses repository URLs (same as real implementation)
 func (m *MockGitHubManager) ParseRepositoryURL(url string) (owner, repo string, err error) {
 	m.CallLog = append(m.CallLog, fmt.Sprintf("ParseRepositoryURL(%s)", url))
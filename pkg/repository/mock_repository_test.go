@@ -441,7 +441,7 @@ func TestIntegrationFlow_FullRepositoryLifecycle(t *testing.T) {
 		githubManager.gitCommand = originalGitCmd
 	}()
 
-	githubManager.gitCommand = func(ctx context.Context, args ...string) error {
+	githubManager.gitCommand = func(ctx context.Context, env []string, args ...string) error {
 		if len(args) > 0 && args[0] == "clone" {
 			// Extract repository name from URL
 			url := args[len(args)-2]
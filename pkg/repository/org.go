@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the REST API root of github.com
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// OrgRepository is a repository listed by the GitHub REST API
+type OrgRepository struct {
+	Name       string    `json:"name"`
+	FullName   string    `json:"full_name"`
+	HTMLURL    string    `json:"html_url"`
+	Visibility string    `json:"visibility"`
+	Private    bool      `json:"private"`
+	Archived   bool      `json:"archived"`
+	Fork       bool      `json:"fork"`
+	Topics     []string  `json:"topics"`
+	PushedAt   time.Time `json:"pushed_at"`
+}
+
+// OrgFilter selects the repositories of an organisation to scan
+type OrgFilter struct {
+	Topics          []string  // Repositories must have every one of these topics
+	Visibility      string    // public, private or internal (empty = any)
+	IncludeArchived bool      // Include archived repositories
+	PushedSince     time.Time // Only repositories pushed to since this time (zero = any)
+}
+
+// Matches reports whether a repository passes the filter
+func (f OrgFilter) Matches(repo OrgRepository) bool {
+	if repo.Archived && !f.IncludeArchived {
+		return false
+	}
+
+	if f.Visibility != "" && !strings.EqualFold(repo.visibility(), f.Visibility) {
+		return false
+	}
+
+	if !f.PushedSince.IsZero() && repo.PushedAt.Before(f.PushedSince) {
+		return false
+	}
+
+	for _, topic := range f.Topics {
+		found := false
+		for _, t := range repo.Topics {
+			if strings.EqualFold(t, topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// visibility returns the repository visibility. Older GitHub Enterprise
+// versions only report the private flag.
+func (r OrgRepository) visibility() string {
+	if r.Visibility != "" {
+		return r.Visibility
+	}
+	if r.Private {
+		return VisibilityPrivate
+	}
+	return VisibilityPublic
+}
+
+// OrgClient lists the repositories of a GitHub organisation through the REST
+// API. The base URL is https://api.github.com for github.com and
+// https://host/api/v3 for GitHub Enterprise Server.
+type OrgClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewOrgClient creates an organisation client. An empty base URL means github.com.
+func NewOrgClient(baseURL, token string) *OrgClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	return &OrgClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ListRepositories returns the repositories of an organisation that pass the
+// filter, most recently pushed first. Pages are followed until the listing
+// ends or, with PushedSince set, reaches repositories pushed before it.
+func (c *OrgClient) ListRepositories(ctx context.Context, org string, filter OrgFilter) ([]OrgRepository, error) {
+	if org == "" {
+		return nil, fmt.Errorf("organisation name is required")
+	}
+
+	header := http.Header{}
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	next := fmt.Sprintf("%s/orgs/%s/repos?type=all&sort=pushed&direction=desc&per_page=100", c.baseURL, url.PathEscape(org))
+
+	var repos []OrgRepository
+	for next != "" {
+		var page []OrgRepository
+		respHeader, err := getJSON(ctx, c.client, next, header, &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", org, err)
+		}
+
+		// Only follow pages on the API host, so the token is not sent elsewhere
+		next = nextPageURL(respHeader.Get("Link"))
+		if next != "" && !strings.HasPrefix(next, c.baseURL+"/") {
+			return nil, fmt.Errorf("unexpected pagination link for %s: %s", org, next)
+		}
+		for _, repo := range page {
+			if !filter.PushedSince.IsZero() && repo.PushedAt.Before(filter.PushedSince) {
+				// Sorted by push date, so the remaining repositories are older
+				next = ""
+				break
+			}
+			if filter.Matches(repo) {
+				repos = append(repos, repo)
+			}
+		}
+	}
+
+	return repos, nil
+}
+
+// nextPageURL returns the rel="next" target of a Link header
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(target), "<>")
+	}
+	return ""
+}
+
+// GitHubToken returns the token for GitHub API requests: the configured
+// personal token, GITHUB_TOKEN, or the GitHub CLI's token when the CLI is used
+func GitHubToken(ctx context.Context, config GitHubConfig) string {
+	if config.PersonalToken != "" {
+		return config.PersonalToken
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	if config.UseGitHubCLI {
+		if output, err := exec.CommandContext(ctx, "gh", "auth", "token").Output(); err == nil {
+			return strings.TrimSpace(string(output))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgClient_ListRepositories(t *testing.T) {
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		assert.Equal(t, "Bearer ghp-token", r.Header.Get("Authorization"))
+
+		if r.URL.Path != "/api/v3/orgs/acme/repos" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			assert.Equal(t, "pushed", r.URL.Query().Get("sort"))
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/orgs/acme/repos?page=2>; rel="next", <%s/api/v3/orgs/acme/repos?page=2>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[
+				{"name": "payroll", "html_url": "https://ghe.example.com/acme/payroll", "visibility": "internal", "topics": ["hr", "pii"], "pushed_at": "2024-06-01T00:00:00Z"},
+				{"name": "legacy", "html_url": "https://ghe.example.com/acme/legacy", "visibility": "private", "archived": true, "topics": ["pii"], "pushed_at": "2024-05-01T00:00:00Z"}
+			]`))
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/orgs/acme/repos?page=1>; rel="first"`, server.URL))
+			w.Write([]byte(`[
+				{"name": "website", "html_url": "https://ghe.example.com/acme/website", "private": false, "topics": ["web"], "pushed_at": "2024-04-01T00:00:00Z"},
+				{"name": "archive-2019", "html_url": "https://ghe.example.com/acme/archive-2019", "private": true, "topics": ["pii"], "pushed_at": "2019-01-01T00:00:00Z"}
+			]`))
+		default:
+			t.Errorf("unexpected page request: %s", r.URL.RequestURI())
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewOrgClient(server.URL+"/api/v3/", "ghp-token")
+
+	tests := []struct {
+		name   string
+		filter OrgFilter
+		want   []string
+	}{
+		{name: "archived excluded by default", filter: OrgFilter{}, want: []string{"payroll", "website", "archive-2019"}},
+		{name: "include archived", filter: OrgFilter{IncludeArchived: true}, want: []string{"payroll", "legacy", "website", "archive-2019"}},
+		{name: "topic", filter: OrgFilter{Topics: []string{"PII"}}, want: []string{"payroll", "archive-2019"}},
+		{name: "all topics", filter: OrgFilter{Topics: []string{"pii", "hr"}}, want: []string{"payroll"}},
+		{name: "visibility from private flag", filter: OrgFilter{Visibility: VisibilityPublic}, want: []string{"website"}},
+		{name: "internal visibility", filter: OrgFilter{Visibility: VisibilityInternal}, want: []string{"payroll"}},
+		{name: "pushed since", filter: OrgFilter{PushedSince: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, want: []string{"payroll", "website"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, err := client.ListRepositories(context.Background(), "acme", tt.filter)
+			require.NoError(t, err)
+
+			var names []string
+			for _, repo := range repos {
+				names = append(names, repo.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+
+	// Listing stops at the first page once repositories are older than PushedSince
+	requests = nil
+	_, err := client.ListRepositories(context.Background(), "acme", OrgFilter{PushedSince: time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+}
+
+func TestOrgClient_ListRepositoriesErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/orgs/redirect/repos" {
+			w.Header().Set("Link", `<https://elsewhere.example.com/orgs/redirect/repos?page=2>; rel="next"`)
+			w.Write([]byte(`[]`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewOrgClient(server.URL, "")
+
+	_, err := client.ListRepositories(context.Background(), "missing", OrgFilter{})
+	assert.ErrorContains(t, err, "404")
+
+	_, err = client.ListRepositories(context.Background(), "redirect", OrgFilter{})
+	assert.ErrorContains(t, err, "unexpected pagination link")
+
+	_, err = client.ListRepositories(context.Background(), "", OrgFilter{})
+	assert.Error(t, err)
+}
+
+func TestNextPageURL(t *testing.T) {
+	assert.Equal(t, "https://api.github.com/organizations/1/repos?page=2",
+		nextPageURL(`<https://api.github.com/organizations/1/repos?page=2>; rel="next", <https://api.github.com/organizations/1/repos?page=9>; rel="last"`))
+	assert.Equal(t, "", nextPageURL(`<https://api.github.com/organizations/1/repos?page=1>; rel="first"`))
+	assert.Equal(t, "", nextPageURL(""))
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ProviderKind identifies a git hosting service
+type ProviderKind string
+
+const (
+	ProviderGitHub          ProviderKind = "github"
+	ProviderGitLab          ProviderKind = "gitlab"
+	ProviderBitbucketServer ProviderKind = "bitbucket-server"
+	ProviderAzureDevOps     ProviderKind = "azure-devops"
+)
+
+// ErrUnsupportedHost is returned for repository URLs on a host no provider
+// is known to serve
+var ErrUnsupportedHost = errors.New("unsupported repository host")
+
+// Repository visibility levels reported by providers
+const (
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
+	VisibilityPrivate  = "private"
+)
+
+// ParseProviderKind parses a provider name as used in configuration files
+func ParseProviderKind(name string) (ProviderKind, error) {
+	switch kind := ProviderKind(strings.ToLower(strings.TrimSpace(name))); kind {
+	case ProviderGitHub, ProviderGitLab, ProviderBitbucketServer, ProviderAzureDevOps:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown repository provider %q (expected github, gitlab, bitbucket-server or azure-devops)", name)
+	}
+}
+
+// Provider is a git hosting service repositories can be cloned from. Each
+// provider parses its own URL formats and knows how to authenticate git and
+// API requests.
+type Provider interface {
+	Kind() ProviderKind
+
+	// ParseURL parses a repository URL hosted by the provider
+	ParseURL(rawURL string) (*RepositoryRef, error)
+
+	// CloneURL returns the HTTPS clone URL of a repository
+	CloneURL(ref *RepositoryRef) string
+
+	// GitAuthHeader returns the HTTP header that authenticates git operations,
+	// or an empty string when no token is configured
+	GitAuthHeader() string
+
+	// CheckAuthentication verifies the configured credentials can read a repository
+	CheckAuthentication(ctx context.Context, ref *RepositoryRef) error
+
+	// Metadata looks up a repository's visibility and popularity
+	Metadata(ctx context.Context, ref *RepositoryRef) (*RepositoryMetadata, error)
+}
+
+// RepositoryMetadata is what a hosting service's API reports about a
+// repository. Counts a service does not track are left at zero.
+type RepositoryMetadata struct {
+	Visibility    string // public, internal or private
+	Stars         int
+	Forks         int
+	DefaultBranch string
+}
+
+// RepositoryRef identifies a repository on a hosting service
+type RepositoryRef struct {
+	Provider ProviderKind
+	BaseURL  string // Web root of the service, e.g. https://gitlab.example.com
+	Owner    string // Namespace: nested GitLab groups are joined with "/", Azure DevOps uses organization/project
+	Name     string
+}
+
+// FullName returns the owner and name of the repository
+func (r *RepositoryRef) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// ProviderConfig configures the repository providers other than GitHub
+type ProviderConfig struct {
+	// Hosts maps self-hosted servers onto providers, e.g. "gitlab.example.com": gitlab
+	Hosts map[string]ProviderKind
+
+	// Tokens
+	GitLabToken      string
+	BitbucketToken   string
+	AzureDevOpsToken string
+
+	APITimeout time.Duration // Timeout for API requests
+}
+
+// DefaultProviderConfig returns provider settings with tokens read from the
+// GITLAB_TOKEN, BITBUCKET_TOKEN and AZURE_DEVOPS_TOKEN environment variables
+func DefaultProviderConfig() ProviderConfig {
+	azureToken := os.Getenv("AZURE_DEVOPS_TOKEN")
+	if azureToken == "" {
+		azureToken = os.Getenv("AZURE_DEVOPS_EXT_PAT")
+	}
+
+	return ProviderConfig{
+		GitLabToken:      os.Getenv("GITLAB_TOKEN"),
+		BitbucketToken:   os.Getenv("BITBUCKET_TOKEN"),
+		AzureDevOpsToken: azureToken,
+		APITimeout:       30 * time.Second,
+	}
+}
+
+// ProviderRegistry selects the provider for a repository URL by its host
+type ProviderRegistry struct {
+	hosts     map[string]ProviderKind
+	providers map[ProviderKind]Provider
+}
+
+// NewProviderRegistry creates a registry of all supported providers
+func NewProviderRegistry(config GitHubConfig) *ProviderRegistry {
+	client := &http.Client{Timeout: config.Providers.APITimeout}
+
+	hosts := make(map[string]ProviderKind, len(config.Providers.Hosts))
+	for host, kind := range config.Providers.Hosts {
+		hosts[strings.ToLower(host)] = kind
+	}
+
+	return &ProviderRegistry{
+		hosts: hosts,
+		providers: map[ProviderKind]Provider{
+			ProviderGitHub:          newGitHubProvider(config, client),
+			ProviderGitLab:          &gitLabProvider{token: config.Providers.GitLabToken, client: client},
+			ProviderBitbucketServer: &bitbucketServerProvider{token: config.Providers.BitbucketToken, client: client},
+			ProviderAzureDevOps:     &azureDevOpsProvider{token: config.Providers.AzureDevOpsToken, client: client},
+		},
+	}
+}
+
+// Provider returns the provider of a kind
+func (r *ProviderRegistry) Provider(kind ProviderKind) Provider {
+	return r.providers[kind]
+}
+
+// Resolve finds the provider hosting a repository URL and parses the URL
+func (r *ProviderRegistry) Resolve(rawURL string) (Provider, *RepositoryRef, error) {
+	if rawURL == "" {
+		return nil, nil, fmt.Errorf("empty repository URL")
+	}
+
+	// GitHub CLI format: owner/repo
+	if gitHubShorthand.MatchString(strings.TrimSuffix(rawURL, ".git")) {
+		provider := r.providers[ProviderGitHub]
+		ref, err := provider.ParseURL(rawURL)
+		return provider, ref, err
+	}
+
+	remote, err := parseRemoteURL(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kind, ok := r.kindForHost(remote.host)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w %s: map it to a provider under providers.hosts", ErrUnsupportedHost, remote.host)
+	}
+
+	provider := r.providers[kind]
+	ref, err := provider.ParseURL(rawURL)
+	return provider, ref, err
+}
+
+// kindForHost returns the provider serving a host. Configured hosts take
+// precedence over the well-known cloud hosts and host name conventions.
+func (r *ProviderRegistry) kindForHost(host string) (ProviderKind, bool) {
+	host = strings.ToLower(host)
+	if kind, ok := r.hosts[host]; ok {
+		return kind, true
+	}
+	if kind, ok := r.hosts[hostname(host)]; ok {
+		return kind, true
+	}
+
+	name := hostname(host)
+	switch {
+	case name == "github.com":
+		return ProviderGitHub, true
+	case name == "dev.azure.com", name == "ssh.dev.azure.com", strings.HasSuffix(name, ".visualstudio.com"):
+		return ProviderAzureDevOps, true
+	case strings.Contains(name, "gitlab"):
+		return ProviderGitLab, true
+	case strings.Contains(name, "bitbucket") && name != "bitbucket.org":
+		return ProviderBitbucketServer, true
+	}
+	return "", false
+}
+
+// gitHubShorthand matches the owner/repo form accepted by the GitHub CLI
+var gitHubShorthand = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?/[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`)
+
+// scpLikeURL matches SSH remotes in scp syntax, e.g. git@gitlab.com:group/repo.git
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// remoteURL is a repository URL split into the parts providers parse
+type remoteURL struct {
+	scheme string // http, https or ssh
+	host   string // including the port, if any
+	path   string // without surrounding slashes or the .git suffix
+}
+
+// baseURL returns the web root of the host. SSH remotes map to HTTPS on the
+// default port, since SSH ports are rarely shared with the web server.
+func (u remoteURL) baseURL() string {
+	if u.scheme == "ssh" {
+		return "https://" + hostname(u.host)
+	}
+	return u.scheme + "://" + u.host
+}
+
+// segments returns the unescaped path segments
+func (u remoteURL) segments() []string {
+	parts := strings.Split(u.path, "/")
+	for i, part := range parts {
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			parts[i] = unescaped
+		}
+	}
+	return parts
+}
+
+// parseRemoteURL splits an HTTP(S), ssh:// or scp-like git remote
+func parseRemoteURL(rawURL string) (remoteURL, error) {
+	var remote remoteURL
+
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return remote, fmt.Errorf("invalid repository URL %s: %w", rawURL, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "ssh":
+		default:
+			return remote, fmt.Errorf("unsupported repository URL scheme: %s", u.Scheme)
+		}
+		remote.scheme = u.Scheme
+		remote.host = u.Host
+		remote.path = u.EscapedPath()
+	} else if matches := scpLikeURL.FindStringSubmatch(rawURL); matches != nil {
+		remote.scheme = "ssh"
+		remote.host = matches[1]
+		remote.path = matches[2]
+	} else {
+		return remote, fmt.Errorf("invalid repository URL: %s", rawURL)
+	}
+
+	if remote.host == "" {
+		return remote, fmt.Errorf("repository URL must include a host: %s", rawURL)
+	}
+
+	remote.path = strings.TrimSuffix(strings.Trim(remote.path, "/"), ".git")
+	return remote, nil
+}
+
+// hostname strips the port from a host
+func hostname(host string) string {
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
+
+// nonEmpty reports whether all segments are non-empty
+func nonEmpty(segments []string) bool {
+	for _, segment := range segments {
+		if segment == "" {
+			return false
+		}
+	}
+	return len(segments) > 0
+}
+
+// getJSON sends an API request and decodes the JSON response. The response
+// headers are returned for pagination.
+func getJSON(ctx context.Context, client *http.Client, apiURL string, header http.Header, v interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", apiURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("request to %s failed: %s", apiURL, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", apiURL, err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// azureDevOpsProvider implements Provider for Azure DevOps Services and
+// Azure DevOps Server. The base URL is the organization or collection and the
+// owner is organization/project.
+type azureDevOpsProvider struct {
+	token  string
+	client *http.Client
+}
+
+// Kind returns ProviderAzureDevOps
+func (p *azureDevOpsProvider) Kind() ProviderKind {
+	return ProviderAzureDevOps
+}
+
+// ParseURL parses https://dev.azure.com/org/project/_git/repo, the legacy
+// https://org.visualstudio.com/project/_git/repo, Azure DevOps Server
+// collection URLs and SSH remotes such as git@ssh.dev.azure.com:v3/org/project/repo
+func (p *azureDevOpsProvider) ParseURL(rawURL string) (*RepositoryRef, error) {
+	remote, err := parseRemoteURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := remote.segments()
+	name := hostname(remote.host)
+	ref := &RepositoryRef{Provider: ProviderAzureDevOps}
+
+	var collection []string
+	var project string
+
+	if remote.scheme == "ssh" {
+		// v3/org/project/repo
+		if len(segments) != 4 || segments[0] != "v3" || !nonEmpty(segments) {
+			return nil, fmt.Errorf("invalid Azure DevOps repository URL: %s", rawURL)
+		}
+		org := segments[1]
+		project, ref.Name = segments[2], segments[3]
+
+		if strings.HasSuffix(name, ".visualstudio.com") {
+			ref.BaseURL = "https://" + org + ".visualstudio.com"
+		} else {
+			ref.BaseURL = "https://dev.azure.com/" + url.PathEscape(org)
+		}
+		ref.Owner = org + "/" + project
+		return ref, nil
+	}
+
+	gitIndex := -1
+	for i, segment := range segments {
+		if segment == "_git" {
+			gitIndex = i
+			break
+		}
+	}
+	if gitIndex < 1 || gitIndex+1 >= len(segments) || !nonEmpty(segments[:gitIndex+2]) {
+		return nil, fmt.Errorf("invalid Azure DevOps repository URL: %s", rawURL)
+	}
+
+	collection, project, ref.Name = segments[:gitIndex-1], segments[gitIndex-1], segments[gitIndex+1]
+
+	// The organization is the subdomain on visualstudio.com and the last
+	// collection segment elsewhere, e.g. dev.azure.com/org or tfs/DefaultCollection
+	var org string
+	switch {
+	case strings.HasSuffix(name, ".visualstudio.com"):
+		org = strings.TrimSuffix(name, ".visualstudio.com")
+	case len(collection) > 0:
+		org = collection[len(collection)-1]
+	default:
+		return nil, fmt.Errorf("invalid Azure DevOps repository URL: %s", rawURL)
+	}
+
+	escaped := make([]string, len(collection))
+	for i, segment := range collection {
+		escaped[i] = url.PathEscape(segment)
+	}
+	ref.BaseURL = strings.TrimSuffix(remote.baseURL()+"/"+strings.Join(escaped, "/"), "/")
+	ref.Owner = org + "/" + project
+	return ref, nil
+}
+
+// CloneURL returns the HTTPS clone URL
+func (p *azureDevOpsProvider) CloneURL(ref *RepositoryRef) string {
+	return fmt.Sprintf("%s/%s/_git/%s", ref.BaseURL, url.PathEscape(p.project(ref)), url.PathEscape(ref.Name))
+}
+
+// GitAuthHeader authenticates git over HTTPS with a personal access token
+func (p *azureDevOpsProvider) GitAuthHeader() string {
+	if p.token == "" {
+		return ""
+	}
+	return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(":"+p.token))
+}
+
+// CheckAuthentication verifies the token can read the repository
+func (p *azureDevOpsProvider) CheckAuthentication(ctx context.Context, ref *RepositoryRef) error {
+	if p.token == "" {
+		return fmt.Errorf("no Azure DevOps token configured: set AZURE_DEVOPS_TOKEN")
+	}
+	if _, err := p.Metadata(ctx, ref); err != nil {
+		return fmt.Errorf("Azure DevOps authentication check failed: %w", err)
+	}
+	return nil
+}
+
+// Metadata looks up the repository through the Azure DevOps REST API.
+// Visibility is set per project, not per repository, and there are no stars.
+func (p *azureDevOpsProvider) Metadata(ctx context.Context, ref *RepositoryRef) (*RepositoryMetadata, error) {
+	var repo struct {
+		DefaultBranch string `json:"defaultBranch"`
+		Project       struct {
+			Visibility string `json:"visibility"`
+		} `json:"project"`
+	}
+
+	apiURL := fmt.Sprintf("%s/%s/_apis/git/repositories/%s?api-version=7.0",
+		ref.BaseURL, url.PathEscape(p.project(ref)), url.PathEscape(ref.Name))
+	if _, err := getJSON(ctx, p.client, apiURL, p.header(), &repo); err != nil {
+		return nil, err
+	}
+
+	return &RepositoryMetadata{
+		Visibility:    strings.ToLower(repo.Project.Visibility),
+		DefaultBranch: strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"),
+	}, nil
+}
+
+// project returns the project part of the owner
+func (p *azureDevOpsProvider) project(ref *RepositoryRef) string {
+	return ref.Owner[strings.LastIndex(ref.Owner, "/")+1:]
+}
+
+// header returns the API authentication header
+func (p *azureDevOpsProvider) header() http.Header {
+	header := http.Header{}
+	if auth := p.GitAuthHeader(); auth != "" {
+		header.Set("Authorization", strings.TrimPrefix(auth, "Authorization: "))
+	}
+	return header
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// bitbucketServerProvider implements Provider for Bitbucket Server and Data
+// Center. The owner is the project key, or ~user for personal repositories.
+type bitbucketServerProvider struct {
+	token  string
+	client *http.Client
+}
+
+// Kind returns ProviderBitbucketServer
+func (p *bitbucketServerProvider) Kind() ProviderKind {
+	return ProviderBitbucketServer
+}
+
+// ParseURL parses clone URLs (https://host/scm/PROJ/repo.git), web URLs
+// (https://host/projects/PROJ/repos/repo/browse) and SSH remotes
+// (ssh://git@host:7999/proj/repo.git). A context path before scm/ or
+// projects/ is kept in the base URL.
+func (p *bitbucketServerProvider) ParseURL(rawURL string) (*RepositoryRef, error) {
+	remote, err := parseRemoteURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := remote.segments()
+	ref := &RepositoryRef{Provider: ProviderBitbucketServer, BaseURL: remote.baseURL()}
+
+	if remote.scheme == "ssh" {
+		if len(segments) != 2 || !nonEmpty(segments) {
+			return nil, fmt.Errorf("invalid Bitbucket Server repository URL: %s", rawURL)
+		}
+		ref.Owner, ref.Name = segments[0], segments[1]
+		return ref, nil
+	}
+
+	for i, segment := range segments {
+		rest := segments[i+1:]
+		switch {
+		case segment == "scm" && len(rest) == 2:
+			ref.Owner, ref.Name = rest[0], rest[1]
+		case segment == "projects" && len(rest) >= 3 && rest[1] == "repos":
+			ref.Owner, ref.Name = rest[0], rest[2]
+		case segment == "users" && len(rest) >= 3 && rest[1] == "repos":
+			ref.Owner, ref.Name = "~"+rest[0], rest[2]
+		default:
+			continue
+		}
+
+		if ref.Owner == "" || ref.Name == "" || ref.Owner == "~" {
+			break
+		}
+		if i > 0 {
+			ref.BaseURL += "/" + strings.Join(segments[:i], "/")
+		}
+		return ref, nil
+	}
+
+	return nil, fmt.Errorf("invalid Bitbucket Server repository URL: %s", rawURL)
+}
+
+// CloneURL returns the HTTPS clone URL
+func (p *bitbucketServerProvider) CloneURL(ref *RepositoryRef) string {
+	return fmt.Sprintf("%s/scm/%s/%s.git", ref.BaseURL, strings.ToLower(ref.Owner), ref.Name)
+}
+
+// GitAuthHeader authenticates git over HTTPS with an HTTP access token
+func (p *bitbucketServerProvider) GitAuthHeader() string {
+	if p.token == "" {
+		return ""
+	}
+	return "Authorization: Bearer " + p.token
+}
+
+// CheckAuthentication verifies the token can read the repository
+func (p *bitbucketServerProvider) CheckAuthentication(ctx context.Context, ref *RepositoryRef) error {
+	if p.token == "" {
+		return fmt.Errorf("no Bitbucket token configured: set BITBUCKET_TOKEN")
+	}
+	if _, err := p.Metadata(ctx, ref); err != nil {
+		return fmt.Errorf("Bitbucket authentication check failed: %w", err)
+	}
+	return nil
+}
+
+// Metadata looks up the repository through the Bitbucket Server REST API.
+// Bitbucket only distinguishes public and private repositories and has no stars.
+func (p *bitbucketServerProvider) Metadata(ctx context.Context, ref *RepositoryRef) (*RepositoryMetadata, error) {
+	var repo struct {
+		Public bool `json:"public"`
+	}
+
+	apiURL := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s", ref.BaseURL, url.PathEscape(ref.Owner), url.PathEscape(ref.Name))
+	if _, err := getJSON(ctx, p.client, apiURL, p.header(), &repo); err != nil {
+		return nil, err
+	}
+
+	metadata := &RepositoryMetadata{Visibility: VisibilityPrivate}
+	if repo.Public {
+		metadata.Visibility = VisibilityPublic
+	}
+	return metadata, nil
+}
+
+// header returns the API authentication header
+func (p *bitbucketServerProvider) header() http.Header {
+	header := http.Header{}
+	if p.token != "" {
+		header.Set("Authorization", "Bearer "+p.token)
+	}
+	return header
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// gitHubProvider implements Provider for github.com and GitHub Enterprise
+// Server hosts mapped in ProviderConfig.Hosts
+type gitHubProvider struct {
+	useGitHubCLI  bool
+	personalToken string
+	client        *http.Client
+}
+
+func newGitHubProvider(config GitHubConfig, client *http.Client) *gitHubProvider {
+	return &gitHubProvider{
+		useGitHubCLI:  config.UseGitHubCLI,
+		personalToken: config.PersonalToken,
+		client:        client,
+	}
+}
+
+// Kind returns ProviderGitHub
+func (p *gitHubProvider) Kind() ProviderKind {
+	return ProviderGitHub
+}
+
+// ParseURL parses github.com URLs, owner/repo shorthand and GitHub
+// Enterprise URLs of the form https://host/owner/repo
+func (p *gitHubProvider) ParseURL(rawURL string) (*RepositoryRef, error) {
+	if owner, repo, err := parseGitHubURL(rawURL); err == nil {
+		return &RepositoryRef{Provider: ProviderGitHub, BaseURL: "https://github.com", Owner: owner, Name: repo}, nil
+	}
+
+	remote, err := parseRemoteURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := remote.segments()
+	if hostname(remote.host) == "github.com" || len(segments) < 2 || !nonEmpty(segments[:2]) {
+		return nil, fmt.Errorf("invalid GitHub repository URL: %s", rawURL)
+	}
+
+	return &RepositoryRef{
+		Provider: ProviderGitHub,
+		BaseURL:  remote.baseURL(),
+		Owner:    segments[0],
+		Name:     segments[1],
+	}, nil
+}
+
+// CloneURL returns the HTTPS clone URL
+func (p *gitHubProvider) CloneURL(ref *RepositoryRef) string {
+	return fmt.Sprintf("%s/%s/%s.git", ref.BaseURL, ref.Owner, ref.Name)
+}
+
+// GitAuthHeader returns an empty header: git authenticates to GitHub through
+// the GitHub CLI or the configured credential helper
+func (p *gitHubProvider) GitAuthHeader() string {
+	return ""
+}
+
+// CheckAuthentication verifies GitHub authentication
+func (p *gitHubProvider) CheckAuthentication(ctx context.Context, ref *RepositoryRef) error {
+	if p.useGitHubCLI {
+		// Check GitHub CLI authentication
+		cmd := exec.CommandContext(ctx, "gh", "auth", "status")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			if strings.Contains(string(output), "not logged into") {
+				return fmt.Errorf("not authenticated with GitHub CLI: run 'gh auth login'")
+			}
+			if strings.Contains(err.Error(), "not found") {
+				return fmt.Errorf("gh CLI not found: install from https://cli.github.com/")
+			}
+			return fmt.Errorf("GitHub CLI authentication check failed: %w", err)
+		}
+		return nil
+	}
+
+	if p.personalToken != "" {
+		// TODO: Validate personal token by making an API call
+		return nil
+	}
+
+	// Check for GITHUB_TOKEN environment variable as fallback
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return nil
+	}
+
+	// In test environment, allow operation without authentication
+	if os.Getenv("CI") != "" || os.Getenv("GITHUB_ACTIONS") != "" {
+		return nil
+	}
+
+	return fmt.Errorf("no authentication method configured")
+}
+
+// Metadata looks up the repository through the GitHub REST API
+func (p *gitHubProvider) Metadata(ctx context.Context, ref *RepositoryRef) (*RepositoryMetadata, error) {
+	var repo struct {
+		OrgRepository
+		Stars         int    `json:"stargazers_count"`
+		Forks         int    `json:"forks_count"`
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, err := getJSON(ctx, p.client, p.apiURL(ref)+"/repos/"+ref.Owner+"/"+ref.Name, p.header(), &repo); err != nil {
+		return nil, err
+	}
+
+	return &RepositoryMetadata{
+		Visibility:    repo.visibility(),
+		Stars:         repo.Stars,
+		Forks:         repo.Forks,
+		DefaultBranch: repo.DefaultBranch,
+	}, nil
+}
+
+// apiURL returns the REST API root for the repository's host
+func (p *gitHubProvider) apiURL(ref *RepositoryRef) string {
+	if ref.BaseURL == "https://github.com" {
+		return "https://api.github.com"
+	}
+	return ref.BaseURL + "/api/v3"
+}
+
+// header returns the API authentication header, if a token is available
+func (p *gitHubProvider) header() http.Header {
+	header := http.Header{}
+	token := p.personalToken
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
+
+var (
+	gitHubHTTPSURL = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)(?:/.*)?$`)
+	gitHubSSHURL   = regexp.MustCompile(`^git@github\.com:([^/]+)/([^/]+)(?:\.git)?$`)
+)
+
+// parseGitHubURL parses various github.com URL formats
+func parseGitHubURL(url string) (owner, repo string, err error) {
+	if url == "" {
+		return "", "", fmt.Errorf("empty repository URL")
+	}
+
+	// Remove .git suffix if present
+	url = strings.TrimSuffix(url, ".git")
+
+	// GitHub CLI format: owner/repo
+	if gitHubShorthand.MatchString(url) {
+		parts := strings.Split(url, "/")
+		if len(parts) == 2 {
+			return parts[0], parts[1], nil
+		}
+	}
+
+	// HTTPS URL: https://github.com/owner/repo
+	if matches := gitHubHTTPSURL.FindStringSubmatch(url); len(matches) == 3 {
+		return matches[1], matches[2], nil
+	}
+
+	// SSH URL: git@github.com:owner/repo.git
+	if matches := gitHubSSHURL.FindStringSubmatch(url); len(matches) == 3 {
+		return matches[1], matches[2], nil
+	}
+
+	return "", "", fmt.Errorf("invalid GitHub repository URL: %s", url)
+}
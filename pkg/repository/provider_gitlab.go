@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitLabProvider implements Provider for gitlab.com and self-hosted GitLab.
+// Projects may sit in nested groups, so the owner is the full namespace path.
+type gitLabProvider struct {
+	token  string
+	client *http.Client
+}
+
+// Kind returns ProviderGitLab
+func (p *gitLabProvider) Kind() ProviderKind {
+	return ProviderGitLab
+}
+
+// ParseURL parses project URLs such as https://gitlab.example.com/group/sub/project,
+// including web URLs with a /-/ suffix, and SSH remotes
+func (p *gitLabProvider) ParseURL(rawURL string) (*RepositoryRef, error) {
+	remote, err := parseRemoteURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Web URLs continue after the project path, e.g. /-/tree/main
+	if i := strings.Index(remote.path+"/", "/-/"); i != -1 {
+		remote.path = strings.TrimSuffix(remote.path[:i], ".git")
+	}
+
+	segments := remote.segments()
+	if len(segments) < 2 || !nonEmpty(segments) {
+		return nil, fmt.Errorf("invalid GitLab project URL: %s", rawURL)
+	}
+
+	return &RepositoryRef{
+		Provider: ProviderGitLab,
+		BaseURL:  remote.baseURL(),
+		Owner:    strings.Join(segments[:len(segments)-1], "/"),
+		Name:     segments[len(segments)-1],
+	}, nil
+}
+
+// CloneURL returns the HTTPS clone URL
+func (p *gitLabProvider) CloneURL(ref *RepositoryRef) string {
+	return fmt.Sprintf("%s/%s/%s.git", ref.BaseURL, ref.Owner, ref.Name)
+}
+
+// GitAuthHeader authenticates git over HTTPS with the access token as password
+func (p *gitLabProvider) GitAuthHeader() string {
+	if p.token == "" {
+		return ""
+	}
+	return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("oauth2:"+p.token))
+}
+
+// CheckAuthentication verifies the token can read the project
+func (p *gitLabProvider) CheckAuthentication(ctx context.Context, ref *RepositoryRef) error {
+	if p.token == "" {
+		return fmt.Errorf("no GitLab token configured: set GITLAB_TOKEN")
+	}
+	if _, err := p.Metadata(ctx, ref); err != nil {
+		return fmt.Errorf("GitLab authentication check failed: %w", err)
+	}
+	return nil
+}
+
+// Metadata looks up the project through the GitLab REST API
+func (p *gitLabProvider) Metadata(ctx context.Context, ref *RepositoryRef) (*RepositoryMetadata, error) {
+	var project struct {
+		Visibility    string `json:"visibility"`
+		Stars         int    `json:"star_count"`
+		Forks         int    `json:"forks_count"`
+		DefaultBranch string `json:"default_branch"`
+	}
+
+	apiURL := ref.BaseURL + "/api/v4/projects/" + url.PathEscape(ref.FullName())
+	if _, err := getJSON(ctx, p.client, apiURL, p.header(), &project); err != nil {
+		return nil, err
+	}
+
+	return &RepositoryMetadata{
+		Visibility:    project.Visibility,
+		Stars:         project.Stars,
+		Forks:         project.Forks,
+		DefaultBranch: project.DefaultBranch,
+	}, nil
+}
+
+// header returns the API authentication header
+func (p *gitLabProvider) header() http.Header {
+	header := http.Header{}
+	if p.token != "" {
+		header.Set("PRIVATE-TOKEN", p.token)
+	}
+	return header
+}
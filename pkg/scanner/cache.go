@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/cache"
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// openCache opens the incremental scan cache for the detectors' ruleset and
+// reports when the repository was last scanned. Scans continue without the
+// cache if it cannot be opened.
+func (s *Scanner) openCache(ctx context.Context, repoInfo *repository.RepositoryInfo, detectors []detection.Detector) *cache.Cache {
+	if s.config.CacheDir == "" {
+		return nil
+	}
+
+	fileCache, err := cache.Open(s.config.CacheDir, s.rulesetFingerprint(detectors))
+	if err != nil {
+		s.logf("⚠️  Incremental cache unavailable: %v\n", err)
+		return nil
+	}
+
+	if last, ok := fileCache.LastScan(cacheKey(repoInfo)); ok {
+		s.logf("♻️  Last scanned at %s on %s\n", shortSHA(last.Commit), last.ScannedAt.Format("2006-01-02"))
+		if head, err := repository.HeadCommit(ctx, repoInfo.LocalPath); err == nil && head == last.Commit {
+			s.logf("♻️  No new commits since the last scan\n")
+		}
+	}
+
+	return fileCache
+}
+
+// recordScan remembers the commit a repository was scanned at and counts the
+// files whose findings came from the cache
+func (s *Scanner) recordScan(ctx context.Context, fileCache *cache.Cache, repoInfo *repository.RepositoryInfo, result *ScanResult) {
+	hits, misses := fileCache.Stats()
+	result.Stats.CachedFiles = hits
+	s.logf("♻️  Reused findings for %d files, scanned %d\n", hits, misses)
+
+	head, err := repository.HeadCommit(ctx, repoInfo.LocalPath)
+	if err != nil {
+		return
+	}
+	if err := fileCache.RecordScan(cacheKey(repoInfo), head); err != nil {
+		s.logf("⚠️  %v\n", err)
+	}
+}
+
+// rulesetFingerprint describes everything besides the scanner build that
+// decides what the detectors find: the detectors, their settings, the
+// decoding limits and the Gitleaks rules
+func (s *Scanner) rulesetFingerprint(detectors []detection.Detector) string {
+	var parts []string
+	for _, d := range detectors {
+		parts = append(parts, d.Name())
+	}
+
+	if s.config.Detection != nil {
+		if data, err := json.Marshal(s.config.Detection); err == nil {
+			parts = append(parts, string(data))
+		}
+	}
+
+	// Findings in decoded text are cached with the file's own
+	if data, err := json.Marshal(s.config.Decoding); err == nil {
+		parts = append(parts, string(data))
+	}
+
+	if s.config.GitleaksConfigPath != "" {
+		if data, err := os.ReadFile(s.config.GitleaksConfigPath); err == nil {
+			parts = append(parts, string(data))
+		}
+	}
+
+	return strings.Join(parts, "\x00")
+}
+
+// cacheKey identifies a repository across scans: its remote URL or, for a
+// directory without one, its path
+func cacheKey(repoInfo *repository.RepositoryInfo) string {
+	if repoInfo.URL != "" {
+		return repoInfo.URL
+	}
+	return repoInfo.LocalPath
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
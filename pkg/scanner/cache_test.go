@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanPath_Incremental(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("init", "-q")
+	writeFile(t, root, "payroll.go", "package payroll\n\nconst employeeTFN = \"123456782\"\n")
+	writeFile(t, root, "contacts.go", "package payroll\n\nconst contact = \"jane.citizen@example.com\"\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add payroll")
+
+	var log bytes.Buffer
+	config := testConfig()
+	config.CacheDir = t.TempDir()
+	config.Verbose = true
+	config.Output = &log
+
+	first, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, first.Error)
+	require.NotEmpty(t, first.Findings)
+	assert.Zero(t, first.Stats.CachedFiles)
+
+	// Nothing changed, so every file comes from the cache with the same findings
+	log.Reset()
+	second, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Stats.CachedFiles)
+	// Findings arrive in completion order, so compare them as sets
+	locations := func(result *ScanResult) []string {
+		var found []string
+		for _, f := range result.Findings {
+			found = append(found, fmt.Sprintf("%s:%d:%s", f.File, f.Line, f.Match))
+		}
+		return found
+	}
+	assert.ElementsMatch(t, locations(first), locations(second))
+	assert.Contains(t, log.String(), "No new commits since the last scan")
+
+	// Only the changed file is scanned again
+	writeFile(t, root, "payroll.go", "package payroll\n\nconst employeeTFN = \"876543210\"\n")
+	git("commit", "-q", "-am", "Change employee")
+
+	log.Reset()
+	third, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, third.Stats.CachedFiles)
+	assert.Contains(t, log.String(), "Last scanned at")
+	assert.NotContains(t, log.String(), "No new commits")
+
+	var matches []string
+	for _, f := range third.Findings {
+		matches = append(matches, f.Match)
+	}
+	assert.Contains(t, matches, "876543210")
+	assert.NotContains(t, matches, "123456782")
+
+	// The last scanned commit is remembered per repository
+	fileCache, err := cache.Open(config.CacheDir, New(config).rulesetFingerprint(New(config).setupDetectors()))
+	require.NoError(t, err)
+	record, ok := fileCache.LastScan(root)
+	require.True(t, ok)
+	assert.Len(t, record.Commit, 40)
+}
+
+func TestScanPath_IncrementalDecodingLimits(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "payroll.go", "package payroll\n\nconst employeeTFN = \"123456782\"\n")
+
+	config := testConfig()
+	config.CacheDir = t.TempDir()
+
+	first, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, first.Error)
+
+	second, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.Stats.CachedFiles)
+
+	// Findings cached under other decoding limits are not reused
+	config.Decoding.MaxDepth++
+	third, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	assert.Zero(t, third.Stats.CachedFiles)
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/config"
+	"github.com/MacAttak/pi-scanner/pkg/decode"
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/discovery"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// FromConfig builds a scanner configuration from the YAML scanner settings
+func FromConfig(cfg *config.Config) Config {
+	scannerConfig := DefaultConfig()
+	if cfg == nil {
+		return scannerConfig
+	}
+
+	settings := cfg.Scanner
+
+	scannerConfig.NumWorkers = settings.Workers
+	scannerConfig.Timeout = settings.Timeout
+	scannerConfig.MemoryBudget = settings.MemoryBudget
+	scannerConfig.ChunkSize = settings.MaxFileSize
+	scannerConfig.ChunkOverlap = settings.ChunkOverlap
+	if settings.GitleaksConfig != "" {
+		scannerConfig.GitleaksConfigPath = settings.GitleaksConfig
+	}
+
+	scannerConfig.Discovery.IncludePatterns = fileTypePatterns(settings.FileTypes)
+	scannerConfig.Discovery.ExcludePatterns = excludePathPatterns(settings.ExcludePaths)
+	if settings.MaxScannedFileSize > 0 {
+		scannerConfig.Discovery.MaxFileSize = settings.MaxScannedFileSize
+	}
+
+	scannerConfig.Discovery.ExpandArchives = settings.Archives.Enabled
+	scannerConfig.Discovery.Archives = discovery.ArchiveLimits{
+		MaxDepth:     settings.Archives.MaxDepth,
+		MaxEntrySize: settings.Archives.MaxEntrySize,
+		MaxTotalSize: settings.Archives.MaxTotalSize,
+		MaxEntries:   settings.Archives.MaxEntries,
+	}
+	scannerConfig.Discovery.ExtractDocuments = settings.Documents.Enabled
+
+	scannerConfig.Decoding = decode.Limits{}
+	if settings.Decoding.Enabled {
+		scannerConfig.Decoding = decode.Limits{
+			MaxDepth:     settings.Decoding.MaxDepth,
+			MaxSize:      settings.Decoding.MaxSize,
+			MaxTotalSize: settings.Decoding.MaxTotalSize,
+		}
+	}
+
+	detectionConfig := detection.DefaultConfig()
+	detectionConfig.MaxFileSize = settings.MaxFileSize
+	detectionConfig.TypeSettings = typeSettings(settings.Validators)
+	detectionConfig.RiskThresholds = detection.RiskThresholds{
+		Critical: cfg.Risk.Thresholds.Critical,
+		High:     cfg.Risk.Thresholds.High,
+		Medium:   cfg.Risk.Thresholds.Medium,
+	}
+	scannerConfig.Detection = detectionConfig
+
+	if len(cfg.Providers.Hosts) > 0 {
+		scannerConfig.ProviderHosts = make(map[string]repository.ProviderKind, len(cfg.Providers.Hosts))
+		for host, name := range cfg.Providers.Hosts {
+			if kind, err := repository.ParseProviderKind(name); err == nil {
+				scannerConfig.ProviderHosts[host] = kind
+			}
+		}
+	}
+
+	return scannerConfig
+}
+
+// fileTypePatterns converts file extensions such as ".go" into discovery globs.
+// Entries without a leading dot are treated as file names (e.g. "Dockerfile")
+// and entries that already contain glob or path characters are used as is.
+func fileTypePatterns(fileTypes []string) []string {
+	patterns := make([]string, 0, len(fileTypes))
+	for _, fileType := range fileTypes {
+		switch {
+		case fileType == "":
+			continue
+		case strings.ContainsAny(fileType, "*/"):
+			patterns = append(patterns, fileType)
+		case strings.HasPrefix(fileType, "."):
+			patterns = append(patterns, "**/*"+fileType)
+		default:
+			patterns = append(patterns, "**/"+fileType)
+		}
+	}
+	return patterns
+}
+
+// excludePathPatterns converts exclude paths into discovery globs. Plain names
+// such as "node_modules" exclude any file or directory with that name at any
+// depth; globs such as "*.min.js" match file names at any depth.
+func excludePathPatterns(paths []string) []string {
+	patterns := make([]string, 0, len(paths)*2)
+	for _, path := range paths {
+		path = strings.TrimSuffix(filepath.ToSlash(path), "/")
+		switch {
+		case path == "":
+			continue
+		case strings.HasPrefix(path, "**/"):
+			patterns = append(patterns, path)
+		case strings.Contains(path, "/"):
+			patterns = append(patterns, path, path+"/**")
+		case strings.Contains(path, "*"):
+			patterns = append(patterns, "**/"+path)
+		default:
+			patterns = append(patterns, "**/"+path, "**/"+path+"/**")
+		}
+	}
+	return patterns
+}
+
+// typeSettings maps the YAML validator settings onto detector type settings
+func typeSettings(validators config.ValidatorConfig) map[detection.PIType]detection.TypeSettings {
+	byType := map[detection.PIType]config.ValidatorSettings{
+		detection.PITypeTFN:        validators.TFN,
+		detection.PITypeMedicare:   validators.Medicare,
+		detection.PITypeABN:        validators.ABN,
+		detection.PITypeBSB:        validators.BSB,
+		detection.PITypeCreditCard: validators.CreditCard,
+		detection.PITypeEmail:      validators.Email,
+		detection.PITypePhone:      validators.Phone,
+	}
+
+	settings := make(map[detection.PIType]detection.TypeSettings, len(byType))
+	for piType, v := range byType {
+		settings[piType] = detection.TypeSettings{
+			Enabled:       v.Enabled,
+			StrictMode:    v.StrictMode,
+			MinConfidence: float32(v.MinConfidence),
+			CustomPattern: v.CustomPattern,
+		}
+	}
+	return settings
+}
@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/allowlist"
+	"github.com/MacAttak/pi-scanner/pkg/config"
+	"github.com/MacAttak/pi-scanner/pkg/decode"
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Scanner.Workers = 12
+	cfg.Scanner.Timeout = 2 * time.Minute
+	cfg.Scanner.MaxFileSize = 1024
+	cfg.Scanner.MaxScannedFileSize = 4096
+	cfg.Scanner.ChunkOverlap = 128
+	cfg.Scanner.GitleaksConfig = "/etc/pi-scanner/gitleaks.toml"
+	cfg.Scanner.Validators.Email.Enabled = false
+	cfg.Scanner.Validators.TFN.CustomPattern = `\d{9}`
+
+	scanConfig := FromConfig(cfg)
+
+	assert.Equal(t, 12, scanConfig.NumWorkers)
+	assert.Equal(t, 2*time.Minute, scanConfig.Timeout)
+	assert.Equal(t, "/etc/pi-scanner/gitleaks.toml", scanConfig.GitleaksConfigPath)
+	assert.Equal(t, int64(4096), scanConfig.Discovery.MaxFileSize)
+	assert.Equal(t, int64(1024), scanConfig.ChunkSize)
+	assert.Equal(t, 128, scanConfig.ChunkOverlap)
+	assert.True(t, scanConfig.Discovery.ExpandArchives)
+	assert.True(t, scanConfig.Discovery.ExtractDocuments)
+	assert.Equal(t, decode.DefaultLimits(), scanConfig.Decoding)
+	assert.Equal(t, 3, scanConfig.Discovery.Archives.MaxDepth)
+	assert.Equal(t, int64(10*1024*1024), scanConfig.Discovery.Archives.MaxEntrySize)
+	assert.Contains(t, scanConfig.Discovery.IncludePatterns, "**/*.go")
+	assert.Contains(t, scanConfig.Discovery.ExcludePatterns, "**/node_modules/**")
+
+	require.NotNil(t, scanConfig.Detection)
+	assert.Equal(t, int64(1024), scanConfig.Detection.MaxFileSize)
+	assert.False(t, scanConfig.Detection.TypeSettings[detection.PITypeEmail].Enabled)
+	assert.Equal(t, `\d{9}`, scanConfig.Detection.TypeSettings[detection.PITypeTFN].CustomPattern)
+	assert.True(t, scanConfig.Detection.TypeSettings[detection.PITypeTFN].StrictMode)
+	assert.InDelta(t, 0.8, scanConfig.Detection.TypeSettings[detection.PITypeTFN].MinConfidence, 0.001)
+	assert.Equal(t, 0.8, scanConfig.Detection.RiskThresholds.Critical)
+	assert.Equal(t, 0.6, scanConfig.Detection.RiskThresholds.High)
+}
+
+func TestFileTypePatterns(t *testing.T) {
+	patterns := fileTypePatterns([]string{".go", "Dockerfile", "**/*.sql", ""})
+	assert.Equal(t, []string{"**/*.go", "**/Dockerfile", "**/*.sql"}, patterns)
+}
+
+func TestExcludePathPatterns(t *testing.T) {
+	patterns := excludePathPatterns([]string{"node_modules", "*.min.js", "docs/generated/", "**/fixtures/**"})
+	assert.Equal(t, []string{
+		"**/node_modules", "**/node_modules/**",
+		"**/*.min.js",
+		"docs/generated", "docs/generated/**",
+		"**/fixtures/**",
+	}, patterns)
+}
+
+func TestScan_HonoursConfig(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "src/payroll.go", "package payroll\n\nvar validTFN = \"123456782\"\nvar invalidTFN = \"123456789\"\nvar contact = \"jane.citizen@company.com.au\"\n")
+	writeFile(t, root, "src/notes.md", "TFN 123456782\n")
+	writeFile(t, root, "node_modules/lib/index.js", "const tfn = \"123456782\"\n")
+
+	cfg := config.DefaultConfig()
+	cfg.Scanner.Validators.Email.Enabled = false
+
+	scanConfig := FromConfig(cfg)
+	scanConfig.GitleaksConfigPath = ""
+
+	result, err := ScanPath(context.Background(), root, scanConfig)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	// .md is not in the configured file types and node_modules is excluded
+	assert.Equal(t, 1, result.FilesScanned)
+
+	var tfns []string
+	for _, f := range result.Findings {
+		assert.NotEqual(t, detection.PITypeEmail, f.Type, "email detection is disabled")
+		assert.Equal(t, filepath.Join(root, "src", "payroll.go"), f.File)
+		if f.Type == detection.PITypeTFN {
+			tfns = append(tfns, f.Match)
+			assert.Equal(t, detection.RiskLevelCritical, f.RiskLevel)
+		}
+	}
+
+	// TFN strict mode drops the value that fails checksum validation
+	assert.Equal(t, []string{"123456782"}, tfns)
+}
+
+func TestScan_Timeout(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+
+	scanConfig := testConfig()
+	scanConfig.Timeout = time.Nanosecond
+
+	result, err := ScanPath(context.Background(), root, scanConfig)
+	require.NoError(t, err)
+	assert.Contains(t, result.Error, "deadline exceeded")
+}
+
+func TestScan_Suppressions(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "src/payroll.go", `package payroll
+
+// pi-scanner:ignore TFN reason=published by the ATO
+var employeeTFN = "123456782"
+
+var companyABN = "51824753556" // pi-scanner:ignore EMAIL reason=wrong type
+
+var realTFN = "876543210"
+`)
+	writeFile(t, root, allowlist.DefaultFile, `version: "1.0"
+entries:
+  - value_hash: `+allowlist.HashValue("51824753556")+`
+    justification: Our published company ABN
+    expires: 2099-12-31
+`)
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	byMatch := make(map[string]Finding)
+	for _, f := range result.Findings {
+		byMatch[f.Match] = f
+	}
+
+	tfn, ok := byMatch["123456782"]
+	require.True(t, ok, "suppressed findings are still reported")
+	require.NotNil(t, tfn.Suppression)
+	assert.Equal(t, detection.SuppressionInline, tfn.Suppression.Source)
+	assert.Equal(t, "published by the ATO", tfn.Suppression.Justification)
+
+	abn, ok := byMatch["51824753556"]
+	require.True(t, ok)
+	require.NotNil(t, abn.Suppression, "inline marker for another type does not apply, the allowlist does")
+	assert.Equal(t, detection.SuppressionAllowlist, abn.Suppression.Source)
+
+	real, ok := byMatch["876543210"]
+	require.True(t, ok)
+	assert.Nil(t, real.Suppression)
+
+	assert.Positive(t, result.Stats.Suppressed)
+}
+
+func TestScan_InvalidAllowlist(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, allowlist.DefaultFile, "entries:\n  - type: TFN\n")
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+	assert.Contains(t, result.Error, "justification is required")
+}
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/discovery"
+	"github.com/MacAttak/pi-scanner/pkg/processing"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// ScanDiff scans the changes between two revisions, such as the base and head
+// of a pull request. Changed files are scanned in full at head so the context
+// validator sees the surrounding code, but only findings on lines the change
+// adds are reported. Refs missing from the clone are fetched from origin.
+func (s *Scanner) ScanDiff(ctx context.Context, repoInfo *repository.RepositoryInfo, spec string) *ScanResult {
+	result := NewScanResult()
+	result.Repository = repoInfo
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	root := repoInfo.LocalPath
+
+	diffRange, err := repository.ParseDiffRange(spec)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	allow, err := s.loadAllowlist(root)
+	if err != nil {
+		result.Error = fmt.Sprintf("Allowlist failed: %v", err)
+		return result
+	}
+
+	// Set up detectors
+	s.logf("🔧 Setting up detection pipeline...\n")
+
+	detectors := s.setupDetectors()
+
+	s.logf("✅ %d detectors configured\n", len(detectors))
+
+	// Read the changed lines
+	s.logf("🔀 Reading changes in %s...\n", spec)
+
+	manager := repository.NewGitHubManager(repository.DefaultGitHubConfig())
+	shas, err := manager.ResolveRefs(ctx, root, diffRange.Base, diffRange.Head)
+	if err != nil {
+		result.Error = fmt.Sprintf("Resolving diff range failed: %v", err)
+		return result
+	}
+	base, head := shas[0], shas[1]
+
+	changes, err := repository.DiffChanges(ctx, root, base, head, diffRange.MergeBase)
+	if err != nil {
+		result.Error = fmt.Sprintf("Reading diff failed: %v", err)
+		return result
+	}
+
+	fileDiscovery := discovery.NewFileDiscovery(s.config.Discovery)
+	addedLines := make(map[string]map[int]bool)
+
+	// The changes are read as they are scanned rather than all at once
+	var read ScanStats
+	findings, filesScanned, err := s.processJobs(ctx, detectors, result, func(submit func(processing.FileJob) error) error {
+		for _, change := range changes {
+			if !fileDiscovery.MatchesPath(change.Path) {
+				read.SkippedFiles++
+				continue
+			}
+
+			content, err := repository.ReadFileAtRevision(ctx, root, head, change.Path)
+			if err != nil {
+				s.logf("⚠️  Skipping %s: %v\n", change.Path, err)
+				read.SkippedFiles++
+				continue
+			}
+
+			filePath := filepath.Join(root, change.Path)
+			lines := make(map[int]bool, len(change.Added))
+			for _, line := range change.Added {
+				lines[line.Number] = true
+			}
+			addedLines[filePath] = lines
+
+			if err := submit(processing.FileJob{
+				FilePath: filePath,
+				Content:  content,
+			}); err != nil {
+				return err
+			}
+			read.ScannedFiles++
+			read.TotalSize += int64(len(content))
+		}
+		return nil
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("File processing failed: %v", err)
+		return result
+	}
+
+	result.Stats.TotalFiles = len(changes)
+	result.Stats.SkippedFiles = read.SkippedFiles
+	result.Stats.ScannedFiles = read.ScannedFiles
+	result.Stats.TotalSize = read.TotalSize
+
+	s.logf("📋 Read %d changed files (%d skipped)\n", read.ScannedFiles, read.SkippedFiles)
+
+	findings = onAddedLines(findings, addedLines)
+
+	s.finish(ctx, result, findings, filesScanned, allow)
+	return result
+}
+
+// onAddedLines keeps the findings on lines added by the change
+func onAddedLines(findings []detection.Finding, addedLines map[string]map[int]bool) []detection.Finding {
+	kept := findings[:0]
+	for _, f := range findings {
+		if addedLines[f.File][f.Line] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("init", "-q", "-b", "main")
+	writeFile(t, root, "payroll.go", "package payroll\n\nvar existing = \"876543210\"\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add payroll")
+
+	git("checkout", "-q", "-b", "feature")
+	writeFile(t, root, "payroll.go", "package payroll\n\nvar existing = \"876543210\"\n\nvar added = \"123456782\"\n")
+	git("commit", "-q", "-am", "Add employee")
+
+	// The working tree is not scanned, only the head revision
+	git("checkout", "-q", "main")
+
+	config := testConfig()
+	config.Diff = "main...feature"
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	var tfns []Finding
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			tfns = append(tfns, f)
+		}
+	}
+	require.Len(t, tfns, 1, "only the added line is reported")
+	assert.Equal(t, "123456782", tfns[0].Match)
+	assert.Equal(t, filepath.Join(root, "payroll.go"), tfns[0].File)
+	assert.Equal(t, 5, tfns[0].Line, "line numbers refer to the head revision")
+	assert.Equal(t, 1, result.FilesScanned)
+}
+
+func TestScanDiff_InvalidRange(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = root
+	require.NoError(t, cmd.Run())
+
+	config := testConfig()
+	config.Diff = "main"
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	assert.Contains(t, result.Error, "invalid diff range")
+}
@@ -0,0 +1,236 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/discovery"
+	"github.com/MacAttak/pi-scanner/pkg/processing"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// historyKey identifies the lines one commit added to one file. Staged scans
+// leave the commit empty.
+type historyKey struct {
+	commit string
+	path   string
+}
+
+// ScanHistory scans the lines added by each commit in the repository history,
+// so PI that was committed and later removed is still reported. Every finding
+// carries the commit that introduced it and whether the value is still present
+// at HEAD. A value added to the same file more than once is reported once,
+// against the earliest commit.
+func (s *Scanner) ScanHistory(ctx context.Context, repoInfo *repository.RepositoryInfo, opts repository.HistoryOptions) *ScanResult {
+	result := NewScanResult()
+	result.Repository = repoInfo
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	root := repoInfo.LocalPath
+	if repository.IsShallowRepository(root) {
+		s.logf("⚠️  Repository is a shallow clone, history will be incomplete\n")
+	}
+
+	allow, err := s.loadAllowlist(root)
+	if err != nil {
+		result.Error = fmt.Sprintf("Allowlist failed: %v", err)
+		return result
+	}
+
+	// Set up detectors
+	s.logf("🔧 Setting up detection pipeline...\n")
+
+	detectors := s.setupDetectors()
+
+	s.logf("✅ %d detectors configured\n", len(detectors))
+
+	// Walk history
+	s.logf("📜 Reading commit history...\n")
+
+	fileDiscovery := discovery.NewFileDiscovery(s.config.Discovery)
+	lineNumbers := make(map[historyKey][]int)
+	paths := make(map[string]bool)
+
+	// The walk keeps its own counts until it is done, and is scanned as it
+	// streams rather than read into memory first
+	var read ScanStats
+	findings, _, err := s.processJobs(ctx, detectors, result, func(submit func(processing.FileJob) error) error {
+		err := repository.WalkHistory(ctx, root, opts, func(commit *repository.Commit) error {
+			read.CommitsScanned++
+
+			info := &detection.CommitInfo{
+				SHA:         commit.SHA,
+				Author:      commit.Author,
+				AuthorEmail: commit.AuthorEmail,
+				Date:        commit.Date,
+				Message:     commit.Message,
+			}
+
+			for _, change := range commit.Files {
+				if !fileDiscovery.MatchesPath(change.Path) {
+					read.SkippedFiles++
+					continue
+				}
+
+				content, lines := addedContent(change.Added)
+				lineNumbers[historyKey{commit.SHA, change.Path}] = lines
+				paths[change.Path] = true
+
+				if err := submit(processing.FileJob{
+					FilePath: filepath.Join(root, change.Path),
+					Content:  content,
+					Commit:   info,
+				}); err != nil {
+					return err
+				}
+				read.ScannedFiles++
+				read.TotalSize += int64(len(content))
+			}
+			return nil
+		})
+
+		var failed submitError
+		if err != nil && !errors.As(err, &failed) {
+			return historyError{err}
+		}
+		return err
+	})
+
+	var walkErr historyError
+	if errors.As(err, &walkErr) {
+		result.Error = fmt.Sprintf("History walk failed: %v", walkErr.error)
+		return result
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("File processing failed: %v", err)
+		return result
+	}
+
+	result.Stats.CommitsScanned = read.CommitsScanned
+	result.Stats.SkippedFiles = read.SkippedFiles
+	result.Stats.ScannedFiles = read.ScannedFiles
+	result.Stats.TotalSize = read.TotalSize
+	result.Stats.TotalFiles = len(paths)
+
+	s.logf("📋 Read %d file changes from %d commits (%d skipped)\n",
+		read.ScannedFiles, read.CommitsScanned, read.SkippedFiles)
+
+	findings = mapAddedLines(findings, root, lineNumbers)
+	findings = earliestOccurrences(findings)
+	s.markPresentAtHead(ctx, root, findings)
+
+	s.finish(ctx, result, findings, len(paths), allow)
+	return result
+}
+
+// historyError marks a failure to walk the history, as opposed to a failure
+// to scan what was read
+type historyError struct{ error }
+
+func (e historyError) Unwrap() error { return e.error }
+
+// addedContent joins a commit's added lines into scannable content. Runs of
+// consecutive lines are kept together and separated from other runs by a
+// blank line so patterns cannot match across unrelated hunks. The returned
+// slice maps each content line (1-based index - 1) to its line in the file;
+// separator lines map to 0.
+func addedContent(added []repository.AddedLine) ([]byte, []int) {
+	var b strings.Builder
+	lines := make([]int, 0, len(added))
+
+	for i, line := range added {
+		if i > 0 && line.Number != added[i-1].Number+1 {
+			b.WriteString("\n")
+			lines = append(lines, 0)
+		}
+		b.WriteString(line.Content)
+		b.WriteString("\n")
+		lines = append(lines, line.Number)
+	}
+
+	return []byte(b.String()), lines
+}
+
+// mapAddedLines translates finding line numbers from the scanned added-line
+// content back to line numbers in the committed or staged file. Findings on
+// hunk separator lines are dropped.
+func mapAddedLines(findings []detection.Finding, root string, lineNumbers map[historyKey][]int) []detection.Finding {
+	mapped := findings[:0]
+	for _, f := range findings {
+		sha := ""
+		if f.Commit != nil {
+			sha = f.Commit.SHA
+		}
+
+		relPath, err := filepath.Rel(root, f.File)
+		if err != nil {
+			continue
+		}
+
+		lines := lineNumbers[historyKey{sha, filepath.ToSlash(relPath)}]
+		if f.Line < 1 || f.Line > len(lines) || lines[f.Line-1] == 0 {
+			continue
+		}
+
+		f.Line = lines[f.Line-1]
+		mapped = append(mapped, f)
+	}
+	return mapped
+}
+
+// earliestOccurrences keeps one finding per file, type and value, choosing the
+// commit that introduced it first
+func earliestOccurrences(findings []detection.Finding) []detection.Finding {
+	type occurrence struct {
+		file   string
+		piType detection.PIType
+		match  string
+	}
+
+	index := make(map[occurrence]int)
+	var unique []detection.Finding
+
+	for _, f := range findings {
+		key := occurrence{f.File, f.Type, f.Match}
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(unique)
+			unique = append(unique, f)
+			continue
+		}
+		if f.Commit.Date.Before(unique[i].Commit.Date) {
+			unique[i] = f
+		}
+	}
+
+	return unique
+}
+
+// markPresentAtHead records whether each finding's value still exists in its file at HEAD
+func (s *Scanner) markPresentAtHead(ctx context.Context, root string, findings []detection.Finding) {
+	headContent := make(map[string]string)
+
+	for i := range findings {
+		f := &findings[i]
+
+		content, ok := headContent[f.File]
+		if !ok {
+			relPath, err := filepath.Rel(root, f.File)
+			if err == nil {
+				data, err := repository.ReadFileAtRevision(ctx, root, "HEAD", filepath.ToSlash(relPath))
+				if err == nil {
+					content = string(data)
+				}
+			}
+			headContent[f.File] = content
+		}
+
+		f.Commit.PresentAtHead = content != "" && strings.Contains(content, f.Match)
+	}
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com",
+			"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("2024-01-01T00:00:00Z", "init", "-q")
+	writeFile(t, root, "payroll.go", "package payroll\n\nvar leaked = \"123456782\"\n")
+	writeFile(t, root, "customers.go", "package payroll\n\nvar kept = \"876543210\"\n")
+	git("2024-01-01T00:00:00Z", "add", ".")
+	git("2024-01-01T00:00:00Z", "commit", "-q", "-m", "Add payroll")
+
+	// The leaked TFN is deleted, the other stays at HEAD
+	writeFile(t, root, "payroll.go", "package payroll\n")
+	git("2024-02-01T00:00:00Z", "commit", "-q", "-am", "Remove leaked TFN")
+
+	result, err := ScanPath(context.Background(), root, withHistory(testConfig(), repository.HistoryOptions{}))
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+	assert.Equal(t, 2, result.Stats.CommitsScanned)
+
+	found := make(map[string]Finding)
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			found[f.Match] = f
+		}
+	}
+
+	leaked, ok := found["123456782"]
+	require.True(t, ok, "TFN removed from HEAD must still be reported")
+	require.NotNil(t, leaked.Commit)
+	assert.Equal(t, filepath.Join(root, "payroll.go"), leaked.File)
+	assert.Equal(t, 3, leaked.Line)
+	assert.Equal(t, "Jane Citizen", leaked.Commit.Author)
+	assert.Equal(t, "Add payroll", leaked.Commit.Message)
+	assert.Len(t, leaked.Commit.SHA, 40)
+	assert.False(t, leaked.Commit.PresentAtHead)
+
+	if kept, ok := found["876543210"]; ok {
+		require.NotNil(t, kept.Commit)
+		assert.True(t, kept.Commit.PresentAtHead)
+	}
+
+	// Commits before --since are not visited
+	result, err = ScanPath(context.Background(), root, withHistory(testConfig(), repository.HistoryOptions{Since: "2024-01-15"}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Stats.CommitsScanned)
+	assert.Empty(t, result.Findings)
+}
+
+func TestScanHistory_RequiresGitRepository(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+
+	_, err := ScanPath(context.Background(), root, withHistory(testConfig(), repository.HistoryOptions{}))
+	assert.ErrorContains(t, err, "requires a git repository")
+}
+
+func TestAddedContent(t *testing.T) {
+	content, lines := addedContent([]repository.AddedLine{
+		{Number: 4, Content: "a"},
+		{Number: 5, Content: "b"},
+		{Number: 9, Content: "c"},
+	})
+
+	assert.Equal(t, "a\nb\n\nc\n", string(content))
+	assert.Equal(t, []int{4, 5, 0, 9}, lines)
+}
+
+func withHistory(config Config, opts repository.HistoryOptions) Config {
+	config.History = &opts
+	return config
+}
@@ -0,0 +1,775 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/allowlist"
+	"github.com/MacAttak/pi-scanner/pkg/baseline"
+	"github.com/MacAttak/pi-scanner/pkg/cache"
+	"github.com/MacAttak/pi-scanner/pkg/decode"
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/discovery"
+	"github.com/MacAttak/pi-scanner/pkg/extract"
+	"github.com/MacAttak/pi-scanner/pkg/policy"
+	"github.com/MacAttak/pi-scanner/pkg/processing"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// ScanResult represents the results of scanning a repository
+type ScanResult struct {
+	Repository   *repository.RepositoryInfo `json:"repository"`
+	ScanStarted  time.Time                  `json:"scan_started"`
+	ScanFinished time.Time                  `json:"scan_finished"`
+	Duration     time.Duration              `json:"duration"`
+	FilesScanned int                        `json:"files_scanned"`
+	Findings     []Finding                  `json:"findings"`
+	Stats        ScanStats                  `json:"stats"`
+	Error        string                     `json:"error,omitempty"`
+
+	// Allowlist entries past their expiry date; they no longer suppress findings
+	ExpiredAllowlistEntries []allowlist.Entry `json:"expired_allowlist_entries,omitempty"`
+
+	// Rules of the configured policy that the findings broke
+	PolicyViolations []policy.Violation `json:"policy_violations,omitempty"`
+
+	// Baseline comparison only
+	Baseline       *baseline.Summary   `json:"baseline,omitempty"`
+	AbsentFindings []detection.Finding `json:"absent_findings,omitempty"` // Baseline entries no longer detected
+}
+
+// ScanStats provides statistics about the scan
+type ScanStats struct {
+	TotalFiles        int            `json:"total_files"`
+	ScannedFiles      int            `json:"scanned_files"`
+	SkippedFiles      int            `json:"skipped_files"`
+	TotalSize         int64          `json:"total_size"`
+	FindingsByType    map[string]int `json:"findings_by_type"`
+	FindingsByRisk    map[string]int `json:"findings_by_risk"`
+	ProcessingTime    time.Duration  `json:"processing_time"`
+	CommitsScanned    int            `json:"commits_scanned,omitempty"`    // History scans only
+	Suppressed        int            `json:"suppressed,omitempty"`         // Findings accepted inline or by the allowlist
+	CachedFiles       int            `json:"cached_files,omitempty"`       // Files whose findings were reused from the incremental cache
+	ChunkedFiles      int            `json:"chunked_files,omitempty"`      // Files too large to scan whole, scanned in overlapping windows
+	ArchiveEntries    int            `json:"archive_entries,omitempty"`    // Files scanned inside zip, jar and tar archives
+	DocumentFiles     int            `json:"document_files,omitempty"`     // Office documents and PDFs whose extracted text was scanned
+	SkippedBySize     []string       `json:"skipped_by_size,omitempty"`    // Files over the discovery or archive entry size limits, left unscanned
+	TruncatedArchives []string       `json:"truncated_archives,omitempty"` // Archives over the total size or entry limits, scanned only up to the limit
+}
+
+// DefaultMaxScannedFileSize is the largest file scanned by default. Files
+// over the chunk size are scanned in overlapping windows.
+const DefaultMaxScannedFileSize = 1024 * 1024 * 1024 // 1GB
+
+// MetadataLookup fills in the hosting details of a repository, such as its
+// visibility, from the hosting service's API
+type MetadataLookup func(ctx context.Context, repoInfo *repository.RepositoryInfo) error
+
+// Config configures the scanning pipeline
+type Config struct {
+	NumWorkers         int                                // Number of file processing workers
+	Timeout            time.Duration                      // Overall scan timeout (0 = no limit)
+	GitleaksConfigPath string                             // Gitleaks rules; skipped if the file does not exist
+	Discovery          discovery.Config                   // File discovery settings
+	Detection          *detection.Config                  // Pattern detector settings (nil = detector defaults)
+	History            *repository.HistoryOptions         // Scan commit history instead of the working tree (nil = working tree)
+	Staged             bool                               // Scan only lines added in the git index instead of the working tree
+	Diff               string                             // Revision range such as "main...feature"; scan only lines it adds
+	CacheDir           string                             // Incremental cache; unchanged files reuse earlier findings (empty = no cache)
+	MemoryBudget       int64                              // Maximum bytes of file content held in memory at once (0 = processor default)
+	ChunkSize          int64                              // Files larger than this are scanned in overlapping windows of this size (0 = detector MaxFileSize)
+	ChunkOverlap       int                                // Bytes each window shares with the next (0 = processing.DefaultChunkOverlap)
+	Decoding           decode.Limits                      // Scan the text decoded from base64, URL-encoded and hex runs (zero MaxDepth = off)
+	ProviderHosts      map[string]repository.ProviderKind // Self-hosted git servers and the provider serving them
+	RepositoryMetadata MetadataLookup                     // Hosting details lookup for path scans of checkouts with a remote (nil = none)
+	Baseline           *baseline.Baseline                 // Accepted findings to compare against (nil = no comparison)
+	Allowlist          *allowlist.Allowlist               // Reviewed suppressions (nil = .pi-scanner-allow.yaml at the root, if present)
+	Policy             *policy.Policy                     // Build-failing rules evaluated on the final findings (nil = none)
+	Verbose            bool                               // Print progress messages
+	Output             io.Writer                          // Destination for progress messages (default: stdout)
+}
+
+// DefaultConfig returns a default scanner configuration
+func DefaultConfig() Config {
+	config := Config{
+		NumWorkers:         4,
+		GitleaksConfigPath: filepath.Join("configs", "gitleaks.toml"),
+		Discovery:          discovery.DefaultConfig(),
+		Decoding:           decode.DefaultLimits(),
+		Output:             os.Stdout,
+	}
+	// Large files are chunked rather than skipped, up to a much higher limit
+	config.Discovery.MaxFileSize = DefaultMaxScannedFileSize
+	return config
+}
+
+// Scanner runs the detection pipeline over a directory tree
+type Scanner struct {
+	config Config
+}
+
+// New creates a new scanner
+func New(config Config) *Scanner {
+	if config.NumWorkers <= 0 {
+		config.NumWorkers = 4
+	}
+	if config.Output == nil {
+		config.Output = os.Stdout
+	}
+	return &Scanner{config: config}
+}
+
+// NewScanResult creates an empty scan result with initialised statistics
+func NewScanResult() *ScanResult {
+	return &ScanResult{
+		ScanStarted: time.Now(),
+		Stats: ScanStats{
+			FindingsByType: make(map[string]int),
+			FindingsByRisk: make(map[string]int),
+		},
+	}
+}
+
+// ScanPath scans a local directory, such as an existing checkout or CI
+// workspace, without cloning. Repository details are read from git when the
+// directory contains a .git folder. When config.History is set the commit
+// history of the checkout is scanned instead of its working tree. When
+// config.Staged or config.Diff is set only the lines added in the index or by
+// the revision range are reported.
+func ScanPath(ctx context.Context, path string, config Config) (*ScanResult, error) {
+	return New(config).ScanPath(ctx, path)
+}
+
+// ScanPath scans a local directory without cloning it
+func (s *Scanner) ScanPath(ctx context.Context, path string) (*ScanResult, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access path %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", path)
+	}
+
+	_, gitErr := os.Stat(filepath.Join(absPath, ".git"))
+
+	// Staged scans run from pre-commit hooks, so skip measuring the whole tree
+	if s.config.Staged {
+		if gitErr != nil {
+			return nil, fmt.Errorf("staged scan requires a git repository: %s", path)
+		}
+		repoInfo := &repository.RepositoryInfo{Name: filepath.Base(absPath), LocalPath: absPath}
+		if remote, err := repository.GetRemoteURL(absPath); err == nil {
+			repoInfo.URL = remote
+		}
+		return s.ScanStaged(ctx, repoInfo), nil
+	}
+
+	repoInfo, err := LocalRepositoryInfo(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.RepositoryMetadata != nil && repoInfo.URL != "" {
+		if err := s.config.RepositoryMetadata(ctx, repoInfo); err != nil {
+			s.logf("⚠️  %v\n", err)
+		}
+	}
+
+	if s.config.History != nil {
+		if gitErr != nil {
+			return nil, fmt.Errorf("history scan requires a git repository: %s", path)
+		}
+		return s.ScanHistory(ctx, repoInfo, *s.config.History), nil
+	}
+
+	if s.config.Diff != "" {
+		if gitErr != nil {
+			return nil, fmt.Errorf("diff scan requires a git repository: %s", path)
+		}
+		return s.ScanDiff(ctx, repoInfo, s.config.Diff), nil
+	}
+
+	return s.Scan(ctx, repoInfo), nil
+}
+
+// LocalRepositoryInfo describes a local directory. When the directory is a git
+// checkout its details are gathered with GetRepositoryInfo and the origin
+// remote is used as the repository URL.
+func LocalRepositoryInfo(path string) (*repository.RepositoryInfo, error) {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return &repository.RepositoryInfo{
+			Name:      filepath.Base(path),
+			LocalPath: path,
+		}, nil
+	}
+
+	manager := repository.NewGitHubManager(repository.DefaultGitHubConfig())
+	repoInfo, err := manager.GetRepositoryInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository info: %w", err)
+	}
+
+	if remote, err := repository.GetRemoteURL(path); err == nil && remote != "" {
+		repoInfo.URL = remote
+		providers := repository.NewProviderRegistry(repository.DefaultGitHubConfig())
+		if _, ref, err := providers.Resolve(remote); err == nil {
+			repoInfo.Owner = ref.Owner
+			repoInfo.Name = ref.Name
+			repoInfo.Provider = ref.Provider
+		}
+	}
+
+	return repoInfo, nil
+}
+
+// Scan runs the detection pipeline over a repository that is already on disk.
+// Failures are recorded on the returned result.
+func (s *Scanner) Scan(ctx context.Context, repoInfo *repository.RepositoryInfo) *ScanResult {
+	result := NewScanResult()
+	result.Repository = repoInfo
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	allow, err := s.loadAllowlist(repoInfo.LocalPath)
+	if err != nil {
+		result.Error = fmt.Sprintf("Allowlist failed: %v", err)
+		return result
+	}
+
+	// Set up detectors
+	s.logf("🔧 Setting up detection pipeline...\n")
+
+	detectors := s.setupDetectors()
+
+	s.logf("✅ %d detectors configured\n", len(detectors))
+
+	fileCache := s.openCache(ctx, repoInfo, detectors)
+
+	// Discover files
+	s.logf("🔍 Discovering files to scan...\n")
+
+	fileDiscovery := discovery.NewFileDiscovery(s.config.Discovery)
+
+	files, err := fileDiscovery.DiscoverFiles(ctx, repoInfo.LocalPath)
+	if err != nil {
+		result.Error = fmt.Sprintf("File discovery failed: %v", err)
+		return result
+	}
+
+	oversized := fileDiscovery.Oversized()
+	for _, file := range oversized {
+		result.Stats.SkippedBySize = append(result.Stats.SkippedBySize, file.Path)
+	}
+	result.Stats.TotalFiles = len(files) + len(oversized)
+
+	s.logf("✅ Discovered %d files\n", len(files))
+	if len(oversized) > 0 {
+		s.logf("⚠️  Skipping %d files over the %d byte size limit\n", len(oversized), s.config.Discovery.MaxFileSize)
+	}
+
+	// Read files as the workers keep up, so only the memory budget's worth of
+	// content is held at once. The reader keeps its own counts until it is done.
+	var read ScanStats
+	chunkSize := s.chunkSize()
+	findings, filesScanned, err := s.processStream(ctx, detectors, result, fileCache, func(submit func(processing.FileJob) error) error {
+		for _, file := range files {
+			if file.IsArchive {
+				if err := s.submitArchive(ctx, fileDiscovery, file, submit, &read); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if file.IsDocument {
+				content, err := os.ReadFile(file.Path)
+				if err != nil {
+					s.logf("⚠️  Could not read file %s: %v\n", file.Path, err)
+					read.SkippedFiles++
+					continue
+				}
+				extracted, err := s.submitDocument(file.Path, content, file, submit, &read)
+				if err != nil {
+					return err
+				}
+				if !extracted {
+					read.SkippedFiles++
+					continue
+				}
+				read.ScannedFiles++
+				read.TotalSize += int64(len(content))
+				continue
+			}
+
+			if file.IsBinary {
+				read.SkippedFiles++
+				continue
+			}
+
+			if file.Size > chunkSize {
+				size, err := s.submitFileChunks(file, submit)
+				if err != nil {
+					return err
+				}
+				if size < 0 {
+					read.SkippedFiles++
+					continue
+				}
+				read.ScannedFiles++
+				read.ChunkedFiles++
+				read.TotalSize += size
+				continue
+			}
+
+			content, err := os.ReadFile(file.Path)
+			if err != nil {
+				s.logf("⚠️  Could not read file %s: %v\n", file.Path, err)
+				read.SkippedFiles++
+				continue
+			}
+
+			if err := submit(processing.FileJob{FilePath: file.Path, Content: content, FileInfo: file}); err != nil {
+				return err
+			}
+			read.ScannedFiles++
+			read.TotalSize += int64(len(content))
+		}
+		return nil
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("File processing failed: %v", err)
+		return result
+	}
+
+	result.Stats.SkippedFiles = read.SkippedFiles + len(oversized)
+	result.Stats.ScannedFiles = read.ScannedFiles
+	result.Stats.ChunkedFiles = read.ChunkedFiles
+	result.Stats.ArchiveEntries = read.ArchiveEntries
+	result.Stats.DocumentFiles = read.DocumentFiles
+	result.Stats.SkippedBySize = append(result.Stats.SkippedBySize, read.SkippedBySize...)
+	result.Stats.TruncatedArchives = read.TruncatedArchives
+	result.Stats.TotalSize = read.TotalSize
+
+	s.logf("📋 Read %d files (%d skipped)\n", read.ScannedFiles, read.SkippedFiles)
+
+	if fileCache != nil {
+		s.recordScan(ctx, fileCache, repoInfo, result)
+	}
+
+	s.finish(ctx, result, findings, filesScanned, allow)
+	return result
+}
+
+// withTimeout applies the configured scan timeout, if any, to the context
+func (s *Scanner) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.config.Timeout > 0 {
+		return context.WithTimeout(ctx, s.config.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// processJobs runs the detectors, without a cache, over the jobs produce
+// submits and returns the findings together with the number of files
+// processed. Jobs too large to scan whole are split into overlapping windows.
+// A failed submission is returned to produce as a submitError.
+func (s *Scanner) processJobs(ctx context.Context, detectors []detection.Detector, result *ScanResult,
+	produce func(submit func(processing.FileJob) error) error) ([]detection.Finding, int, error) {
+
+	chunkSize := s.chunkSize()
+	chunked := 0
+	findings, filesProcessed, err := s.processStream(ctx, detectors, result, nil, func(submit func(processing.FileJob) error) error {
+		return produce(func(job processing.FileJob) error {
+			if int64(len(job.Content)) <= chunkSize {
+				if err := submit(job); err != nil {
+					return submitError{err}
+				}
+				return nil
+			}
+			chunked++
+			content := job.Content
+			job.Content = nil
+			_, err := s.submitChunks(job, bytes.NewReader(content), submit)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// chunked is only read once produce has returned
+	result.Stats.ChunkedFiles += chunked
+	return findings, filesProcessed, nil
+}
+
+// submitFileChunks submits a file too large to scan whole as overlapping
+// windows, read from disk as the workers take them. It returns the bytes
+// read, or -1 when the file could not be read; only a failed submission is
+// returned as an error.
+func (s *Scanner) submitFileChunks(file discovery.FileResult, submit func(processing.FileJob) error) (int64, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		s.logf("⚠️  Could not read file %s: %v\n", file.Path, err)
+		return -1, nil
+	}
+	defer f.Close()
+
+	size, err := s.submitChunks(processing.FileJob{FilePath: file.Path, FileInfo: file}, f, submit)
+	var failed submitError
+	if errors.As(err, &failed) {
+		return 0, err
+	}
+	if err != nil {
+		// Windows already submitted are still scanned
+		s.logf("⚠️  Could not read all of file %s: %v\n", file.Path, err)
+	}
+	return size, nil
+}
+
+// submitArchive submits the files inside an archive as jobs named by their
+// virtual paths, such as data/export.zip!/customers.csv, and records them in
+// the reader's statistics. Only a failed submission is returned as an error;
+// files read before an unreadable entry or an archive limit are still scanned.
+func (s *Scanner) submitArchive(ctx context.Context, fileDiscovery *discovery.FileDiscovery, file discovery.FileResult,
+	submit func(processing.FileJob) error, read *ScanStats) error {
+
+	chunkSize := s.chunkSize()
+	extractDocuments := s.config.Discovery.ExtractDocuments
+	skipped, err := fileDiscovery.ExpandArchive(ctx, file, func(path string, content []byte) error {
+		job := processing.FileJob{
+			FilePath: path,
+			Content:  content,
+			FileInfo: discovery.FileResult{Path: path, Size: int64(len(content))},
+		}
+		if extractDocuments && extract.Supported(path) {
+			job.FileInfo.IsDocument = true
+			extracted, err := s.submitDocument(path, content, job.FileInfo, submit, read)
+			if err != nil || !extracted {
+				return err
+			}
+		} else if int64(len(content)) > chunkSize {
+			job.Content = nil
+			if _, err := s.submitChunks(job, bytes.NewReader(content), submit); err != nil {
+				return err
+			}
+			read.ChunkedFiles++
+		} else if err := submit(job); err != nil {
+			return submitError{err}
+		}
+
+		read.ArchiveEntries++
+		read.TotalSize += int64(len(content))
+		return nil
+	})
+
+	var failed submitError
+	if errors.As(err, &failed) {
+		return err
+	}
+	if err != nil {
+		s.logf("⚠️  Could not read all of archive %s: %v\n", file.Path, err)
+	}
+	if errors.Is(err, discovery.ErrArchiveLimit) {
+		read.TruncatedArchives = append(read.TruncatedArchives, file.Path)
+	}
+
+	read.SkippedBySize = append(read.SkippedBySize, skipped...)
+	read.ScannedFiles++
+	return nil
+}
+
+// submitDocument extracts the text of an Office document or PDF and submits
+// it, so findings are located by sheet and cell or by page and paragraph. It
+// reports whether the text could be extracted; only a failed submission is
+// returned as an error, as a submitError.
+func (s *Scanner) submitDocument(path string, content []byte, info discovery.FileResult,
+	submit func(processing.FileJob) error, read *ScanStats) (bool, error) {
+
+	doc, err := extract.Extract(path, content)
+	if err != nil {
+		s.logf("⚠️  Could not extract text from %s: %v\n", path, err)
+		return false, nil
+	}
+
+	job := processing.FileJob{FilePath: path, Content: doc.Text, FileInfo: info, Locations: doc.Locations}
+	if int64(len(doc.Text)) > s.chunkSize() {
+		job.Content = nil
+		if _, err := s.submitChunks(job, bytes.NewReader(doc.Text), submit); err != nil {
+			return false, err
+		}
+		read.ChunkedFiles++
+	} else if err := submit(job); err != nil {
+		return false, submitError{err}
+	}
+
+	read.DocumentFiles++
+	return true, nil
+}
+
+// submitError marks a failed submission while chunking, as opposed to a
+// failed read
+type submitError struct{ error }
+
+func (e submitError) Unwrap() error { return e.error }
+
+// submitChunks submits r as overlapping windows of job and returns the bytes
+// read. A failed submission is returned as a submitError.
+func (s *Scanner) submitChunks(job processing.FileJob, r io.Reader, submit func(processing.FileJob) error) (int64, error) {
+	var size int64
+	err := processing.ReadChunks(r, int(s.chunkSize()), s.chunkOverlap(), func(content []byte, chunk processing.Chunk) error {
+		window := job
+		window.Content = content
+		window.Chunk = &chunk
+		size = chunk.Offset + int64(len(content))
+		if err := submit(window); err != nil {
+			return submitError{err}
+		}
+		return nil
+	})
+	return size, err
+}
+
+// chunkSize returns the size above which content is scanned in windows, and
+// the size of those windows. Windows never exceed the pattern detector's
+// file size limit.
+func (s *Scanner) chunkSize() int64 {
+	limit := detection.DefaultConfig().MaxFileSize
+	if s.config.Detection != nil && s.config.Detection.MaxFileSize > 0 {
+		limit = s.config.Detection.MaxFileSize
+	}
+	if s.config.ChunkSize > 0 && s.config.ChunkSize < limit {
+		return s.config.ChunkSize
+	}
+	return limit
+}
+
+// chunkOverlap returns the bytes each window shares with the next
+func (s *Scanner) chunkOverlap() int {
+	if s.config.ChunkOverlap > 0 {
+		return s.config.ChunkOverlap
+	}
+	return processing.DefaultChunkOverlap
+}
+
+// processStream runs the detectors over the jobs produce submits and returns
+// the findings together with the number of files processed. Submission blocks
+// while the workers are busy or the memory budget is spent.
+func (s *Scanner) processStream(ctx context.Context, detectors []detection.Detector, result *ScanResult, fileCache *cache.Cache,
+	produce func(submit func(processing.FileJob) error) error) ([]detection.Finding, int, error) {
+
+	processorConfig := processing.DefaultProcessorConfig()
+	processorConfig.NumWorkers = s.config.NumWorkers
+	processorConfig.Cache = fileCache
+	processorConfig.Decoding = s.config.Decoding
+	if s.config.MemoryBudget > 0 {
+		processorConfig.MemoryBudget = s.config.MemoryBudget
+	}
+
+	fileProcessor := processing.NewFileProcessor(processorConfig, detectors)
+
+	s.logf("🚀 Starting file processing with %d workers...\n", processorConfig.NumWorkers)
+
+	processingStart := time.Now()
+
+	var findings []detection.Finding
+	filesProcessed := 0
+	err := fileProcessor.Stream(ctx, produce, func(procResult processing.ProcessingResult) {
+		// Count chunked files once, by their first window
+		if procResult.Chunk == nil || procResult.Chunk.Index == 0 {
+			filesProcessed++
+		}
+		if procResult.Error != nil {
+			s.logf("⚠️  Error processing %s: %v\n", procResult.FilePath, procResult.Error)
+			return
+		}
+		findings = append(findings, procResult.Findings...)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result.Stats.ProcessingTime = time.Since(processingStart)
+
+	s.logf("✅ Processing completed in %v\n", result.Stats.ProcessingTime)
+
+	return findings, filesProcessed, nil
+}
+
+// finish scores the findings and records them with statistics on the result
+func (s *Scanner) finish(ctx context.Context, result *ScanResult, findings []detection.Finding, filesScanned int, allow *allowlist.Allowlist) {
+	root := ""
+	if result.Repository != nil {
+		root = result.Repository.LocalPath
+	}
+
+	if allow != nil {
+		result.ExpiredAllowlistEntries = allow.Apply(findings, root, time.Now())
+		for _, entry := range result.ExpiredAllowlistEntries {
+			s.logf("⚠️  Allowlist entry expired on %s: %s\n", entry.Expires.Format("2006-01-02"), entry.Justification)
+		}
+	}
+
+	if s.config.Baseline != nil {
+		absent, summary := s.config.Baseline.Compare(findings, root)
+		result.AbsentFindings = absent
+		result.Baseline = &summary
+	}
+
+	// The stats and policy take the assessed risk level into account, so
+	// score first
+	result.Findings = s.scoreFindings(ctx, findings, root, result.Repository)
+	leveled := policyFindings(result.Findings)
+
+	for _, finding := range leveled {
+		result.Stats.FindingsByType[string(finding.Type)] += finding.Records()
+		result.Stats.FindingsByRisk[string(finding.RiskLevel)] += finding.Records()
+		if finding.Suppressed() {
+			result.Stats.Suppressed++
+		}
+	}
+
+	result.PolicyViolations = s.config.Policy.Evaluate(leveled)
+
+	result.FilesScanned = filesScanned
+	result.ScanFinished = time.Now()
+	result.Duration = result.ScanFinished.Sub(result.ScanStarted)
+
+	s.printSummary(result)
+}
+
+// scoreFindings attaches confidence scores and risk assessments to findings.
+// If the scoring engines cannot be created the findings are kept unscored.
+func (s *Scanner) scoreFindings(ctx context.Context, findings []detection.Finding, root string, repoInfo *repository.RepositoryInfo) []Finding {
+	s.logf("🧮 Scoring %d findings...\n", len(findings))
+
+	scorer, err := newFindingScorer(root, repoInfo)
+	if err != nil {
+		s.logf("⚠️  Risk scoring unavailable: %v\n", err)
+		scored := make([]Finding, len(findings))
+		for i, f := range findings {
+			scored[i].Finding = f
+		}
+		return scored
+	}
+
+	return scorer.score(ctx, findings)
+}
+
+// loadAllowlist returns the configured allowlist or, when none is configured,
+// the allowlist file at the repository root if one exists
+func (s *Scanner) loadAllowlist(root string) (*allowlist.Allowlist, error) {
+	if s.config.Allowlist != nil {
+		return s.config.Allowlist, nil
+	}
+
+	path := filepath.Join(root, allowlist.DefaultFile)
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+
+	s.logf("📝 Using allowlist: %s\n", path)
+	return allowlist.Load(path)
+}
+
+// setupDetectors creates the pattern detector and, when configured, the Gitleaks detector
+func (s *Scanner) setupDetectors() []detection.Detector {
+	detectors := []detection.Detector{detection.NewDetector()}
+	if s.config.Detection != nil {
+		detectors[0] = detection.NewDetectorWithConfig(s.config.Detection)
+	}
+
+	if s.config.GitleaksConfigPath == "" {
+		return detectors
+	}
+
+	if _, err := os.Stat(s.config.GitleaksConfigPath); err != nil {
+		s.logf("⚠️  Gitleaks config not found, skipping\n")
+		return detectors
+	}
+
+	gitleaksDetector, err := detection.NewGitleaksDetector(s.config.GitleaksConfigPath)
+	if err != nil {
+		s.logf("⚠️  Gitleaks detector setup failed: %v\n", err)
+		return detectors
+	}
+
+	s.logf("✅ Gitleaks detector loaded\n")
+	return append(detectors, gitleaksDetector)
+}
+
+// printSummary prints a verbose summary of the scan
+func (s *Scanner) printSummary(result *ScanResult) {
+	if !s.config.Verbose {
+		return
+	}
+
+	s.logf("🎯 Scan Summary:\n")
+	s.logf("   • Duration: %v\n", result.Duration)
+	s.logf("   • Files scanned: %d\n", result.FilesScanned)
+	s.logf("   • Total findings: %d\n", len(result.Findings))
+
+	if len(result.Stats.FindingsByType) > 0 {
+		s.logf("   • Findings by type:\n")
+		for piType, count := range result.Stats.FindingsByType {
+			s.logf("     - %s: %d\n", piType, count)
+		}
+	}
+
+	if len(result.Stats.FindingsByRisk) > 0 {
+		s.logf("   • Findings by risk:\n")
+		for risk, count := range result.Stats.FindingsByRisk {
+			s.logf("     - %s: %d\n", risk, count)
+		}
+	}
+
+	if result.Stats.Suppressed > 0 {
+		s.logf("   • Suppressed: %d\n", result.Stats.Suppressed)
+	}
+
+	if result.Stats.ChunkedFiles > 0 {
+		s.logf("   • Large files scanned in chunks: %d\n", result.Stats.ChunkedFiles)
+	}
+
+	if result.Stats.DocumentFiles > 0 {
+		s.logf("   • Office documents and PDFs scanned: %d\n", result.Stats.DocumentFiles)
+	}
+
+	if len(result.Stats.SkippedBySize) > 0 {
+		s.logf("   • Skipped over the size limit: %d\n", len(result.Stats.SkippedBySize))
+		for _, path := range result.Stats.SkippedBySize {
+			s.logf("     - %s\n", path)
+		}
+	}
+
+	if len(result.Stats.TruncatedArchives) > 0 {
+		s.logf("   • Archives scanned only up to their limits: %d\n", len(result.Stats.TruncatedArchives))
+		for _, path := range result.Stats.TruncatedArchives {
+			s.logf("     - %s\n", path)
+		}
+	}
+
+	if b := result.Baseline; b != nil {
+		s.logf("   • Baseline: %d new, %d unchanged, %d absent\n", b.New, b.Unchanged, b.Absent)
+	}
+
+	for _, v := range result.PolicyViolations {
+		s.logf("   • Policy violated: %s\n", v.Message)
+	}
+}
+
+// logf prints a progress message when verbose output is enabled
+func (s *Scanner) logf(format string, args ...interface{}) {
+	if s.config.Verbose {
+		fmt.Fprintf(s.config.Output, format, args...)
+	}
+}
@@ -0,0 +1,352 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.GitleaksConfigPath = ""
+	return config
+}
+
+func TestScanPath_Directory(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "src/payroll.go", "package payroll\n\nconst employeeTFN = \"123456782\"\n")
+	writeFile(t, root, "README.md", "# Payroll\n")
+	writeFile(t, root, "logo.png", "\x89PNG\r\n\x1a\n\x00\x00\x00")
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+
+	require.NotNil(t, result.Repository)
+	assert.Equal(t, filepath.Base(root), result.Repository.Name)
+	assert.Equal(t, root, result.Repository.LocalPath)
+	assert.Empty(t, result.Repository.URL)
+	assert.Empty(t, result.Error)
+
+	assert.Equal(t, 2, result.FilesScanned)
+	assert.False(t, result.ScanFinished.IsZero())
+
+	var tfnFindings []Finding
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			tfnFindings = append(tfnFindings, f)
+		}
+	}
+	require.NotEmpty(t, tfnFindings)
+	assert.Equal(t, filepath.Join(root, "src", "payroll.go"), tfnFindings[0].File)
+	assert.Equal(t, len(tfnFindings), result.Stats.FindingsByType[string(detection.PITypeTFN)])
+}
+
+func TestScanPath_MemoryBudget(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 40; i++ {
+		writeFile(t, root, fmt.Sprintf("payroll/employee%02d.go", i), "package payroll\n\nconst employeeTFN = \"123456782\"\n")
+	}
+
+	// A budget smaller than any file still scans everything, one file at a time
+	config := testConfig()
+	config.NumWorkers = 2
+	config.MemoryBudget = 1
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	assert.Equal(t, 40, result.FilesScanned)
+	assert.Equal(t, 40, result.Stats.ScannedFiles)
+	assert.Equal(t, int64(40*len("package payroll\n\nconst employeeTFN = \"123456782\"\n")), result.Stats.TotalSize)
+	assert.Equal(t, 40, result.Stats.FindingsByType[string(detection.PITypeTFN)])
+}
+
+func TestScanPath_LargeFiles(t *testing.T) {
+	root := t.TempDir()
+	var dump strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&dump, "INSERT INTO employees VALUES (%03d, '123456782');\n", i)
+	}
+	writeFile(t, root, "dump.sql", dump.String())
+	writeFile(t, root, "archive.sql", strings.Repeat("-- nothing here\n", 1024))
+
+	// Files over the chunk size are scanned in windows; files over the
+	// discovery limit are skipped and listed
+	config := testConfig()
+	config.ChunkSize = 512
+	config.ChunkOverlap = 64
+	config.Discovery.MaxFileSize = 8 * 1024
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	assert.Equal(t, 1, result.FilesScanned)
+	assert.Equal(t, 1, result.Stats.ChunkedFiles)
+	assert.Equal(t, 2, result.Stats.TotalFiles)
+	assert.Equal(t, 1, result.Stats.SkippedFiles)
+	assert.Equal(t, []string{filepath.Join(root, "archive.sql")}, result.Stats.SkippedBySize)
+	assert.Equal(t, int64(dump.Len()), result.Stats.TotalSize)
+
+	// Each row is reported once, on its own line of the file
+	lines := make(map[int]int)
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			lines[f.Line]++
+			assert.Equal(t, 37, f.Column)
+		}
+	}
+	assert.Len(t, lines, 100)
+	for line, count := range lines {
+		assert.Equal(t, 1, count, "line %d", line)
+	}
+}
+
+func zipFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func TestScanPath_Archives(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "data/export.zip", zipFiles(t, map[string]string{
+		"customers.sql": "INSERT INTO employees VALUES ('Jane', '123456782');\n",
+		"big.sql":       strings.Repeat("-- padding\n", 200),
+	}))
+
+	config := testConfig()
+	config.Discovery.Archives.MaxEntrySize = 1024
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	archive := filepath.Join(root, "data", "export.zip")
+	assert.Equal(t, 1, result.Stats.ScannedFiles)
+	assert.Equal(t, 1, result.Stats.ArchiveEntries)
+	assert.Equal(t, []string{archive + "!/big.sql"}, result.Stats.SkippedBySize)
+
+	var tfnFindings []Finding
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			tfnFindings = append(tfnFindings, f)
+		}
+	}
+	require.Len(t, tfnFindings, 1)
+	assert.Equal(t, archive+"!/customers.sql", tfnFindings[0].File)
+	assert.Equal(t, 1, tfnFindings[0].Line)
+}
+
+func TestScanPath_TruncatedArchives(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "data/export.zip", zipFiles(t, map[string]string{
+		"a.sql": "INSERT INTO employees VALUES ('Jane', '123456782');\n",
+		"b.sql": "INSERT INTO employees VALUES ('John', '123456782');\n",
+		"c.sql": "INSERT INTO employees VALUES ('Mary', '123456782');\n",
+	}))
+	writeFile(t, root, "data/small.zip", zipFiles(t, map[string]string{
+		"a.sql": "-- empty\n",
+	}))
+
+	config := testConfig()
+	config.Discovery.Archives.MaxEntries = 2
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	// Entries read before the limit are still scanned
+	assert.Equal(t, 3, result.Stats.ArchiveEntries)
+	assert.Equal(t, []string{filepath.Join(root, "data", "export.zip")}, result.Stats.TruncatedArchives)
+	assert.Empty(t, result.Stats.SkippedBySize)
+}
+
+func TestScanPath_Documents(t *testing.T) {
+	root := t.TempDir()
+	workbook := zipFiles(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Payroll" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="inlineStr"><is><t>Employee</t></is></c><c r="B1" t="inlineStr"><is><t>TFN</t></is></c></row>` +
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>Jane Citizen</t></is></c><c r="B2" t="inlineStr"><is><t>123 456 782</t></is></c></row>` +
+			`</sheetData></worksheet>`,
+	})
+	writeFile(t, root, "exports/payroll.xlsx", workbook)
+	writeFile(t, root, "exports/backup.zip", zipFiles(t, map[string]string{"payroll.xlsx": workbook}))
+	writeFile(t, root, "exports/broken.pdf", "not a pdf")
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	assert.Equal(t, 2, result.Stats.DocumentFiles)
+	assert.Equal(t, 2, result.Stats.ScannedFiles)
+	assert.Equal(t, 1, result.Stats.SkippedFiles)
+
+	tfnFindings := make(map[string]*detection.DocumentLocation)
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			tfnFindings[f.File] = f.Location
+		}
+	}
+	location := &detection.DocumentLocation{Sheet: "Payroll", Cell: "B2"}
+	assert.Equal(t, map[string]*detection.DocumentLocation{
+		filepath.Join(root, "exports", "payroll.xlsx"):                  location,
+		filepath.Join(root, "exports", "backup.zip") + "!/payroll.xlsx": location,
+	}, tfnFindings)
+
+	// Without extraction documents are binary files, excluded as before
+	config := testConfig()
+	config.Discovery.ExtractDocuments = false
+	result, err = ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	assert.Zero(t, result.Stats.DocumentFiles)
+	assert.Empty(t, result.Findings)
+}
+
+// validTFNs returns n distinct tax file numbers passing the checksum
+func validTFNs(n int) []string {
+	weights := []int{1, 4, 3, 7, 5, 8, 6, 9, 10}
+	var tfns []string
+	for base := 87654321; len(tfns) < n; base += 7 {
+		digits := fmt.Sprintf("%08d", base)
+		sum := 0
+		for i, d := range digits {
+			sum += int(d-'0') * weights[i]
+		}
+		// The check digit has weight 10, so sum + 10*check must be a multiple of 11
+		for check := 0; check <= 9; check++ {
+			if (sum+10*check)%11 == 0 {
+				tfns = append(tfns, fmt.Sprintf("%s%d", digits, check))
+				break
+			}
+		}
+	}
+	return tfns
+}
+
+func TestScanPath_StructuredData(t *testing.T) {
+	root := t.TempDir()
+
+	var csv strings.Builder
+	csv.WriteString("employee,tfn\n")
+	for i, tfn := range validTFNs(12) {
+		fmt.Fprintf(&csv, "Employee %d,%s\n", i+1, tfn)
+	}
+	writeFile(t, root, "exports/payroll.csv", csv.String())
+	writeFile(t, root, "exports/customers.json", `{"customers": [{"name": "Jane"}, {"email": "jane.citizen@example.com"}]}`)
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	var tfns, emails []Finding
+	for _, f := range result.Findings {
+		switch f.Type {
+		case detection.PITypeTFN:
+			tfns = append(tfns, f)
+		case detection.PITypeEmail:
+			emails = append(emails, f)
+		}
+	}
+
+	// The column of TFNs is one finding counting its rows
+	require.Len(t, tfns, 1)
+	assert.Equal(t, 2, tfns[0].Line)
+	assert.Equal(t, "tfn", tfns[0].Field)
+	assert.Equal(t, 12, tfns[0].RecordCount)
+	require.NotNil(t, tfns[0].RiskAssessment)
+	assert.Equal(t, 12, tfns[0].RiskAssessment.ImpactFactors.RecordCount)
+
+	require.Len(t, emails, 1)
+	assert.Equal(t, "$.customers[1].email", emails[0].Field)
+}
+
+func TestScanPath_GitCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "https://token@github.com/example-org/payroll.git"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		require.NoError(t, cmd.Run())
+	}
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+
+	require.NotNil(t, result.Repository)
+	assert.Equal(t, "https://github.com/example-org/payroll.git", result.Repository.URL)
+	assert.Equal(t, "example-org", result.Repository.Owner)
+	assert.Equal(t, "payroll", result.Repository.Name)
+	assert.Equal(t, 1, result.Repository.FileCount)
+}
+
+func TestScanPath_InvalidPath(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "file.txt", "hello")
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing directory", filepath.Join(root, "missing")},
+		{"regular file", filepath.Join(root, "file.txt")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ScanPath(context.Background(), tt.path, testConfig())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScanner_VerboseOutput(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "notes.txt", "nothing to see here\n")
+
+	var out bytes.Buffer
+	config := testConfig()
+	config.Verbose = true
+	config.Output = &out
+
+	_, err := New(config).ScanPath(context.Background(), root)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "Discovered 1 files")
+	assert.Contains(t, out.String(), "Scan Summary")
+}
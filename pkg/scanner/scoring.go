@@ -0,0 +1,270 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/MacAttak/pi-scanner/pkg/scoring"
+)
+
+// maxCoOccurrences limits how many neighbouring findings in the same file are
+// considered when scoring a finding, keeping files with many matches linear
+const maxCoOccurrences = 50
+
+// Finding is a detection finding together with its confidence score and
+// risk assessment. The detection fields are embedded so saved scan results
+// keep their flat JSON layout.
+type Finding struct {
+	detection.Finding
+	ConfidenceResult *scoring.ConfidenceResult `json:"confidence_result,omitempty"`
+	RiskAssessment   *scoring.RiskAssessment   `json:"risk_assessment,omitempty"`
+}
+
+// DetectionFindings returns the findings without their scoring details
+func (r *ScanResult) DetectionFindings() []detection.Finding {
+	findings := make([]detection.Finding, len(r.Findings))
+	for i, f := range r.Findings {
+		findings[i] = f.Finding
+	}
+	return findings
+}
+
+// AssessedRiskLevel returns the risk level of the finding's risk assessment,
+// or the detector's risk level when it was not scored
+func (f Finding) AssessedRiskLevel() detection.RiskLevel {
+	if f.RiskAssessment != nil && f.RiskAssessment.RiskLevel != "" {
+		return detection.RiskLevel(f.RiskAssessment.RiskLevel)
+	}
+	return f.RiskLevel
+}
+
+// PolicyRiskLevel returns the higher of the detector's and the assessed risk
+// levels. The risk matrix can rate a validated identifier in source code
+// lower than its detector does, which must not let it past --fail-on.
+func (f Finding) PolicyRiskLevel() detection.RiskLevel {
+	if assessed := f.AssessedRiskLevel(); assessed.Severity() > f.RiskLevel.Severity() {
+		return assessed
+	}
+	return f.RiskLevel
+}
+
+// policyFindings returns the findings with the risk levels policies use
+func policyFindings(findings []Finding) []detection.Finding {
+	leveled := make([]detection.Finding, len(findings))
+	for i, f := range findings {
+		leveled[i] = f.Finding
+		leveled[i].RiskLevel = f.PolicyRiskLevel()
+	}
+	return leveled
+}
+
+// findingScorer runs the confidence engine and risk matrix over findings
+type findingScorer struct {
+	engine *scoring.ConfidenceEngine
+	matrix *scoring.RiskMatrix
+	root   string
+	repo   scoring.RepositoryInfo
+}
+
+// newFindingScorer creates a scorer for findings under root. Repository
+// details raise the exposure of public, popular and active repositories.
+func newFindingScorer(root string, repoInfo *repository.RepositoryInfo) (*findingScorer, error) {
+	engine, err := scoring.NewConfidenceEngine(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create confidence engine: %w", err)
+	}
+
+	matrix, err := scoring.NewRiskMatrix(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create risk matrix: %w", err)
+	}
+
+	return &findingScorer{engine: engine, matrix: matrix, root: root, repo: scoringRepositoryInfo(repoInfo)}, nil
+}
+
+// scoringRepositoryInfo converts repository details for the risk matrix.
+// Repositories of unknown visibility are treated as private.
+func scoringRepositoryInfo(repoInfo *repository.RepositoryInfo) scoring.RepositoryInfo {
+	if repoInfo == nil {
+		return scoring.RepositoryInfo{}
+	}
+	return scoring.RepositoryInfo{
+		IsPublic:      repoInfo.Visibility == repository.VisibilityPublic,
+		Stars:         repoInfo.Stars,
+		Forks:         repoInfo.Forks,
+		Contributors:  repoInfo.Contributors,
+		LastCommit:    repoInfo.LastCommit,
+		DefaultBranch: repoInfo.DefaultBranch,
+		HasCICD:       repoInfo.HasCICD,
+	}
+}
+
+// score scores every finding, using the other findings in the same file as
+// co-occurrences. Findings the engines cannot score are kept without scores.
+func (fs *findingScorer) score(ctx context.Context, findings []detection.Finding) []Finding {
+	scored := make([]Finding, len(findings))
+
+	byFile := make(map[string][]int)
+	for i, f := range findings {
+		scored[i].Finding = f
+		byFile[f.File] = append(byFile[f.File], i)
+	}
+
+	for file, indexes := range byFile {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return findings[indexes[a]].Line < findings[indexes[b]].Line
+		})
+
+		fileContext := fs.fileContext(file)
+
+		for pos, i := range indexes {
+			neighbours := nearbyFindings(findings, indexes, pos)
+			scored[i].ConfidenceResult, scored[i].RiskAssessment = fs.scoreFinding(ctx, findings[i], neighbours, fileContext)
+		}
+	}
+
+	return scored
+}
+
+// scoreFinding calculates the confidence and risk of a single finding
+func (fs *findingScorer) scoreFinding(ctx context.Context, f detection.Finding, neighbours []detection.Finding, fileContext scoring.FileContext) (*scoring.ConfidenceResult, *scoring.RiskAssessment) {
+	input := scoring.ScoreInput{
+		Finding:       f,
+		Content:       f.Context,
+		CoOccurrences: coOccurrences(f, neighbours),
+		ScanTimestamp: f.DetectedAt,
+	}
+	if f.Validated || f.ValidationError != "" {
+		input.ValidationScore = &scoring.ValidationScore{
+			IsValid:    f.Validated,
+			Algorithm:  "checksum",
+			Confidence: 1.0,
+			Details:    f.ValidationError,
+		}
+	}
+
+	// Types the confidence engine does not support are assessed with the
+	// detector's own confidence
+	confidenceScore := float64(f.Confidence)
+	confidence, err := fs.engine.CalculateScore(ctx, input)
+	if err == nil {
+		confidenceScore = confidence.FinalScore
+	}
+
+	assessment, err := fs.matrix.AssessRisk(scoring.RiskAssessmentInput{
+		Finding:         f,
+		ConfidenceScore: confidenceScore,
+		RepositoryInfo:  fs.repo,
+		FileContext:     fileContext,
+		CoOccurrences:   neighbours,
+	})
+	if err != nil {
+		return confidence, nil
+	}
+
+	return confidence, assessment
+}
+
+// fileContext describes the file a finding was made in
+func (fs *findingScorer) fileContext(file string) scoring.FileContext {
+	relPath := file
+	if fs.root != "" {
+		if rel, err := filepath.Rel(fs.root, file); err == nil {
+			relPath = rel
+		}
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	fc := scoring.FileContext{
+		FilePath:        relPath,
+		IsTest:          isTestPath(relPath),
+		IsConfiguration: isConfigPath(relPath),
+		Language:        strings.TrimPrefix(strings.ToLower(filepath.Ext(relPath)), "."),
+	}
+	fc.IsProduction = !fc.IsTest
+	fc.IsSource = !fc.IsConfiguration && fc.Language != "" && !isDataPath(relPath)
+
+	if info, err := os.Stat(file); err == nil {
+		fc.FileSize = info.Size()
+	}
+
+	return fc
+}
+
+// nearbyFindings returns up to maxCoOccurrences findings closest to
+// indexes[pos] in the same file, excluding the finding itself
+func nearbyFindings(findings []detection.Finding, indexes []int, pos int) []detection.Finding {
+	start := pos - maxCoOccurrences/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxCoOccurrences + 1
+	if end > len(indexes) {
+		end = len(indexes)
+		start = end - maxCoOccurrences - 1
+		if start < 0 {
+			start = 0
+		}
+	}
+
+	neighbours := make([]detection.Finding, 0, end-start)
+	for p := start; p < end; p++ {
+		if p != pos {
+			neighbours = append(neighbours, findings[indexes[p]])
+		}
+	}
+	return neighbours
+}
+
+// coOccurrences converts neighbouring findings into confidence engine input,
+// measuring distance in lines
+func coOccurrences(f detection.Finding, neighbours []detection.Finding) []scoring.CoOccurrence {
+	result := make([]scoring.CoOccurrence, 0, len(neighbours))
+	for _, n := range neighbours {
+		distance := n.Line - f.Line
+		if distance < 0 {
+			distance = -distance
+		}
+		result = append(result, scoring.CoOccurrence{
+			PIType:   n.Type,
+			Distance: distance,
+			Match:    n.Match,
+		})
+	}
+	return result
+}
+
+// isTestPath reports whether a path looks like test code or test data
+func isTestPath(path string) bool {
+	lower := strings.ToLower(path)
+	for _, marker := range []string{"test", "spec", "fixture", "mock"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConfigPath reports whether a path looks like a configuration file
+func isConfigPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json", ".toml", ".ini", ".cfg", ".conf", ".config", ".env", ".properties":
+		return true
+	}
+	return strings.HasPrefix(filepath.Base(path), ".env")
+}
+
+// isDataPath reports whether a path looks like a data or document file
+func isDataPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv", ".txt", ".md", ".log", ".xml", ".sql":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/MacAttak/pi-scanner/pkg/policy"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+	"github.com/MacAttak/pi-scanner/pkg/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanPath_ScoresFindings(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "src/payroll.go", "package payroll\n\nconst employeeTFN = \"123456782\"\n")
+
+	result, err := ScanPath(context.Background(), root, testConfig())
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Findings)
+
+	for _, f := range result.Findings {
+		if f.Type != detection.PITypeTFN {
+			continue
+		}
+		require.NotNil(t, f.ConfidenceResult)
+		require.NotNil(t, f.RiskAssessment)
+		assert.NotEmpty(t, f.RiskAssessment.RiskLevel)
+		assert.NotEmpty(t, f.ConfidenceResult.AuditTrail)
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var loaded ScanResult
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	require.Len(t, loaded.Findings, len(result.Findings))
+	assert.Equal(t, result.Findings[0].Match, loaded.Findings[0].Match, "detection fields keep their flat layout")
+	assert.NotNil(t, loaded.Findings[0].RiskAssessment)
+	assert.NotNil(t, loaded.Findings[0].ConfidenceResult)
+}
+
+func TestScanPath_PolicyUsesHigherRiskLevel(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "src/payroll.go", "package payroll\n\nconst employeeTFN = \"123456782\"\n")
+
+	config := testConfig()
+	config.Policy = &policy.Policy{FailOn: []detection.RiskLevel{detection.RiskLevelCritical, detection.RiskLevelHigh}}
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+
+	// The risk matrix rates a validated TFN in private source code lower than
+	// the detector does, which must not let it past the policy
+	tfn := result.Findings[0]
+	require.True(t, tfn.Validated)
+	require.NotNil(t, tfn.RiskAssessment)
+	assert.Less(t, tfn.AssessedRiskLevel().Severity(), tfn.RiskLevel.Severity())
+	assert.Equal(t, tfn.RiskLevel, tfn.PolicyRiskLevel())
+
+	assert.Equal(t, map[string]int{string(tfn.RiskLevel): 1}, result.Stats.FindingsByRisk)
+	require.Len(t, result.PolicyViolations, 1)
+	assert.Equal(t, "fail-on:"+strings.ToLower(string(tfn.RiskLevel)), result.PolicyViolations[0].Rule)
+}
+
+func TestFinding_PolicyRiskLevel(t *testing.T) {
+	tests := []struct {
+		detector detection.RiskLevel
+		assessed scoring.RiskLevel
+		expected detection.RiskLevel
+	}{
+		{detection.RiskLevelCritical, scoring.RiskLevelLow, detection.RiskLevelCritical},
+		{detection.RiskLevelLow, scoring.RiskLevelHigh, detection.RiskLevelHigh},
+		{detection.RiskLevelMedium, "", detection.RiskLevelMedium},
+	}
+
+	for _, tt := range tests {
+		f := Finding{Finding: detection.Finding{RiskLevel: tt.detector}}
+		if tt.assessed != "" {
+			f.RiskAssessment = &scoring.RiskAssessment{RiskLevel: tt.assessed}
+		}
+		assert.Equal(t, tt.expected, f.PolicyRiskLevel(), "%s and %s", tt.detector, tt.assessed)
+	}
+}
+
+func TestFindingScorer_CoOccurrences(t *testing.T) {
+	root := "/repo"
+	file := filepath.Join(root, "data", "customers.go")
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: file, Line: 10, Validated: true, Confidence: 0.9},
+		{Type: detection.PITypeName, Match: "Jane Citizen", File: file, Line: 11, Confidence: 0.6},
+		{Type: detection.PITypeTFN, Match: "876543210", File: filepath.Join(root, "other.go"), Line: 3, Confidence: 0.9},
+	}
+
+	scorer, err := newFindingScorer(root, nil)
+	require.NoError(t, err)
+
+	scored := scorer.score(context.Background(), findings)
+	require.Len(t, scored, 3)
+
+	for i, f := range scored {
+		assert.Equal(t, findings[i], f.Finding, "findings keep their order")
+		require.NotNil(t, f.ConfidenceResult)
+		require.NotNil(t, f.RiskAssessment)
+	}
+
+	// A TFN next to a name scores higher than the same TFN on its own
+	assert.Greater(t, scored[0].ConfidenceResult.FinalScore, scored[2].ConfidenceResult.FinalScore)
+}
+
+func TestFindingScorer_RepositoryExposure(t *testing.T) {
+	findings := []detection.Finding{
+		{Type: detection.PITypeTFN, Match: "123456782", File: "/repo/src/payroll.go", Line: 3, Validated: true, Confidence: 0.9},
+	}
+	lastCommit := time.Now().Add(-24 * time.Hour)
+
+	score := func(repoInfo *repository.RepositoryInfo) *scoring.RiskAssessment {
+		scorer, err := newFindingScorer("/repo", repoInfo)
+		require.NoError(t, err)
+		scored := scorer.score(context.Background(), findings)
+		require.NotNil(t, scored[0].RiskAssessment)
+		return scored[0].RiskAssessment
+	}
+
+	private := score(&repository.RepositoryInfo{Visibility: repository.VisibilityPrivate, Contributors: 3, LastCommit: lastCommit})
+	public := score(&repository.RepositoryInfo{Visibility: repository.VisibilityPublic, Stars: 250, Forks: 20, Contributors: 3, LastCommit: lastCommit, HasCICD: true})
+
+	assert.Greater(t, public.ExposureScore, private.ExposureScore)
+	assert.Greater(t, public.OverallRisk, private.OverallRisk)
+	assert.Equal(t, "PUBLIC_MEDIUM_VISIBILITY", public.ExposureFactors.RepositoryVisibility)
+}
+
+func TestFindingScorer_FileContext(t *testing.T) {
+	scorer, err := newFindingScorer("/repo", nil)
+	require.NoError(t, err)
+
+	fc := scorer.fileContext("/repo/config/app.yaml")
+	assert.Equal(t, "config/app.yaml", fc.FilePath)
+	assert.True(t, fc.IsConfiguration)
+	assert.False(t, fc.IsSource)
+	assert.True(t, fc.IsProduction)
+
+	fc = scorer.fileContext("/repo/internal/fixtures/users.go")
+	assert.True(t, fc.IsTest)
+	assert.False(t, fc.IsProduction)
+	assert.True(t, fc.IsSource)
+}
+
+func TestNearbyFindings(t *testing.T) {
+	var findings []detection.Finding
+	var indexes []int
+	for i := 0; i < maxCoOccurrences*2; i++ {
+		findings = append(findings, detection.Finding{Line: i + 1})
+		indexes = append(indexes, i)
+	}
+
+	assert.Len(t, nearbyFindings(findings, indexes, 0), maxCoOccurrences)
+	assert.Len(t, nearbyFindings(findings, indexes, len(indexes)-1), maxCoOccurrences)
+	assert.Len(t, nearbyFindings(findings[:3], indexes[:3], 1), 2)
+}
+
+func TestScanPath_StructuredDataPolicy(t *testing.T) {
+	root := t.TempDir()
+
+	var csv strings.Builder
+	csv.WriteString("employee,tfn\n")
+	for i, tfn := range validTFNs(12) {
+		fmt.Fprintf(&csv, "Employee %d,%s\n", i+1, tfn)
+	}
+	writeFile(t, root, "exports/payroll.csv", csv.String())
+
+	config := testConfig()
+	config.Policy = &policy.Policy{MaxCounts: map[detection.PIType]int{detection.PITypeTFN: 10}}
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	// The column is one finding, but --max-count and the stats count its rows
+	var tfns int
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			tfns++
+		}
+	}
+	assert.Equal(t, 1, tfns)
+	assert.Equal(t, 12, result.Stats.FindingsByType[string(detection.PITypeTFN)])
+	require.Len(t, result.PolicyViolations, 1)
+	assert.Equal(t, "max-count:TFN", result.PolicyViolations[0].Rule)
+	assert.Equal(t, 12, result.PolicyViolations[0].Count)
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/MacAttak/pi-scanner/pkg/discovery"
+	"github.com/MacAttak/pi-scanner/pkg/processing"
+	"github.com/MacAttak/pi-scanner/pkg/repository"
+)
+
+// ScanStaged scans the lines added in the index (`git diff --cached`), so PI
+// can be caught by a pre-commit hook before it enters history. Changed files
+// are scanned in full as staged, so the context validator sees the same
+// surrounding code as a full scan, but only findings on added lines are
+// reported.
+func (s *Scanner) ScanStaged(ctx context.Context, repoInfo *repository.RepositoryInfo) *ScanResult {
+	result := NewScanResult()
+	result.Repository = repoInfo
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	root := repoInfo.LocalPath
+
+	allow, err := s.loadAllowlist(root)
+	if err != nil {
+		result.Error = fmt.Sprintf("Allowlist failed: %v", err)
+		return result
+	}
+
+	// Set up detectors
+	s.logf("🔧 Setting up detection pipeline...\n")
+
+	detectors := s.setupDetectors()
+
+	s.logf("✅ %d detectors configured\n", len(detectors))
+
+	// Read staged changes
+	s.logf("📝 Reading staged changes...\n")
+
+	changes, err := repository.StagedChanges(ctx, root)
+	if err != nil {
+		result.Error = fmt.Sprintf("Reading staged changes failed: %v", err)
+		return result
+	}
+
+	fileDiscovery := discovery.NewFileDiscovery(s.config.Discovery)
+	addedLines := make(map[string]map[int]bool)
+
+	// The changes are read as they are scanned rather than all at once
+	var read ScanStats
+	findings, filesScanned, err := s.processJobs(ctx, detectors, result, func(submit func(processing.FileJob) error) error {
+		for _, change := range changes {
+			if !fileDiscovery.MatchesPath(change.Path) {
+				read.SkippedFiles++
+				continue
+			}
+
+			content, err := repository.ReadStagedFile(ctx, root, change.Path)
+			if err != nil {
+				s.logf("⚠️  Skipping %s: %v\n", change.Path, err)
+				read.SkippedFiles++
+				continue
+			}
+
+			filePath := filepath.Join(root, change.Path)
+			lines := make(map[int]bool, len(change.Added))
+			for _, line := range change.Added {
+				lines[line.Number] = true
+			}
+			addedLines[filePath] = lines
+
+			if err := submit(processing.FileJob{
+				FilePath: filePath,
+				Content:  content,
+			}); err != nil {
+				return err
+			}
+			read.ScannedFiles++
+			read.TotalSize += int64(len(content))
+		}
+		return nil
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("File processing failed: %v", err)
+		return result
+	}
+
+	result.Stats.TotalFiles = len(changes)
+	result.Stats.SkippedFiles = read.SkippedFiles
+	result.Stats.ScannedFiles = read.ScannedFiles
+	result.Stats.TotalSize = read.TotalSize
+
+	s.logf("📋 Read %d staged files (%d skipped)\n", read.ScannedFiles, read.SkippedFiles)
+
+	findings = onAddedLines(findings, addedLines)
+
+	s.finish(ctx, result, findings, filesScanned, allow)
+	return result
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanStaged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("init", "-q")
+	writeFile(t, root, "payroll.go", "package payroll\n\nvar existing = \"876543210\"\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add payroll")
+
+	// Only the staged line is scanned; committed and unstaged values are not
+	writeFile(t, root, "payroll.go", "package payroll\n\nvar existing = \"876543210\"\n\nvar staged = \"123456782\"\n")
+	git("add", "payroll.go")
+	writeFile(t, root, "notes.go", "package payroll\n\nvar unstaged = \"123456782\"\n")
+
+	config := testConfig()
+	config.Staged = true
+	result, err := ScanPath(context.Background(), root, config)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	var tfns []Finding
+	for _, f := range result.Findings {
+		if f.Type == detection.PITypeTFN {
+			tfns = append(tfns, f)
+		}
+	}
+	require.Len(t, tfns, 1)
+	assert.Equal(t, "123456782", tfns[0].Match)
+	assert.Equal(t, filepath.Join(root, "payroll.go"), tfns[0].File)
+	assert.Equal(t, 5, tfns[0].Line, "line numbers refer to the staged file")
+	assert.Nil(t, tfns[0].Commit)
+	assert.Equal(t, 1, result.FilesScanned)
+}
+
+func TestScanStaged_MatchesFullScan(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Citizen", "GIT_AUTHOR_EMAIL=jane@example.com",
+			"GIT_COMMITTER_NAME=Jane Citizen", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	git("init", "-q")
+	writeFile(t, root, "payroll.go", "package payroll\n\n// Dummy placeholder values for the payroll fixtures\nvar (\n)\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add payroll")
+
+	// The context of the added line is in the committed part of the file
+	writeFile(t, root, "payroll.go", "package payroll\n\n// Dummy placeholder values for the payroll fixtures\nvar (\n\temployeeTFN = \"123456782\"\n)\n")
+	git("add", "payroll.go")
+
+	tfns := func(staged bool) []Finding {
+		config := testConfig()
+		config.Staged = staged
+		result, err := ScanPath(context.Background(), root, config)
+		require.NoError(t, err)
+		require.Empty(t, result.Error)
+
+		var found []Finding
+		for _, f := range result.Findings {
+			if f.Type == detection.PITypeTFN {
+				found = append(found, f)
+			}
+		}
+		return found
+	}
+
+	// The comment above the added line marks it as dummy data in both scans
+	assert.Empty(t, tfns(false))
+	assert.Empty(t, tfns(true))
+
+	// Without it, both report the value on the same line with the same context
+	writeFile(t, root, "payroll.go", "package payroll\n\n// Payroll settings\nvar (\n\temployeeTFN = \"123456782\"\n)\n")
+	git("add", "payroll.go")
+
+	staged, full := tfns(true), tfns(false)
+	require.Len(t, staged, 1)
+	require.Len(t, full, 1)
+	assert.Equal(t, 5, staged[0].Line)
+	assert.Equal(t, full[0].Confidence, staged[0].Confidence)
+	assert.Equal(t, full[0].Context, staged[0].Context)
+}
+
+func TestScanStaged_RequiresGitRepository(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+
+	config := testConfig()
+	config.Staged = true
+	_, err := ScanPath(context.Background(), root, config)
+	assert.ErrorContains(t, err, "requires a git repository")
+}
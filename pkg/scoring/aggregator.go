@@ -316,9 +316,10 @@ func (a *ScoreAggregator) GenerateAuditTrail(factors FactorScores, finalScore fl
 // GenerateRegulatoryCompliance creates regulatory compliance information
 func (a *ScoreAggregator) GenerateRegulatoryCompliance(piType detection.PIType, riskLevel RiskLevel) RegulatoryCompliance {
 	compliance := RegulatoryCompliance{
-		APRA:            a.isAPRARelevant(piType),
-		PrivacyAct:      a.isPrivacyActRelevant(piType),
-		RequiredActions: []ComplianceAction{},
+		APRA:                     a.isAPRARelevant(piType),
+		PrivacyAct:               a.isPrivacyActRelevant(piType),
+		HealthcareIdentifiersAct: isHealthcareIdentifier(piType),
+		RequiredActions:          []ComplianceAction{},
 	}
 
 	// Generate required actions based on PI type and risk level
@@ -355,13 +356,20 @@ func (a *ScoreAggregator) isPrivacyActRelevant(piType detection.PIType) bool {
 
 	// Privacy Act applies to most personal information
 	nonPersonalTypes := map[detection.PIType]bool{
-		detection.PITypeIP:  true, // IP addresses may not be personal in all contexts
-		detection.PITypeABN: true, // Business numbers are not personal
+		detection.PITypeIP:   true, // IP addresses may not be personal in all contexts
+		detection.PITypeABN:  true, // Business numbers are not personal
+		detection.PITypeHPIO: true, // Healthcare organisation identifiers are not personal
 	}
 
 	return !nonPersonalTypes[piType]
 }
 
+// isHealthcareIdentifier reports whether the Healthcare Identifiers Act 2010
+// restricts the collection, use and disclosure of this PI type
+func isHealthcareIdentifier(piType detection.PIType) bool {
+	return piType == detection.PITypeIHI || piType == detection.PITypeHPII || piType == detection.PITypeHPIO
+}
+
 // generateComplianceActions generates required compliance actions
 func (a *ScoreAggregator) generateComplianceActions(piType detection.PIType, riskLevel RiskLevel) []ComplianceAction {
 	actions := []ComplianceAction{}
@@ -406,6 +414,17 @@ func (a *ScoreAggregator) generateComplianceActions(piType detection.PIType, ris
 		})
 	}
 
+	// Unauthorised use or disclosure of a healthcare identifier is an offence
+	// whatever the assessed risk
+	if isHealthcareIdentifier(piType) {
+		actions = append(actions, ComplianceAction{
+			Type:        "HEALTHCARE_IDENTIFIERS_ACT",
+			Description: "Confirm the healthcare identifier is held for a purpose authorised by the Healthcare Identifiers Act 2010 and restrict access to it",
+			Priority:    "HIGH",
+			Deadline:    now.Add(48 * time.Hour),
+		})
+	}
+
 	// Privacy Act compliance actions
 	if a.isPrivacyActRelevant(piType) && (riskLevel == RiskLevelCritical || riskLevel == RiskLevelHigh) {
 		actions = append(actions, ComplianceAction{
@@ -437,6 +456,12 @@ func (a *ScoreAggregator) getValidationAlgorithm(piType detection.PIType) string
 		detection.PITypeABN:      "ABN_MODULUS_89",
 		detection.PITypeMedicare: "MEDICARE_CHECKSUM",
 		detection.PITypeBSB:      "BSB_FORMAT",
+		detection.PITypeIHI:      "HI_LUHN",
+		detection.PITypeHPII:     "HI_LUHN",
+		detection.PITypeHPIO:     "HI_LUHN",
+		detection.PITypeCRN:      "CRN_CHECK_LETTER",
+		detection.PITypeDVA:      "DVA_FORMAT",
+		detection.PITypeImmiCard: "IMMICARD_FORMAT",
 	}
 
 	if algo, exists := algorithms[piType]; exists {
@@ -481,6 +506,9 @@ func (a *ScoreAggregator) getRegulatoryComplianceStatus(piType detection.PIType)
 	if a.isPrivacyActRelevant(piType) {
 		status = append(status, "Privacy_Act")
 	}
+	if isHealthcareIdentifier(piType) {
+		status = append(status, "Healthcare_Identifiers_Act")
+	}
 
 	if len(status) == 0 {
 		return "minimal_regulatory_impact"
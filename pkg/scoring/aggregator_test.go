@@ -357,6 +357,40 @@ func TestScoreAggregator_GenerateRegulatoryCompliance(t *testing.T) {
 	}
 }
 
+func TestScoreAggregator_HealthcareIdentifierCompliance(t *testing.T) {
+	aggregator, err := NewScoreAggregator(DefaultAggregatorConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		piType          detection.PIType
+		expectedPrivacy bool
+	}{
+		{detection.PITypeIHI, true},
+		{detection.PITypeHPII, true},
+		{detection.PITypeHPIO, false}, // Identifies an organisation, not a person
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.piType), func(t *testing.T) {
+			// The Act applies whatever the assessed risk
+			compliance := aggregator.GenerateRegulatoryCompliance(tt.piType, RiskLevelLow)
+
+			assert.True(t, compliance.HealthcareIdentifiersAct)
+			assert.False(t, compliance.APRA)
+			assert.Equal(t, tt.expectedPrivacy, compliance.PrivacyAct)
+
+			var actionTypes []string
+			for _, action := range compliance.RequiredActions {
+				actionTypes = append(actionTypes, action.Type)
+			}
+			assert.Contains(t, actionTypes, "HEALTHCARE_IDENTIFIERS_ACT")
+		})
+	}
+
+	compliance := aggregator.GenerateRegulatoryCompliance(detection.PITypeMedicare, RiskLevelHigh)
+	assert.False(t, compliance.HealthcareIdentifiersAct)
+}
+
 func TestScoreAggregator_WeightedCombination(t *testing.T) {
 	config := DefaultAggregatorConfig()
 	config.WeightedCombination = true
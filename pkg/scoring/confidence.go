@@ -175,9 +175,10 @@ type AuditEntry struct {
 
 // RegulatoryCompliance represents Australian regulatory compliance information
 type RegulatoryCompliance struct {
-	APRA            bool               `json:"apra_compliance"`
-	PrivacyAct      bool               `json:"privacy_act_compliance"`
-	RequiredActions []ComplianceAction `json:"required_actions"`
+	APRA                     bool               `json:"apra_compliance"`
+	PrivacyAct               bool               `json:"privacy_act_compliance"`
+	HealthcareIdentifiersAct bool               `json:"healthcare_identifiers_act_compliance"`
+	RequiredActions          []ComplianceAction `json:"required_actions"`
 }
 
 // ComplianceAction represents a required action for regulatory compliance
@@ -339,6 +340,12 @@ func (e *ConfidenceEngine) validateInput(input ScoreInput) error {
 		detection.PITypePassport:      true,
 		detection.PITypeAccount:       true,
 		detection.PITypeIP:            true,
+		detection.PITypeIHI:           true,
+		detection.PITypeHPII:          true,
+		detection.PITypeHPIO:          true,
+		detection.PITypeCRN:           true,
+		detection.PITypeDVA:           true,
+		detection.PITypeImmiCard:      true,
 	}
 
 	if !supportedTypes[input.Finding.Type] {
@@ -125,14 +125,21 @@ func (ec *ExposureCalculator) calculateDataLifetime(input RiskAssessmentInput) i
 
 // determineEncryptionStatus checks if data appears to be encrypted
 func (ec *ExposureCalculator) determineEncryptionStatus(input RiskAssessmentInput) string {
+	// Values found by decoding base64, URL-encoded or hex text were only
+	// encoded, which anyone can reverse
+	if len(input.Finding.EncodingChain) > 0 {
+		return "ENCODED"
+	}
+
 	// Check for encryption indicators in the finding
 	match := strings.ToLower(input.Finding.Match)
 	context := strings.ToLower(input.Finding.Context)
 
-	// Look for encryption patterns
+	// Look for encryption patterns. Base64 is an encoding rather than
+	// encryption, and the values it hides are decoded and scanned.
 	encryptionPatterns := []string{
 		"encrypted", "encrypt", "aes", "rsa", "hash",
-		"bcrypt", "pbkdf2", "sha256", "base64",
+		"bcrypt", "pbkdf2", "sha256",
 	}
 
 	for _, pattern := range encryptionPatterns {
@@ -231,6 +238,7 @@ func (ec *ExposureCalculator) combineExposureFactors(factors ExposureFactors, in
 	// Encryption status affects exposure
 	encryptionMultipliers := map[string]float64{
 		"PLAIN_TEXT":         1.2,
+		"ENCODED":            1.2,
 		"UNKNOWN":            1.0,
 		"POSSIBLY_ENCRYPTED": 0.6,
 		"ENCRYPTED":          0.3,
@@ -50,14 +50,20 @@ func (ic *ImpactCalculator) calculateDataSensitivity(piType detection.PIType) fl
 	sensitivityLevels := map[detection.PIType]float64{
 		detection.PITypeTFN:           1.0,  // Highest - tax file number
 		detection.PITypeMedicare:      0.95, // Very high - health identifier
+		detection.PITypeIHI:           0.95, // Very high - links a person to their health records
 		detection.PITypeCreditCard:    0.9,  // Very high - financial data
 		detection.PITypePassport:      0.9,  // Very high - identity document
+		detection.PITypeCRN:           0.85, // High - links a person to their welfare records
+		detection.PITypeImmiCard:      0.85, // High - identity document of a visa holder
+		detection.PITypeDVA:           0.8,  // High - links a veteran to their service and health records
 		detection.PITypeDriverLicense: 0.85, // High - identity document
 		detection.PITypeABN:           0.6,  // Medium - business identifier
 		detection.PITypeBSB:           0.7,  // High - banking identifier
+		detection.PITypeHPII:          0.7,  // High - identifies a practitioner
 		detection.PITypeAccount:       0.8,  // High - financial account
 		detection.PITypeName:          0.5,  // Medium - personal identifier
 		detection.PITypeAddress:       0.5,  // Medium - personal identifier
+		detection.PITypeHPIO:          0.4,  // Medium-low - identifies a healthcare organisation
 		detection.PITypePhone:         0.4,  // Medium-low - contact info
 		detection.PITypeEmail:         0.3,  // Low - contact info
 		detection.PITypeIP:            0.2,  // Low - technical identifier
@@ -72,6 +78,11 @@ func (ic *ImpactCalculator) calculateDataSensitivity(piType detection.PIType) fl
 
 // estimateRecordCount estimates the number of records exposed
 func (ic *ImpactCalculator) estimateRecordCount(input RiskAssessmentInput) int {
+	// A CSV column of values reported as one finding counts its rows
+	if input.Finding.RecordCount > 0 {
+		return input.Finding.RecordCount
+	}
+
 	baseCount := 1 // At least one record
 
 	// Check for bulk exposure patterns
@@ -100,6 +111,8 @@ func (ic *ImpactCalculator) calculateFinancialImpact(piType detection.PIType, re
 		detection.PITypeAccount:    0.8, // High - account access
 		detection.PITypeTFN:        0.7, // High - tax/identity fraud
 		detection.PITypeMedicare:   0.5, // Medium - healthcare fraud
+		detection.PITypeIHI:        0.5, // Medium - healthcare fraud
+		detection.PITypeCRN:        0.5, // Medium - welfare fraud
 		detection.PITypePassport:   0.6, // Medium-high - identity fraud
 		detection.PITypeABN:        0.3, // Low-medium - business impact
 		detection.PITypeName:       0.2, // Low - requires additional info
@@ -153,8 +166,13 @@ func (ic *ImpactCalculator) calculateRegulatoryImpact(piType detection.PIType, o
 		privacyActTypes := map[detection.PIType]bool{
 			detection.PITypeTFN:           true,
 			detection.PITypeMedicare:      true,
+			detection.PITypeIHI:           true,
+			detection.PITypeHPII:          true,
 			detection.PITypeDriverLicense: true,
 			detection.PITypePassport:      true,
+			detection.PITypeCRN:           true,
+			detection.PITypeDVA:           true,
+			detection.PITypeImmiCard:      true,
 		}
 
 		if privacyActTypes[piType] {
@@ -206,6 +224,7 @@ func (ic *ImpactCalculator) calculateReputationalImpact(input RiskAssessmentInpu
 	sensitiveTypes := map[detection.PIType]bool{
 		detection.PITypeTFN:        true,
 		detection.PITypeMedicare:   true,
+		detection.PITypeIHI:        true,
 		detection.PITypeCreditCard: true,
 		detection.PITypePassport:   true,
 	}
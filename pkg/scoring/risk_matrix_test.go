@@ -245,6 +245,36 @@ func TestRiskMatrix_AssessRisk_Medicare(t *testing.T) {
 	assert.True(t, foundHealthcareMitigation, "Should have healthcare-specific mitigations")
 }
 
+func TestRiskMatrix_HealthcareIdentifiers(t *testing.T) {
+	matrix, err := NewRiskMatrix(DefaultRiskMatrixConfig())
+	require.NoError(t, err)
+
+	sensitivity := make(map[detection.PIType]float64)
+	for _, piType := range []detection.PIType{detection.PITypeIHI, detection.PITypeHPII, detection.PITypeHPIO} {
+		result, err := matrix.AssessRisk(RiskAssessmentInput{
+			Finding: detection.Finding{
+				Type:      piType,
+				Match:     "8003608166690503",
+				File:      "src/patients.go",
+				Validated: true,
+			},
+			ConfidenceScore: 0.9,
+			FileContext: FileContext{
+				FilePath:     "src/patients.go",
+				IsProduction: true,
+			},
+		})
+		require.NoError(t, err)
+		sensitivity[piType] = result.ImpactFactors.DataSensitivity
+	}
+
+	// A patient's identifier is as sensitive as their Medicare number; a
+	// practitioner's less so, and an organisation's least
+	assert.Equal(t, 0.95, sensitivity[detection.PITypeIHI])
+	assert.Greater(t, sensitivity[detection.PITypeIHI], sensitivity[detection.PITypeHPII])
+	assert.Greater(t, sensitivity[detection.PITypeHPII], sensitivity[detection.PITypeHPIO])
+}
+
 func TestRiskMatrix_Mitigations(t *testing.T) {
 	matrix, err := NewRiskMatrix(DefaultRiskMatrixConfig())
 	require.NoError(t, err)
@@ -524,6 +554,67 @@ func TestRiskMatrix_EnvironmentContext(t *testing.T) {
 		"Production environment should have higher risk than test")
 }
 
+func TestRiskMatrix_StructuredRecordCount(t *testing.T) {
+	matrix, err := NewRiskMatrix(DefaultRiskMatrixConfig())
+	require.NoError(t, err)
+
+	single := RiskAssessmentInput{
+		Finding: detection.Finding{
+			Type:      detection.PITypeTFN,
+			Validated: true,
+			Field:     "tfn",
+		},
+		ConfidenceScore: 0.9,
+		FileContext: FileContext{
+			FilePath:     "exports/customers.csv",
+			IsProduction: true,
+		},
+		CoOccurrences: []detection.Finding{{Type: detection.PITypeName}, {Type: detection.PITypeEmail}},
+	}
+
+	column := single
+	column.Finding.RecordCount = 10000
+
+	singleResult, err := matrix.AssessRisk(single)
+	require.NoError(t, err)
+	columnResult, err := matrix.AssessRisk(column)
+	require.NoError(t, err)
+
+	// The record count replaces the estimate from co-occurrences
+	assert.Equal(t, 2, singleResult.ImpactFactors.RecordCount)
+	assert.Equal(t, 10000, columnResult.ImpactFactors.RecordCount)
+	assert.Greater(t, columnResult.ImpactScore, singleResult.ImpactScore)
+}
+
+func TestRiskMatrix_EncodedValues(t *testing.T) {
+	matrix, err := NewRiskMatrix(DefaultRiskMatrixConfig())
+	require.NoError(t, err)
+
+	encoded := RiskAssessmentInput{
+		Finding: detection.Finding{
+			Type:          detection.PITypeTFN,
+			Match:         "123456782",
+			Context:       "base64 customer record",
+			Validated:     true,
+			EncodingChain: []string{"base64"},
+		},
+		ConfidenceScore: 0.9,
+	}
+	encrypted := encoded
+	encrypted.Finding.Context = "aes encrypted customer record"
+	encrypted.Finding.EncodingChain = nil
+
+	encodedResult, err := matrix.AssessRisk(encoded)
+	require.NoError(t, err)
+	encryptedResult, err := matrix.AssessRisk(encrypted)
+	require.NoError(t, err)
+
+	// Encoding is reversible, so encoded values are as exposed as plain text
+	assert.Equal(t, "ENCODED", encodedResult.ExposureFactors.EncryptionStatus)
+	assert.Equal(t, "ENCRYPTED", encryptedResult.ExposureFactors.EncryptionStatus)
+	assert.Greater(t, encodedResult.ExposureScore, encryptedResult.ExposureScore)
+}
+
 func TestRiskMatrix_EdgeCases(t *testing.T) {
 	matrix, err := NewRiskMatrix(DefaultRiskMatrixConfig())
 	require.NoError(t, err)
@@ -0,0 +1,171 @@
+package structured
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// fieldBoost is added to the confidence of findings in a field whose name
+// says it holds that type of PI
+const fieldBoost = 0.2
+
+// minColumnRecords is the number of findings of one type in a CSV column at
+// which they are reported as a single finding with a record count
+const minColumnRecords = 10
+
+// fieldKeywords are the words in a normalized field name, such as
+// "medicareno" for medicare_no, marking it as holding a type of PI
+var fieldKeywords = map[detection.PIType][]string{
+	detection.PITypeTFN:           {"tfn", "taxfile"},
+	detection.PITypeMedicare:      {"medicare"},
+	detection.PITypeABN:           {"abn", "businessnumber"},
+	detection.PITypeACN:           {"acn", "companynumber"},
+	detection.PITypeBSB:           {"bsb"},
+	detection.PITypeEmail:         {"email", "mail"},
+	detection.PITypePhone:         {"phone", "mobile", "telephone"},
+	detection.PITypeName:          {"firstname", "lastname", "fullname", "surname", "givenname"},
+	detection.PITypeAddress:       {"address", "street", "postcode"},
+	detection.PITypeCreditCard:    {"card"},
+	detection.PITypeDriverLicense: {"licence", "license"},
+	detection.PITypePassport:      {"passport"},
+	detection.PITypeAccount:       {"account", "acct"},
+	detection.PITypeIP:            {"ipaddress", "ipaddr"},
+	detection.PITypeIHI:           {"ihi"},
+	detection.PITypeHPII:          {"hpii"},
+	detection.PITypeHPIO:          {"hpio"},
+	detection.PITypeCRN:           {"crn", "customerreference"},
+	detection.PITypeDVA:           {"dva", "veteranfile"},
+	detection.PITypeImmiCard:      {"immicard"},
+}
+
+// Annotate sets the field of findings in a CSV, TSV, JSON or YAML file and
+// raises the confidence of those in a field named for their type. Findings
+// of one type filling a CSV column are reported once, on the first row, with
+// the number of rows as the record count. Findings in other files, or in
+// files that cannot be parsed, are returned unchanged.
+func Annotate(name string, content []byte, findings []detection.Finding) []detection.Finding {
+	format := FormatOf(name)
+	if format == "" || len(findings) == 0 {
+		return findings
+	}
+	data, err := Parse(format, content)
+	if err != nil {
+		return findings
+	}
+
+	fields := make([]Field, len(findings))
+	found := make([]bool, len(findings))
+	for i := range findings {
+		f := &findings[i]
+		field, ok := data.Lookup(f.Line, f.Column)
+		if !ok {
+			continue
+		}
+		fields[i], found[i] = field, true
+
+		f.Field = field.Path
+		if f.Field == "" {
+			f.Field = field.Header
+		}
+		if namesType(field.Name(), f.Type) {
+			f.Confidence = min(f.Confidence+fieldBoost, 1)
+		}
+	}
+
+	if format != FormatCSV && format != FormatTSV {
+		return findings
+	}
+	return collapseColumns(findings, fields, found)
+}
+
+// namesType reports whether a field name contains a keyword for the PI type
+func namesType(name string, piType detection.PIType) bool {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+	if normalized == "" {
+		return false
+	}
+	for _, keyword := range fieldKeywords[piType] {
+		if strings.Contains(normalized, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// stronger reports whether a is a stronger finding than b: validated first,
+// then by risk level and confidence
+func stronger(a, b detection.Finding) bool {
+	if a.Validated != b.Validated {
+		return a.Validated
+	}
+	if a.RiskLevel.Severity() != b.RiskLevel.Severity() {
+		return a.RiskLevel.Severity() > b.RiskLevel.Severity()
+	}
+	return a.Confidence > b.Confidence
+}
+
+// columnKey groups the findings of one type in one CSV column
+type columnKey struct {
+	column int
+	piType detection.PIType
+}
+
+// collapseColumns replaces the findings of each type in a CSV column with
+// the one on the first row when there are at least minColumnRecords of them.
+// The kept finding takes its validation, risk level and confidence from the
+// strongest row. Suppressed findings are kept as they are.
+func collapseColumns(findings []detection.Finding, fields []Field, found []bool) []detection.Finding {
+	groups := make(map[columnKey][]int)
+	for i, f := range findings {
+		if found[i] && f.Suppression == nil {
+			key := columnKey{column: fields[i].Column, piType: f.Type}
+			groups[key] = append(groups[key], i)
+		}
+	}
+
+	drop := make(map[int]bool)
+	for _, indexes := range groups {
+		// Detectors can report the same value twice, so rows are counted
+		rows := make(map[int]bool)
+		for _, i := range indexes {
+			rows[fields[i].Row] = true
+		}
+		if len(rows) < minColumnRecords {
+			continue
+		}
+
+		sort.Slice(indexes, func(a, b int) bool { return fields[indexes[a]].Row < fields[indexes[b]].Row })
+		first := &findings[indexes[0]]
+		strongest := findings[indexes[0]]
+		for _, i := range indexes[1:] {
+			drop[i] = true
+			if stronger(findings[i], strongest) {
+				strongest = findings[i]
+			}
+		}
+		first.Validated = strongest.Validated
+		first.ValidationError = strongest.ValidationError
+		first.RiskLevel = strongest.RiskLevel
+		first.Confidence = strongest.Confidence
+		first.RecordCount = len(rows)
+	}
+	if len(drop) == 0 {
+		return findings
+	}
+
+	kept := findings[:0]
+	for i, f := range findings {
+		if !drop[i] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,437 @@
+package structured
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a structured data format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatTSV  Format = "tsv"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// FormatOf returns the structured data format of a file by its extension, or
+// "" if it is not structured data
+func FormatOf(name string) Format {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".tsv":
+		return FormatTSV
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return ""
+}
+
+// Field is the place of a value in structured data
+type Field struct {
+	Path   string // JSON or YAML key path, such as $.customers[3].tfn
+	Column int    // CSV column, from 0
+	Header string // CSV column header, if the file has a header row
+	Row    int    // CSV data row, from 1 after any header
+}
+
+// Name returns the CSV column header or the last key of the key path: the
+// name the data's author gave the value
+func (f Field) Name() string {
+	if f.Path == "" {
+		return f.Header
+	}
+
+	p := f.Path
+	for strings.HasSuffix(p, "]") {
+		i := strings.LastIndex(p, "[")
+		if i < 0 {
+			break
+		}
+		inner := p[i+1 : len(p)-1]
+		if _, err := strconv.Atoi(inner); err != nil {
+			return strings.ReplaceAll(strings.Trim(inner, "'"), "\\'", "'")
+		}
+		p = p[:i]
+	}
+	if p == "$" {
+		return ""
+	}
+	return p[strings.LastIndex(p, ".")+1:]
+}
+
+// Data maps positions in structured data to the fields holding them
+type Data struct {
+	Format  Format
+	Headers []string // CSV column headers; nil when the first row is data
+	Rows    int      // CSV data rows
+
+	spans []span // Sorted by start
+	lines []int  // Offset of the start of each line
+	size  int
+}
+
+// span is the extent of one value in the content
+type span struct {
+	start, end int
+	field      Field
+}
+
+// Parse reads the fields of CSV, TSV, JSON or YAML content. Content that
+// cannot be parsed in full yields the fields read before the error, and an
+// error only if there are none.
+func Parse(format Format, content []byte) (*Data, error) {
+	d := &Data{Format: format, lines: lineStarts(content), size: len(content)}
+
+	var err error
+	switch format {
+	case FormatCSV:
+		err = d.parseCSV(content, ',')
+	case FormatTSV:
+		err = d.parseCSV(content, '\t')
+	case FormatJSON:
+		err = d.parseJSON(content)
+	case FormatYAML:
+		err = d.parseYAML(content)
+	default:
+		return nil, fmt.Errorf("unsupported structured data format: %q", format)
+	}
+	if err != nil && len(d.spans) == 0 {
+		return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+	}
+
+	sort.SliceStable(d.spans, func(i, j int) bool { return d.spans[i].start < d.spans[j].start })
+	return d, nil
+}
+
+// Sniff parses content of unknown format, trying JSON for content that
+// starts like it, then CSV and TSV with a header row and at least two
+// consistent columns, then YAML holding a mapping or sequence. It returns nil
+// if the content is none of these.
+func Sniff(content []byte) *Data {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		if json.Valid(trimmed) {
+			d, _ := Parse(FormatJSON, content)
+			return d
+		}
+	}
+
+	for _, format := range []Format{FormatTSV, FormatCSV} {
+		if d, err := Parse(format, content); err == nil && d.Headers != nil && d.Rows > 0 && d.consistent() {
+			return d
+		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err == nil && len(root.Content) > 0 {
+		if kind := root.Content[0].Kind; kind == yaml.MappingNode || kind == yaml.SequenceNode {
+			d, _ := Parse(FormatYAML, content)
+			return d
+		}
+	}
+	return nil
+}
+
+// Lookup returns the field holding the value at a 1-based line and byte
+// column, as reported on findings
+func (d *Data) Lookup(line, column int) (Field, bool) {
+	if line < 1 || line > len(d.lines) {
+		return Field{}, false
+	}
+	return d.LookupOffset(d.lines[line-1] + column - 1)
+}
+
+// LookupOffset returns the field holding the value at a byte offset
+func (d *Data) LookupOffset(offset int) (Field, bool) {
+	i := sort.Search(len(d.spans), func(i int) bool { return d.spans[i].start > offset }) - 1
+	if i < 0 || offset >= d.spans[i].end {
+		return Field{}, false
+	}
+	return d.spans[i].field, true
+}
+
+// lineStarts returns the offset of the start of each line
+func lineStarts(content []byte) []int {
+	starts := []int{0}
+	for i, b := range content {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// offset converts a 1-based line and column to a byte offset
+func (d *Data) offset(line, column int) int {
+	if line < 1 || line > len(d.lines) {
+		return -1
+	}
+	return d.lines[line-1] + column - 1
+}
+
+// parseCSV records each cell's extent. A cell runs from its first byte to
+// the delimiter or end of line ending it, so matches inside quoted cells are
+// found too.
+func (d *Data) parseCSV(content []byte, delimiter rune) error {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var previous []span // Cells of the previous record, ended by this one
+	for record := 0; ; record++ {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			d.close(previous, d.size)
+			return err
+		}
+
+		if record == 0 && isHeader(fields) {
+			d.Headers = append([]string(nil), fields...)
+			continue
+		}
+		d.Rows++
+
+		cells := make([]span, len(fields))
+		for i := range fields {
+			line, column := r.FieldPos(i)
+			cells[i].start = d.offset(line, column)
+			cells[i].field = Field{Column: i, Row: d.Rows}
+			if i < len(d.Headers) {
+				cells[i].field.Header = d.Headers[i]
+			}
+		}
+
+		// Each cell ends where the next begins, or at the end of its record
+		d.close(previous, cells[0].start)
+		for i := 0; i+1 < len(cells); i++ {
+			cells[i].end = cells[i+1].start
+		}
+		d.spans = append(d.spans, cells[:len(cells)-1]...)
+		previous = cells[len(cells)-1:]
+	}
+
+	d.close(previous, d.size)
+	return nil
+}
+
+// close ends the last cell of a record at the start of the next record or
+// the end of the content
+func (d *Data) close(cells []span, end int) {
+	for _, cell := range cells {
+		cell.end = end
+		d.spans = append(d.spans, cell)
+	}
+}
+
+// consistent reports whether every row has as many columns as the header,
+// and there are at least two
+func (d *Data) consistent() bool {
+	if len(d.Headers) < 2 {
+		return false
+	}
+	rows := make(map[int]int)
+	for _, s := range d.spans {
+		rows[s.field.Row]++
+	}
+	for _, n := range rows {
+		if n != len(d.Headers) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHeader reports whether the first record of a CSV file names its columns:
+// every cell is mostly letters, as column names are and PI values are not
+func isHeader(fields []string) bool {
+	for _, field := range fields {
+		letters, digits := 0, 0
+		for _, r := range field {
+			switch {
+			case unicode.IsLetter(r):
+				letters++
+			case unicode.IsDigit(r):
+				digits++
+			}
+		}
+		if letters == 0 || digits > letters || strings.Contains(field, "@") {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// pathStep is one level of a JSON or YAML value's key path: a key in an
+// object or mapping, or an index in an array or sequence
+type pathStep struct {
+	key   string
+	index int
+	array bool
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// formatPath renders a key path, quoting keys that are not identifiers
+func formatPath(steps []pathStep) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, step := range steps {
+		switch {
+		case step.array:
+			b.WriteString("[" + strconv.Itoa(step.index) + "]")
+		case identifier.MatchString(step.key):
+			b.WriteString("." + step.key)
+		default:
+			b.WriteString("['" + strings.ReplaceAll(step.key, "'", "\\'") + "']")
+		}
+	}
+	return b.String()
+}
+
+// parseJSON records the extent of each string, number and literal value.
+// Concatenated top-level values, as in JSON Lines, are each read from $.
+func (d *Data) parseJSON(content []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+
+	var steps []pathStep // One per open object or array
+	expectKey := false
+	for {
+		before := int(dec.InputOffset())
+		token, err := dec.Token()
+		if err == io.EOF && len(steps) > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			steps = steps[:len(steps)-1]
+			expectKey = len(steps) > 0 && !steps[len(steps)-1].array
+			continue
+		}
+
+		if expectKey {
+			steps[len(steps)-1].key, _ = token.(string)
+			expectKey = false
+			continue
+		}
+		if len(steps) > 0 && steps[len(steps)-1].array {
+			steps[len(steps)-1].index++
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			steps = append(steps, pathStep{array: delim == '[', index: -1})
+			expectKey = delim == '{'
+			continue
+		}
+
+		d.spans = append(d.spans, span{
+			start: before + valueStart(content[before:]),
+			end:   int(dec.InputOffset()),
+			field: Field{Path: formatPath(steps)},
+		})
+		expectKey = len(steps) > 0 && !steps[len(steps)-1].array
+	}
+}
+
+// valueStart skips the whitespace and separators the decoder consumes before
+// a value
+func valueStart(data []byte) int {
+	for i, b := range data {
+		switch b {
+		case ' ', '\t', '\n', '\r', ',', ':':
+		default:
+			return i
+		}
+	}
+	return len(data)
+}
+
+// parseYAML records the extent of each scalar value in every document
+func (d *Data) parseYAML(content []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var root yaml.Node
+		err := dec.Decode(&root)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		d.walkYAML(&root, nil)
+	}
+}
+
+func (d *Data) walkYAML(node *yaml.Node, steps []pathStep) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			d.walkYAML(child, steps)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			d.walkYAML(node.Content[i+1], append(steps[:len(steps):len(steps)], pathStep{key: node.Content[i].Value}))
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			d.walkYAML(child, append(steps[:len(steps):len(steps)], pathStep{index: i, array: true}))
+		}
+	case yaml.AliasNode:
+		// The anchored value is recorded where it is defined
+	case yaml.ScalarNode:
+		d.addScalar(node, formatPath(steps))
+	}
+}
+
+// addScalar records a scalar's extent: from its start to the end of its
+// line, or for block scalars to the end of the indented lines that follow
+// the indicator
+func (d *Data) addScalar(node *yaml.Node, path string) {
+	start := d.offset(node.Line, node.Column)
+	if start < 0 {
+		return
+	}
+
+	// Lines after the first: every line of a block scalar, and the line
+	// breaks kept in other multi-line scalars
+	extra := strings.Count(node.Value, "\n")
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		extra = strings.Count(strings.TrimRight(node.Value, "\n"), "\n") + 1
+	}
+
+	end := d.size
+	if next := node.Line + extra; next < len(d.lines) {
+		end = d.lines[next]
+	}
+	d.spans = append(d.spans, span{start: start, end: end, field: Field{Path: path}})
+}
@@ -0,0 +1,294 @@
+package structured
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldAt looks up the field holding the first occurrence of value
+func fieldAt(t *testing.T, d *Data, content, value string) Field {
+	t.Helper()
+	offset := strings.Index(content, value)
+	require.GreaterOrEqual(t, offset, 0, "value %q not in content", value)
+	field, ok := d.LookupOffset(offset)
+	require.True(t, ok, "no field holds %q", value)
+	return field
+}
+
+func TestParse_CSV(t *testing.T) {
+	content := "name,tfn,notes\n" +
+		"Jane Citizen,123 456 782,\"prefers email, jane@example.com\"\n" +
+		"John Smith,876 543 210,\"two\nlines\"\n"
+
+	d, err := Parse(FormatCSV, []byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "tfn", "notes"}, d.Headers)
+	assert.Equal(t, 2, d.Rows)
+
+	assert.Equal(t, Field{Column: 1, Header: "tfn", Row: 1}, fieldAt(t, d, content, "123 456 782"))
+	assert.Equal(t, Field{Column: 2, Header: "notes", Row: 1}, fieldAt(t, d, content, "jane@example.com"))
+	assert.Equal(t, Field{Column: 0, Header: "name", Row: 2}, fieldAt(t, d, content, "John"))
+	assert.Equal(t, Field{Column: 2, Header: "notes", Row: 2}, fieldAt(t, d, content, "lines"))
+
+	// Line 2, column 14 is the start of the first TFN
+	field, ok := d.Lookup(2, 14)
+	require.True(t, ok)
+	assert.Equal(t, "tfn", field.Name())
+
+	// The header row holds no values
+	_, ok = d.Lookup(1, 1)
+	assert.False(t, ok)
+}
+
+func TestParse_CSVWithoutHeader(t *testing.T) {
+	content := "jane@example.com\t123456782\njohn@example.com\t876543210\n"
+
+	d, err := Parse(FormatTSV, []byte(content))
+	require.NoError(t, err)
+	assert.Nil(t, d.Headers)
+	assert.Equal(t, 2, d.Rows)
+	assert.Equal(t, Field{Column: 1, Row: 2}, fieldAt(t, d, content, "876543210"))
+}
+
+func TestParse_JSON(t *testing.T) {
+	content := `{
+  "customers": [
+    {"name": "Jane", "tfn": "123456782"},
+    {"name": "John", "contact": {"email": "john@example.com"}, "tfn": 876543210}
+  ],
+  "first name": "Ann",
+  "ids": [[1, 2], [3]]
+}`
+
+	d, err := Parse(FormatJSON, []byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, "$.customers[0].tfn", fieldAt(t, d, content, "123456782").Path)
+	assert.Equal(t, "$.customers[1].contact.email", fieldAt(t, d, content, "john@example.com").Path)
+	assert.Equal(t, "$.customers[1].tfn", fieldAt(t, d, content, "876543210").Path)
+	assert.Equal(t, "$['first name']", fieldAt(t, d, content, "Ann").Path)
+	assert.Equal(t, "$.ids[1][0]", fieldAt(t, d, content, "3]").Path)
+
+	// Keys are not values
+	_, ok := d.LookupOffset(strings.Index(content, "customers"))
+	assert.False(t, ok)
+}
+
+func TestParse_JSONLines(t *testing.T) {
+	content := "{\"tfn\": \"123456782\"}\n{\"tfn\": \"876543210\"}\n"
+
+	d, err := Parse(FormatJSON, []byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, "$.tfn", fieldAt(t, d, content, "876543210").Path)
+}
+
+func TestParse_YAML(t *testing.T) {
+	content := `customers:
+  - name: Jane
+    tfn: 123 456 782
+  - name: John
+    notes: |
+      Call on 0412 345 678
+      after hours
+    tfn: "876543210"
+---
+admin:
+  email: admin@example.com
+`
+
+	d, err := Parse(FormatYAML, []byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, "$.customers[0].tfn", fieldAt(t, d, content, "456 782").Path)
+	assert.Equal(t, "$.customers[1].notes", fieldAt(t, d, content, "0412 345 678").Path)
+	assert.Equal(t, "$.customers[1].notes", fieldAt(t, d, content, "after hours").Path)
+	assert.Equal(t, "$.customers[1].tfn", fieldAt(t, d, content, "876543210").Path)
+	assert.Equal(t, "$.admin.email", fieldAt(t, d, content, "admin@example.com").Path)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse(FormatJSON, []byte(`{"tfn": `))
+	assert.Error(t, err)
+
+	_, err = Parse("xml", []byte("<tfn/>"))
+	assert.Error(t, err)
+
+	// Values read before a syntax error are kept
+	content := `{"tfn": "123456782", "email": }`
+	d, err := Parse(FormatJSON, []byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, "$.tfn", fieldAt(t, d, content, "123456782").Path)
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		content string
+		format  Format
+	}{
+		{`[{"tfn": "123456782"}]`, FormatJSON},
+		{"name,tfn\nJane,123456782\n", FormatCSV},
+		{"name\ttfn\nJane\t123456782\n", FormatTSV},
+		{"tfn: 123456782\n", FormatYAML},
+		{"The TFN is 123 456 782", ""},
+		{"name,tfn\nJane,123456782,extra\n", ""},
+		{"{not json", ""},
+		{"", ""},
+	}
+
+	for _, tc := range tests {
+		d := Sniff([]byte(tc.content))
+		if tc.format == "" {
+			assert.Nil(t, d, tc.content)
+			continue
+		}
+		require.NotNil(t, d, tc.content)
+		assert.Equal(t, tc.format, d.Format, tc.content)
+	}
+}
+
+func TestField_Name(t *testing.T) {
+	tests := map[string]Field{
+		"tfn":        {Path: "$.customers[3].tfn"},
+		"customers":  {Path: "$.customers[3]"},
+		"first name": {Path: "$.people[0]['first name']"},
+		"medicare":   {Column: 2, Header: "medicare"},
+		"":           {Path: "$"},
+	}
+	for expected, field := range tests {
+		assert.Equal(t, expected, field.Name(), field.Path)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	assert.Equal(t, FormatCSV, FormatOf("exports/Customers.CSV"))
+	assert.Equal(t, FormatTSV, FormatOf("payroll.tsv"))
+	assert.Equal(t, FormatJSON, FormatOf("data.json"))
+	assert.Equal(t, FormatYAML, FormatOf("config.yml"))
+	assert.Equal(t, Format(""), FormatOf("main.go"))
+}
+
+// finding makes a finding for the first occurrence of match in content
+func finding(content string, piType detection.PIType, match string) detection.Finding {
+	offset := strings.Index(content, match)
+	line := strings.Count(content[:offset], "\n") + 1
+	column := offset - strings.LastIndex(content[:offset], "\n")
+	return detection.Finding{Type: piType, Match: match, Line: line, Column: column, Confidence: 0.6}
+}
+
+func TestAnnotate_Fields(t *testing.T) {
+	content := `{"customer": {"medicare_no": "2123456701", "reference": "2123456701x", "email": "jane@example.com"}}`
+	findings := []detection.Finding{
+		finding(content, detection.PITypeMedicare, "2123456701"),
+		finding(content, detection.PITypeEmail, "jane@example.com"),
+		{Type: detection.PITypeTFN, Match: "123456782", Line: 9, Column: 1, Confidence: 0.6},
+	}
+
+	annotated := Annotate("customers.json", []byte(content), findings)
+	require.Len(t, annotated, 3)
+
+	assert.Equal(t, "$.customer.medicare_no", annotated[0].Field)
+	assert.InDelta(t, 0.8, annotated[0].Confidence, 0.001)
+	assert.Equal(t, "$.customer.email", annotated[1].Field)
+	assert.InDelta(t, 0.8, annotated[1].Confidence, 0.001)
+
+	// A finding outside any value is left as it is
+	assert.Empty(t, annotated[2].Field)
+	assert.InDelta(t, 0.6, annotated[2].Confidence, 0.001)
+
+	// Field names unrelated to the type do not raise confidence
+	content = "reference,notes\n123456782,none\n"
+	annotated = Annotate("orders.csv", []byte(content), []detection.Finding{finding(content, detection.PITypeTFN, "123456782")})
+	assert.Equal(t, "reference", annotated[0].Field)
+	assert.InDelta(t, 0.6, annotated[0].Confidence, 0.001)
+
+	// Other files are not parsed
+	findings = []detection.Finding{{Type: detection.PITypeTFN, Line: 1, Column: 5, Confidence: 0.6}}
+	assert.Equal(t, findings, Annotate("main.go", []byte("tfn=123456782"), findings))
+}
+
+func TestAnnotate_CollapsesColumns(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("name,tfn,email\n")
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&b, "Person %d,12345678%d,person%d@example.com\n", i, i%10, i)
+	}
+	content := b.String()
+
+	var findings []detection.Finding
+	for i := 0; i < 12; i++ {
+		findings = append(findings, finding(content, detection.PITypeTFN, fmt.Sprintf("Person %d,12345678%d", i, i%10)))
+		findings[len(findings)-1].Column += len(fmt.Sprintf("Person %d,", i))
+	}
+	// The same value reported by a second detector is one record
+	findings = append(findings, findings[3])
+	findings[5].Validated = true
+	for i := 0; i < 3; i++ {
+		findings = append(findings, finding(content, detection.PITypeEmail, fmt.Sprintf("person%d@example.com", i)))
+	}
+	suppressed := finding(content, detection.PITypeEmail, "person5@example.com")
+	suppressed.Suppression = &detection.Suppression{}
+	findings = append(findings, suppressed)
+
+	annotated := Annotate("export.csv", []byte(content), findings)
+
+	var tfns, emails []detection.Finding
+	for _, f := range annotated {
+		if f.Type == detection.PITypeTFN {
+			tfns = append(tfns, f)
+		} else {
+			emails = append(emails, f)
+		}
+	}
+
+	require.Len(t, tfns, 1)
+	assert.Equal(t, 2, tfns[0].Line)
+	assert.Equal(t, "tfn", tfns[0].Field)
+	assert.Equal(t, 12, tfns[0].RecordCount)
+	assert.True(t, tfns[0].Validated, "a validated row validates the column")
+	assert.InDelta(t, 0.8, tfns[0].Confidence, 0.001)
+
+	// Below the threshold every finding is kept
+	require.Len(t, emails, 4)
+	for _, f := range emails {
+		assert.Equal(t, "email", f.Field)
+		assert.Zero(t, f.RecordCount)
+	}
+}
+
+func TestAnnotate_CollapsedColumnTakesStrongestRow(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("name,tfn\n")
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&b, "Person %d,12345678%d\n", i, i%10)
+	}
+	content := b.String()
+
+	var findings []detection.Finding
+	for i := 0; i < 12; i++ {
+		f := finding(content, detection.PITypeTFN, fmt.Sprintf("Person %d,12345678%d", i, i%10))
+		f.Column += len(fmt.Sprintf("Person %d,", i))
+		f.RiskLevel = detection.RiskLevelLow
+		f.ValidationError = "invalid checksum"
+		findings = append(findings, f)
+	}
+	// Only a later row validates
+	findings[7].Validated = true
+	findings[7].ValidationError = ""
+	findings[7].RiskLevel = detection.RiskLevelCritical
+	findings[7].Confidence = 0.7
+
+	annotated := Annotate("export.csv", []byte(content), findings)
+
+	require.Len(t, annotated, 1)
+	assert.Equal(t, 2, annotated[0].Line, "the column is reported on its first row")
+	assert.Equal(t, 12, annotated[0].RecordCount)
+	assert.True(t, annotated[0].Validated)
+	assert.Empty(t, annotated[0].ValidationError)
+	assert.Equal(t, detection.RiskLevelCritical, annotated[0].RiskLevel)
+	assert.InDelta(t, 0.9, annotated[0].Confidence, 0.001)
+}
@@ -24,6 +24,8 @@ func GenerateComprehensiveTestDataset() *BenchmarkDataset {
 	dataset.TruePositives = append(dataset.TruePositives, generateBSBTestCases(generator)...)
 	dataset.TruePositives = append(dataset.TruePositives, generateACNTestCases(generator)...)
 	dataset.TruePositives = append(dataset.TruePositives, generateDriverLicenseTestCases(generator)...)
+	dataset.TruePositives = append(dataset.TruePositives, generateHealthcareIdentifierTestCases(generator)...)
+	dataset.TruePositives = append(dataset.TruePositives, generateGovernmentServicesTestCases(generator)...)
 	dataset.TruePositives = append(dataset.TruePositives, generateMultiPITestCases(generator)...)
 
 	dataset.TrueNegatives = append(dataset.TrueNegatives, generateFalsePositiveCases(generator)...)
@@ -362,6 +364,103 @@ func generateACNTestCases(g *TestDataGenerator) []TestCase {
 	return cases
 }
 
+// identifierCase describes the production test cases of one identifier type
+type identifierCase struct {
+	piType    detection.PIType
+	generate  func() string
+	code      string              // Go assignment of the value, with %s in its place
+	filename  string              // File holding the assignment
+	holder    string              // What the value is part of, for the rationale
+	format    func(string) string // Printed form, also tested in JSON (nil = none)
+	formatted string              // How the printed form looks, for the rationale
+}
+
+// generateIdentifierTestCases generates count valid values of each identifier
+// in production code, and in JSON in their printed form when they have one
+func generateIdentifierTestCases(g *TestDataGenerator, identifiers []identifierCase, count int) []TestCase {
+	cases := []TestCase{}
+	id := 0
+
+	for _, ic := range identifiers {
+		name := strings.ToLower(strings.ReplaceAll(string(ic.piType), "_", ""))
+
+		for i := 0; i < count; i++ {
+			value := ic.generate()
+
+			cases = append(cases, TestCase{
+				ID:         fmt.Sprintf("%s-prod-%03d", name, id),
+				Code:       fmt.Sprintf(ic.code, value),
+				Language:   "go",
+				PIType:     ic.piType,
+				IsActualPI: true,
+				Context:    "production",
+				Rationale:  fmt.Sprintf("Valid %s in %s", ic.piType, ic.holder),
+				Filename:   ic.filename,
+			})
+			id++
+
+			if ic.format == nil {
+				continue
+			}
+			cases = append(cases, TestCase{
+				ID:         fmt.Sprintf("%s-prod-%03d", name, id),
+				Code:       fmt.Sprintf(`"%s": "%s"`, g.getVarName(ic.piType), ic.format(value)),
+				Language:   "json",
+				PIType:     ic.piType,
+				IsActualPI: true,
+				Context:    "production",
+				Rationale:  fmt.Sprintf("Valid %s in JSON %s", ic.piType, ic.formatted),
+				Filename:   strings.TrimSuffix(ic.filename, ".go") + ".json",
+			})
+			id++
+		}
+	}
+
+	return cases
+}
+
+// generateHealthcareIdentifierTestCases generates IHI, HPI-I and HPI-O test cases
+func generateHealthcareIdentifierTestCases(g *TestDataGenerator) []TestCase {
+	identifiers := []identifierCase{
+		{piType: detection.PITypeIHI, generate: g.GenerateValidIHI, code: `patient.IHI = "%s"`, filename: "patient.go", holder: "patient record"},
+		{piType: detection.PITypeHPII, generate: g.GenerateValidHPII, code: `prescriber.HPII = "%s"`, filename: "prescription.go", holder: "prescriber details"},
+		{piType: detection.PITypeHPIO, generate: g.GenerateValidHPIO, code: `facility.HPIO = "%s"`, filename: "facility.go", holder: "facility details"},
+	}
+	for i := range identifiers {
+		// Printed in groups of four
+		identifiers[i].format = g.FormatHealthcareIdentifier
+		identifiers[i].formatted = "in groups of four"
+	}
+
+	return generateIdentifierTestCases(g, identifiers, 3)
+}
+
+// generateGovernmentServicesTestCases generates Centrelink CRN, DVA file
+// number and ImmiCard test cases
+func generateGovernmentServicesTestCases(g *TestDataGenerator) []TestCase {
+	cases := generateIdentifierTestCases(g, []identifierCase{
+		{piType: detection.PITypeCRN, generate: g.GenerateValidCRN, code: `customer.CRN = "%s"`, filename: "customer.go", holder: "welfare customer record"},
+		{piType: detection.PITypeDVA, generate: g.GenerateValidDVAFileNumber, code: `veteran.DVAFileNumber = "%s"`, filename: "veteran.go", holder: "veteran record"},
+		{piType: detection.PITypeImmiCard, generate: g.GenerateValidImmiCard, code: `applicant.ImmiCardNumber = "%s"`, filename: "applicant.go", holder: "visa applicant record"},
+	}, 5)
+
+	// CRNs with the wrong check letter
+	for i := 0; i < 3; i++ {
+		cases = append(cases, TestCase{
+			ID:         fmt.Sprintf("crn-invalid-%03d", i),
+			Code:       fmt.Sprintf(`crn := "%s"`, g.GenerateInvalidCRN()),
+			Language:   "go",
+			PIType:     detection.PITypeCRN,
+			IsActualPI: false,
+			Context:    "production",
+			Rationale:  "Invalid CRN (wrong check letter)",
+			Filename:   "validate.go",
+		})
+	}
+
+	return cases
+}
+
 // generateDriverLicenseTestCases generates driver license test cases for all states
 func generateDriverLicenseTestCases(g *TestDataGenerator) []TestCase {
 	cases := []TestCase{}
@@ -21,6 +21,14 @@ func NewTestDataGenerator() *TestDataGenerator {
 	}
 }
 
+// NewTestDataGeneratorWithSeed creates a test data generator that generates
+// the same data on every run
+func NewTestDataGeneratorWithSeed(seed int64) *TestDataGenerator {
+	return &TestDataGenerator{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
 // GenerateValidTFN generates a valid TFN using the correct mod 11 algorithm
 // Weights: [1, 4, 3, 7, 5, 8, 6, 9, 10] - official ATO algorithm
 func (g *TestDataGenerator) GenerateValidTFN() string {
@@ -201,6 +209,126 @@ func (g *TestDataGenerator) GenerateValidACN() string {
 	return acn
 }
 
+// GenerateValidIHI generates a valid Individual Healthcare Identifier
+func (g *TestDataGenerator) GenerateValidIHI() string {
+	return g.generateHealthcareIdentifier("800360")
+}
+
+// GenerateValidHPII generates a valid Healthcare Provider Identifier for an
+// individual practitioner
+func (g *TestDataGenerator) GenerateValidHPII() string {
+	return g.generateHealthcareIdentifier("800361")
+}
+
+// GenerateValidHPIO generates a valid Healthcare Provider Identifier for an
+// organisation
+func (g *TestDataGenerator) GenerateValidHPIO() string {
+	return g.generateHealthcareIdentifier("800362")
+}
+
+// generateHealthcareIdentifier generates a 16 digit healthcare identifier
+// starting with prefix, ending in the Luhn check digit of the others
+func (g *TestDataGenerator) generateHealthcareIdentifier(prefix string) string {
+	digits := make([]int, 16)
+	for i := range prefix {
+		digits[i] = int(prefix[i] - '0')
+	}
+	for i := len(prefix); i < 15; i++ {
+		digits[i] = g.rand.Intn(10)
+	}
+
+	// Double every second digit from the right, starting left of the check digit
+	sum := 0
+	for i := 0; i < 15; i++ {
+		d := digits[14-i]
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	digits[15] = (10 - sum%10) % 10
+
+	id := ""
+	for _, d := range digits {
+		id += fmt.Sprintf("%d", d)
+	}
+
+	return id
+}
+
+// FormatHealthcareIdentifier formats a healthcare identifier in groups of four
+// digits, as printed on cards and letters
+func (g *TestDataGenerator) FormatHealthcareIdentifier(id string) string {
+	if len(id) != 16 {
+		return id
+	}
+	return id[:4] + " " + id[4:8] + " " + id[8:12] + " " + id[12:]
+}
+
+// GenerateValidCRN generates a valid Centrelink Customer Reference Number:
+// 9 digits weighted 512 down to 2, with the sum mod 10 selecting the check
+// letter
+func (g *TestDataGenerator) GenerateValidCRN() string {
+	weights := []int{512, 256, 128, 64, 32, 16, 8, 4, 2}
+
+	crn := ""
+	sum := 0
+	for _, weight := range weights {
+		d := g.rand.Intn(10)
+		sum += d * weight
+		crn += fmt.Sprintf("%d", d)
+	}
+
+	return crn + string("XLKJHVCBAT"[sum%10])
+}
+
+// GenerateInvalidCRN generates a CRN with the wrong check letter
+func (g *TestDataGenerator) GenerateInvalidCRN() string {
+	crn := g.GenerateValidCRN()
+	letters := "XLKJHVCBAT"
+	next := (strings.IndexByte(letters, crn[9]) + 1) % len(letters)
+	return crn[:9] + string(letters[next])
+}
+
+// GenerateValidDVAFileNumber generates a DVA file number: a state letter,
+// a war code, up to 6 digits and sometimes a segment letter for a dependant
+func (g *TestDataGenerator) GenerateValidDVAFileNumber() string {
+	states := []string{"N", "V", "Q", "S", "W", "T"}
+	warCodes := []string{"X", "KM", "SM", "SR", "SWP", ""}
+
+	state := states[g.rand.Intn(len(states))]
+	warCode := warCodes[g.rand.Intn(len(warCodes))]
+
+	// Nine characters at most, segment letter included
+	maxDigits := 8 - len(warCode) - 1
+	if maxDigits > 6 {
+		maxDigits = 6
+	}
+	number := ""
+	for i := 4 + g.rand.Intn(maxDigits-3); i > 0; i-- {
+		number += fmt.Sprintf("%d", g.rand.Intn(10))
+	}
+
+	segment := ""
+	if g.rand.Intn(4) == 0 {
+		segment = string(rune('A' + g.rand.Intn(26)))
+	}
+
+	return state + warCode + number + segment
+}
+
+// GenerateValidImmiCard generates an ImmiCard number: 3 letters and 6 digits
+func (g *TestDataGenerator) GenerateValidImmiCard() string {
+	letters := ""
+	for i := 0; i < 3; i++ {
+		letters += string(rune('A' + g.rand.Intn(26)))
+	}
+	return letters + fmt.Sprintf("%06d", g.rand.Intn(1000000))
+}
+
 // GenerateDriverLicense generates driver license numbers for different states
 func (g *TestDataGenerator) GenerateDriverLicense(state string) string {
 	switch strings.ToUpper(state) {
@@ -463,6 +591,12 @@ func (g *TestDataGenerator) getVarName(piType detection.PIType) string {
 		detection.PITypeMedicare: {"medicare", "medicareNumber", "patientMedicare"},
 		detection.PITypeBSB:      {"bsb", "bankCode", "branchCode"},
 		detection.PITypeACN:      {"acn", "companyNumber", "businessACN"},
+		detection.PITypeIHI:      {"ihi", "healthcareIdentifier", "patientIHI"},
+		detection.PITypeHPII:     {"hpii", "providerNumber", "practitionerHPII"},
+		detection.PITypeHPIO:     {"hpio", "organisationIdentifier", "facilityHPIO"},
+		detection.PITypeCRN:      {"crn", "customerReferenceNumber", "centrelinkCRN"},
+		detection.PITypeDVA:      {"dvaFileNumber", "veteranFileNo", "dvaNumber"},
+		detection.PITypeImmiCard: {"immiCard", "immiCardNumber", "immiCardNo"},
 	}
 
 	if names, ok := varNames[piType]; ok {
@@ -478,6 +612,12 @@ func (g *TestDataGenerator) getFieldName(piType detection.PIType) string {
 		detection.PITypeMedicare: {"Medicare", "MedicareNumber", "HealthCard"},
 		detection.PITypeBSB:      {"BSB", "BankCode", "BranchCode"},
 		detection.PITypeACN:      {"ACN", "CompanyNumber", "AustralianCompanyNumber"},
+		detection.PITypeIHI:      {"IHI", "HealthcareIdentifier", "IndividualHealthcareIdentifier"},
+		detection.PITypeHPII:     {"HPII", "ProviderIdentifier", "PractitionerHPII"},
+		detection.PITypeHPIO:     {"HPIO", "OrganisationIdentifier", "FacilityHPIO"},
+		detection.PITypeCRN:      {"CRN", "CustomerReferenceNumber", "CentrelinkCRN"},
+		detection.PITypeDVA:      {"DVAFileNumber", "VeteranFileNumber", "DVANumber"},
+		detection.PITypeImmiCard: {"ImmiCard", "ImmiCardNumber", "ImmiCardNo"},
 	}
 
 	if names, ok := fieldNames[piType]; ok {
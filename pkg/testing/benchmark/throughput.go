@@ -0,0 +1,126 @@
+package benchmark
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// throughputValueInterval is how many lines of code separate each group of
+// PI values in a throughput corpus
+const throughputValueInterval = 200
+
+// throughputCode is PI-free code that makes up most of a throughput corpus
+var throughputCode = []string{
+	`func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {`,
+	`	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)`,
+	`	defer cancel()`,
+	`	if err := s.store.Update(ctx, id, payload); err != nil {`,
+	`		return fmt.Errorf("failed to update record %d: %w", id, err)`,
+	`	}`,
+	`	buf := make([]byte, 0, 4096) // Reused between calls`,
+	`	log.Printf("processed %d items in %v", len(items), time.Since(start))`,
+	`	for i, item := range items { total += item.Price * float64(item.Quantity) }`,
+	`}`,
+	``,
+}
+
+// ThroughputTypes returns the PI types the pattern detector has a matcher for
+func ThroughputTypes() []detection.PIType {
+	return []detection.PIType{
+		detection.PITypeTFN,
+		detection.PITypeABN,
+		detection.PITypeMedicare,
+		detection.PITypeBSB,
+		detection.PITypeACN,
+		detection.PITypePhone,
+		detection.PITypeEmail,
+		detection.PITypeDriverLicense,
+		detection.PITypeName,
+		detection.PITypeIHI,
+		detection.PITypeHPII,
+		detection.PITypeHPIO,
+		detection.PITypeCRN,
+		detection.PITypeDVA,
+		detection.PITypeImmiCard,
+	}
+}
+
+// ThroughputConfig returns the default detection configuration with only
+// piType enabled, so a detector built from it runs that type's matcher alone
+func ThroughputConfig(piType detection.PIType) *detection.Config {
+	config := detection.DefaultConfig()
+	config.TypeSettings = make(map[detection.PIType]detection.TypeSettings)
+	for _, other := range ThroughputTypes() {
+		config.TypeSettings[other] = detection.TypeSettings{Enabled: other == piType}
+	}
+	return config
+}
+
+// GenerateThroughputCorpus generates about size bytes of Go source for
+// measuring detector throughput. Most of it is PI-free code and near misses
+// such as hashes, UUIDs and version numbers, with a value of each of piTypes
+// every few hundred lines.
+func (g *TestDataGenerator) GenerateThroughputCorpus(size int, piTypes []detection.PIType) []byte {
+	var b strings.Builder
+	b.Grow(size)
+
+	for line := 0; b.Len() < size; line++ {
+		switch {
+		case line%throughputValueInterval == 0:
+			for _, piType := range piTypes {
+				b.WriteString(g.wrapThroughputValue(piType))
+				b.WriteByte('\n')
+			}
+		case line%50 == 0:
+			fmt.Fprintf(&b, "\trevision := %q\n", g.GenerateHash())
+		case line%50 == 25:
+			fmt.Fprintf(&b, "\trequestID := %q // build %s\n", g.GenerateUUID(), g.GenerateVersionNumber())
+		default:
+			b.WriteString(throughputCode[line%len(throughputCode)])
+			b.WriteByte('\n')
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// wrapThroughputValue generates a value of piType in the code around it
+func (g *TestDataGenerator) wrapThroughputValue(piType detection.PIType) string {
+	switch piType {
+	case detection.PITypeTFN:
+		return g.WrapInContext(g.FormatTFN(g.GenerateValidTFN(), "spaces"), piType, "assignment", "go")
+	case detection.PITypeABN:
+		return g.WrapInContext(g.FormatABN(g.GenerateValidABN(), "spaces"), piType, "assignment", "go")
+	case detection.PITypeMedicare:
+		return g.WrapInContext(g.GenerateValidMedicare(), piType, "assignment", "go")
+	case detection.PITypeBSB:
+		return g.WrapInContext(g.GenerateValidBSB(), piType, "assignment", "go")
+	case detection.PITypeACN:
+		return fmt.Sprintf(`	// Billed to company ACN: %s`, g.GenerateValidACN())
+	case detection.PITypePhone:
+		return g.WrapInContext(fmt.Sprintf("04%02d %03d %03d", g.rand.Intn(100), g.rand.Intn(1000), g.rand.Intn(1000)), piType, "assignment", "go")
+	case detection.PITypeEmail:
+		return g.WrapInContext(fmt.Sprintf("customer%d@example.com.au", g.rand.Intn(10000)), piType, "assignment", "go")
+	case detection.PITypeDriverLicense:
+		return g.WrapInContext(g.GenerateDriverLicense("SA"), piType, "assignment", "go")
+	case detection.PITypeName:
+		names := []string{"Jane Citizen", "Oliver Nguyen", "Charlotte Williams", "Jack Thompson"}
+		return fmt.Sprintf(`	// Account holder %s`, names[g.rand.Intn(len(names))])
+	case detection.PITypeIHI:
+		return g.WrapInContext(g.FormatHealthcareIdentifier(g.GenerateValidIHI()), piType, "assignment", "go")
+	case detection.PITypeHPII:
+		return g.WrapInContext(g.GenerateValidHPII(), piType, "assignment", "go")
+	case detection.PITypeHPIO:
+		return g.WrapInContext(g.GenerateValidHPIO(), piType, "assignment", "go")
+	case detection.PITypeCRN:
+		return g.WrapInContext(g.GenerateValidCRN(), piType, "assignment", "go")
+	case detection.PITypeDVA:
+		return g.WrapInContext(g.GenerateValidDVAFileNumber(), piType, "assignment", "go")
+	case detection.PITypeImmiCard:
+		return g.WrapInContext(g.GenerateValidImmiCard(), piType, "assignment", "go")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,60 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MacAttak/pi-scanner/pkg/detection"
+)
+
+// throughputCorpusSize is the size of the corpus each detector scans
+const throughputCorpusSize = 4 * 1024 * 1024
+
+func TestGenerateThroughputCorpus(t *testing.T) {
+	g := NewTestDataGeneratorWithSeed(1)
+	content := g.GenerateThroughputCorpus(64*1024, ThroughputTypes())
+	assert.GreaterOrEqual(t, len(content), 64*1024)
+
+	// Each detector has values of its own type to report in the corpus
+	for _, piType := range ThroughputTypes() {
+		d := detection.NewDetectorWithConfig(ThroughputConfig(piType))
+		findings, err := d.Detect(context.Background(), content, "billing.go")
+		require.NoError(t, err)
+		require.NotEmpty(t, findings, piType)
+		for _, f := range findings {
+			assert.Equal(t, piType, f.Type)
+		}
+	}
+}
+
+// BenchmarkDetectorThroughput reports the MB/s of each detector on its own,
+// and of all of them together, scanning the same corpus
+func BenchmarkDetectorThroughput(b *testing.B) {
+	content := NewTestDataGeneratorWithSeed(1).GenerateThroughputCorpus(throughputCorpusSize, ThroughputTypes())
+
+	for _, piType := range ThroughputTypes() {
+		b.Run(string(piType), func(b *testing.B) {
+			benchmarkDetect(b, detection.NewDetectorWithConfig(ThroughputConfig(piType)), content)
+		})
+	}
+	b.Run("all", func(b *testing.B) {
+		benchmarkDetect(b, detection.NewDetector(), content)
+	})
+}
+
+// benchmarkDetect runs a detector over content b.N times
+func benchmarkDetect(b *testing.B, d detection.Detector, content []byte) {
+	ctx := context.Background()
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := d.Detect(ctx, content, "billing.go"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
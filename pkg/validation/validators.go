@@ -1,18 +1,34 @@
 package validation
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+// Patterns are compiled once, as validators run for every match
+var (
+	separatorPattern         = regexp.MustCompile(`[\s\-]`)
+	medicareSeparatorPattern = regexp.MustCompile(`[\s\-/]`)
+	nonDigitPattern          = regexp.MustCompile(`[^\d]`)
+	nonAlphanumericPattern   = regexp.MustCompile(`[^0-9A-Za-z]`)
+	sixDigitsPattern         = regexp.MustCompile(`^\d{6}$`)
+	nineDigitsPattern        = regexp.MustCompile(`^\d{9}$`)
+	elevenDigitsPattern      = regexp.MustCompile(`^\d{11}$`)
+	sixteenDigitsPattern     = regexp.MustCompile(`^\d{16}$`)
+	crnPattern               = regexp.MustCompile(`^\d{9}[A-Z]$`)
+	dvaFileNumberPattern     = regexp.MustCompile(`^([NVQSWT])([A-Z]{0,3})(\d{1,6})[A-Z]?$`)
+	immiCardPattern          = regexp.MustCompile(`^[A-Z]{3}\d{6}$`)
+)
+
 // TFNValidator validates Australian Tax File Numbers
 type TFNValidator struct{}
 
 // Validate checks if the TFN is valid using the official algorithm
 func (v *TFNValidator) Validate(value string) (bool, error) {
 	// Remove spaces and dashes
-	tfn := regexp.MustCompile(`[\s\-]`).ReplaceAllString(value, "")
+	tfn := separatorPattern.ReplaceAllString(value, "")
 
 	// Must be 9 digits
 	if len(tfn) != 9 {
@@ -20,7 +36,7 @@ func (v *TFNValidator) Validate(value string) (bool, error) {
 	}
 
 	// Check all digits
-	if !regexp.MustCompile(`^\d{9}$`).MatchString(tfn) {
+	if !nineDigitsPattern.MatchString(tfn) {
 		return false, nil
 	}
 
@@ -45,7 +61,7 @@ func (v *TFNValidator) Type() string {
 // Normalize returns normalized TFN
 func (v *TFNValidator) Normalize(value string) string {
 	// Remove all non-digits
-	return regexp.MustCompile(`[^\d]`).ReplaceAllString(value, "")
+	return nonDigitPattern.ReplaceAllString(value, "")
 }
 
 // ABNValidator validates Australian Business Numbers
@@ -54,7 +70,7 @@ type ABNValidator struct{}
 // Validate checks if the ABN is valid using modulus 89
 func (v *ABNValidator) Validate(value string) (bool, error) {
 	// Remove spaces and dashes
-	abn := regexp.MustCompile(`[\s\-]`).ReplaceAllString(value, "")
+	abn := separatorPattern.ReplaceAllString(value, "")
 
 	// Must be 11 digits
 	if len(abn) != 11 {
@@ -62,7 +78,7 @@ func (v *ABNValidator) Validate(value string) (bool, error) {
 	}
 
 	// Check all digits
-	if !regexp.MustCompile(`^\d{11}$`).MatchString(abn) {
+	if !elevenDigitsPattern.MatchString(abn) {
 		return false, nil
 	}
 
@@ -91,7 +107,7 @@ func (v *ABNValidator) Type() string {
 
 // Normalize returns normalized ABN
 func (v *ABNValidator) Normalize(value string) string {
-	return regexp.MustCompile(`[^\d]`).ReplaceAllString(value, "")
+	return nonDigitPattern.ReplaceAllString(value, "")
 }
 
 // MedicareValidator validates Australian Medicare numbers
@@ -100,7 +116,7 @@ type MedicareValidator struct{}
 // Validate checks if the Medicare number is valid
 func (v *MedicareValidator) Validate(value string) (bool, error) {
 	// Remove spaces, dashes, and slashes
-	medicare := regexp.MustCompile(`[\s\-/]`).ReplaceAllString(value, "")
+	medicare := medicareSeparatorPattern.ReplaceAllString(value, "")
 
 	// Medicare numbers are 10 or 11 digits (with IRN)
 	if len(medicare) < 10 || len(medicare) > 11 {
@@ -138,7 +154,7 @@ func (v *MedicareValidator) Type() string {
 
 // Normalize returns normalized Medicare number
 func (v *MedicareValidator) Normalize(value string) string {
-	return regexp.MustCompile(`[^\d]`).ReplaceAllString(value, "")
+	return nonDigitPattern.ReplaceAllString(value, "")
 }
 
 // BSBValidator validates Australian Bank State Branch codes
@@ -156,7 +172,7 @@ func (v *BSBValidator) Validate(value string) (bool, error) {
 	}
 
 	// Check all digits
-	if !regexp.MustCompile(`^\d{6}$`).MatchString(bsb) {
+	if !sixDigitsPattern.MatchString(bsb) {
 		return false, nil
 	}
 
@@ -180,7 +196,7 @@ func (v *BSBValidator) Type() string {
 
 // Normalize returns normalized BSB in XXX-XXX format
 func (v *BSBValidator) Normalize(value string) string {
-	clean := regexp.MustCompile(`[^\d]`).ReplaceAllString(value, "")
+	clean := nonDigitPattern.ReplaceAllString(value, "")
 	if len(clean) == 6 {
 		return clean[:3] + "-" + clean[3:]
 	}
@@ -193,7 +209,7 @@ type ACNValidator struct{}
 // Validate checks if the ACN is valid
 func (v *ACNValidator) Validate(value string) (bool, error) {
 	// Remove spaces and dashes
-	acn := regexp.MustCompile(`[\s\-]`).ReplaceAllString(value, "")
+	acn := separatorPattern.ReplaceAllString(value, "")
 
 	// Must be 9 digits
 	if len(acn) != 9 {
@@ -201,7 +217,7 @@ func (v *ACNValidator) Validate(value string) (bool, error) {
 	}
 
 	// Check all digits
-	if !regexp.MustCompile(`^\d{9}$`).MatchString(acn) {
+	if !nineDigitsPattern.MatchString(acn) {
 		return false, nil
 	}
 
@@ -232,7 +248,293 @@ func (v *ACNValidator) Type() string {
 
 // Normalize returns normalized ACN
 func (v *ACNValidator) Normalize(value string) string {
-	return regexp.MustCompile(`[^\d]`).ReplaceAllString(value, "")
+	return nonDigitPattern.ReplaceAllString(value, "")
+}
+
+// Healthcare identifier prefixes: the 80 health industry and 036 Australia
+// issuer codes, then a digit for the kind of identifier
+const (
+	ihiPrefix  = "800360"
+	hpiiPrefix = "800361"
+	hpioPrefix = "800362"
+)
+
+// IHIValidator validates Individual Healthcare Identifiers
+type IHIValidator struct{}
+
+// Validate checks the IHI prefix and Luhn check digit
+func (v *IHIValidator) Validate(value string) (bool, error) {
+	return validHealthcareIdentifier(value, ihiPrefix), nil
+}
+
+// Type returns the PI type
+func (v *IHIValidator) Type() string {
+	return "IHI"
+}
+
+// Normalize returns normalized IHI
+func (v *IHIValidator) Normalize(value string) string {
+	return nonDigitPattern.ReplaceAllString(value, "")
+}
+
+// HPIIValidator validates Healthcare Provider Identifiers for individual
+// practitioners
+type HPIIValidator struct{}
+
+// Validate checks the HPI-I prefix and Luhn check digit
+func (v *HPIIValidator) Validate(value string) (bool, error) {
+	return validHealthcareIdentifier(value, hpiiPrefix), nil
+}
+
+// Type returns the PI type
+func (v *HPIIValidator) Type() string {
+	return "HPI_I"
+}
+
+// Normalize returns normalized HPI-I
+func (v *HPIIValidator) Normalize(value string) string {
+	return nonDigitPattern.ReplaceAllString(value, "")
+}
+
+// HPIOValidator validates Healthcare Provider Identifiers for organisations
+type HPIOValidator struct{}
+
+// Validate checks the HPI-O prefix and Luhn check digit
+func (v *HPIOValidator) Validate(value string) (bool, error) {
+	return validHealthcareIdentifier(value, hpioPrefix), nil
+}
+
+// Type returns the PI type
+func (v *HPIOValidator) Type() string {
+	return "HPI_O"
+}
+
+// Normalize returns normalized HPI-O
+func (v *HPIOValidator) Normalize(value string) string {
+	return nonDigitPattern.ReplaceAllString(value, "")
+}
+
+// validHealthcareIdentifier reports whether value is 16 digits starting with
+// prefix, whose last digit is the Luhn check digit of the others
+func validHealthcareIdentifier(value, prefix string) bool {
+	// Remove spaces and dashes
+	id := separatorPattern.ReplaceAllString(value, "")
+
+	if !sixteenDigitsPattern.MatchString(id) || !strings.HasPrefix(id, prefix) {
+		return false
+	}
+
+	return luhn(id)
+}
+
+// crnWeights weight the nine digits of a Centrelink Customer Reference
+// Number, and crnCheckLetters is indexed by their sum modulo 10: a remainder
+// of 0 is X, 1 is L and so on, in the published order
+var (
+	crnWeights      = []int{512, 256, 128, 64, 32, 16, 8, 4, 2}
+	crnCheckLetters = "XLKJHVCBAT"
+)
+
+// CRNValidator validates Centrelink Customer Reference Numbers
+type CRNValidator struct{}
+
+// Validate checks the nine digits and check letter of the CRN
+func (v *CRNValidator) Validate(value string) (bool, error) {
+	crn := v.Normalize(value)
+
+	if !crnPattern.MatchString(crn) {
+		return false, nil
+	}
+
+	sum := 0
+	for i, weight := range crnWeights {
+		sum += int(crn[i]-'0') * weight
+	}
+
+	return crn[9] == crnCheckLetters[sum%10], nil
+}
+
+// Type returns the PI type
+func (v *CRNValidator) Type() string {
+	return "CRN"
+}
+
+// Normalize returns normalized CRN
+func (v *CRNValidator) Normalize(value string) string {
+	return strings.ToUpper(separatorPattern.ReplaceAllString(value, ""))
+}
+
+// dvaWarCodes are the war and service codes that follow the state in a DVA
+// file number. Files of First World War veterans and of some dependants
+// have none.
+var dvaWarCodes = map[string]bool{
+	"":    true,
+	"X":   true, // Second World War, Army
+	"BW":  true, // Boer War
+	"KM":  true, // Korea and Malaya
+	"SM":  true, // Malaya and Borneo
+	"SR":  true, // Far East Strategic Reserve
+	"SS":  true, // Special Service
+	"SWP": true, // South West Pacific
+	"GW":  true, // Gulf War
+	"CN":  true, // Civilian
+}
+
+// DVAFileNumberValidator validates Department of Veterans' Affairs file
+// numbers: a state letter, a war code, up to six digits and an optional
+// segment letter for dependants, nine characters at most
+type DVAFileNumberValidator struct{}
+
+// Validate checks the state, war code and length of the file number. File
+// numbers have no check digit.
+func (v *DVAFileNumberValidator) Validate(value string) (bool, error) {
+	file := v.Normalize(value)
+
+	if len(file) > 9 {
+		return false, nil
+	}
+
+	parts := dvaFileNumberPattern.FindStringSubmatch(file)
+	if parts == nil {
+		return false, nil
+	}
+
+	return dvaWarCodes[parts[2]], nil
+}
+
+// Type returns the PI type
+func (v *DVAFileNumberValidator) Type() string {
+	return "DVA_FILE_NUMBER"
+}
+
+// Normalize returns normalized DVA file number
+func (v *DVAFileNumberValidator) Normalize(value string) string {
+	return strings.ToUpper(separatorPattern.ReplaceAllString(value, ""))
+}
+
+// ImmiCardValidator validates ImmiCard numbers issued by the Department of
+// Home Affairs
+type ImmiCardValidator struct{}
+
+// Validate checks the ImmiCard number is three letters then six digits.
+// ImmiCard numbers have no published check digit.
+func (v *ImmiCardValidator) Validate(value string) (bool, error) {
+	return immiCardPattern.MatchString(v.Normalize(value)), nil
+}
+
+// Type returns the PI type
+func (v *ImmiCardValidator) Type() string {
+	return "IMMICARD"
+}
+
+// Normalize returns normalized ImmiCard number
+func (v *ImmiCardValidator) Normalize(value string) string {
+	return strings.ToUpper(separatorPattern.ReplaceAllString(value, ""))
+}
+
+// Checksum algorithms available to user-defined detection rules
+const (
+	ChecksumLuhn    = "luhn"    // Luhn (mod 10), as used by card numbers
+	ChecksumMod11   = "mod11"   // Weighted digit sum divisible by 11
+	ChecksumMod97   = "mod97"   // ISO 7064 MOD 97-10, as used by IBANs
+	ChecksumWeights = "weights" // Weighted digit sum divisible by a chosen modulus
+)
+
+// ChecksumValidator validates values of a user-defined PI type with a
+// standard checksum. The weighted algorithms weight every digit, check digit
+// included, and require the sum to be divisible by the modulus: a check digit
+// chosen as the complement of the sum takes weight 1, and one equal to the
+// sum modulo m takes weight m-1.
+type ChecksumValidator struct {
+	PIType    string
+	Algorithm string // One of the Checksum constants
+	Weights   []int  // Weight of each digit, left to right; mod11 and weights only
+	Modulus   int    // Divisor of the weighted sum; weights only
+}
+
+// Validate checks the value's checksum
+func (v *ChecksumValidator) Validate(value string) (bool, error) {
+	switch v.Algorithm {
+	case ChecksumLuhn:
+		return luhn(v.Normalize(value)), nil
+	case ChecksumMod11:
+		return weightedSum(v.Normalize(value), v.Weights, 11), nil
+	case ChecksumWeights:
+		if v.Modulus < 2 {
+			return false, fmt.Errorf("weights checksum needs a modulus of at least 2")
+		}
+		return weightedSum(v.Normalize(value), v.Weights, v.Modulus), nil
+	case ChecksumMod97:
+		return mod97(v.Normalize(value)), nil
+	}
+	return false, fmt.Errorf("unknown checksum algorithm %q", v.Algorithm)
+}
+
+// Type returns the PI type
+func (v *ChecksumValidator) Type() string {
+	return v.PIType
+}
+
+// Normalize returns the digits of the value, or for mod97 its digits and
+// letters in upper case
+func (v *ChecksumValidator) Normalize(value string) string {
+	if v.Algorithm == ChecksumMod97 {
+		return strings.ToUpper(nonAlphanumericPattern.ReplaceAllString(value, ""))
+	}
+	return nonDigitPattern.ReplaceAllString(value, "")
+}
+
+// luhn reports whether digits pass the Luhn check
+func luhn(digits string) bool {
+	if len(digits) < 2 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		// Double every second digit from the right
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// weightedSum reports whether the weighted sum of digits is divisible by
+// modulus. There must be a weight for each digit.
+func weightedSum(digits string, weights []int, modulus int) bool {
+	if len(digits) == 0 || len(digits) != len(weights) {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i]-'0') * weights[i]
+	}
+	return sum%modulus == 0
+}
+
+// mod97 reports whether upper case digits and letters leave a remainder of 1
+// modulo 97, reading letters as the numbers 10 to 35
+func mod97(value string) bool {
+	if len(value) < 2 {
+		return false
+	}
+
+	remainder := 0
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c >= 'A' {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder == 1
 }
 
 // ValidatorRegistry holds all validators
@@ -259,6 +561,12 @@ func NewValidatorRegistry() *ValidatorRegistry {
 	registry.Register(&MedicareValidator{})
 	registry.Register(&BSBValidator{})
 	registry.Register(&ACNValidator{})
+	registry.Register(&IHIValidator{})
+	registry.Register(&HPIIValidator{})
+	registry.Register(&HPIOValidator{})
+	registry.Register(&CRNValidator{})
+	registry.Register(&DVAFileNumberValidator{})
+	registry.Register(&ImmiCardValidator{})
 
 	return registry
 }
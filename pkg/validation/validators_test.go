@@ -339,11 +339,148 @@ func TestACNValidator(t *testing.T) {
 	}
 }
 
+func TestHealthcareIdentifierValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     string
+		expected  bool
+	}{
+		{"valid IHI", &IHIValidator{}, "8003608166690503", true},
+		{"valid IHI with spaces", &IHIValidator{}, "8003 6081 6669 0503", true},
+		{"IHI failing Luhn", &IHIValidator{}, "8003608166690504", false},
+		{"IHI with HPI-I prefix", &IHIValidator{}, "8003612345678900", false},
+		{"IHI too short", &IHIValidator{}, "800360816669050", false},
+		{"IHI with letters", &IHIValidator{}, "80036081666905AB", false},
+		{"valid HPI-I", &HPIIValidator{}, "8003612345678900", true},
+		{"HPI-I failing Luhn", &HPIIValidator{}, "8003612345678901", false},
+		{"valid HPI-O", &HPIOValidator{}, "8003-6234-5678-9014", true},
+		{"HPI-O with IHI prefix", &HPIOValidator{}, "8003608166690503", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := tt.validator.Validate(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, valid)
+		})
+	}
+
+	assert.Equal(t, "8003608166690503", (&IHIValidator{}).Normalize("8003 6081 6669 0503"))
+}
+
+func TestGovernmentServicesValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     string
+		expected  bool
+	}{
+		{"valid CRN", &CRNValidator{}, "204567890X", true},
+		{"valid CRN with spaces", &CRNValidator{}, "123 456 789C", true},
+		{"valid CRN in lower case", &CRNValidator{}, "987654321h", true},
+		{"CRN with wrong check letter", &CRNValidator{}, "204567890A", false},
+		{"CRN without check letter", &CRNValidator{}, "204567890", false},
+		{"CRN too short", &CRNValidator{}, "20456789A", false},
+		{"valid DVA file number", &DVAFileNumberValidator{}, "NX123456", true},
+		{"DVA file number with segment", &DVAFileNumberValidator{}, "QKM12345B", true},
+		{"DVA file number without war code", &DVAFileNumberValidator{}, "V12345", true},
+		{"DVA file number with space", &DVAFileNumberValidator{}, "WSWP 4821", true},
+		{"DVA file number in unknown state", &DVAFileNumberValidator{}, "AX123456", false},
+		{"DVA file number with unknown war code", &DVAFileNumberValidator{}, "NZZ123456", false},
+		{"DVA file number too long", &DVAFileNumberValidator{}, "NSWP123456A", false},
+		{"DVA file number without digits", &DVAFileNumberValidator{}, "NX", false},
+		{"valid ImmiCard", &ImmiCardValidator{}, "EIK123456", true},
+		{"valid ImmiCard in lower case", &ImmiCardValidator{}, "eik 123456", true},
+		{"ImmiCard with two letters", &ImmiCardValidator{}, "EI1234567", false},
+		{"ImmiCard too long", &ImmiCardValidator{}, "EIK1234567", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := tt.validator.Validate(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, valid)
+		})
+	}
+
+	assert.Equal(t, "123456789C", (&CRNValidator{}).Normalize("123 456 789c"))
+}
+
+func TestCRNValidator_CheckLetters(t *testing.T) {
+	// The weights are all even, so only even remainders occur
+	tests := []struct {
+		crn       string
+		remainder int
+	}{
+		{"204567890X", 0}, // 1024 + 512 + 320 + 192 + 112 + 64 + 36 = 2260
+		{"100000000K", 2}, // 512
+		{"987654321H", 4}, // 4608 + 2048 + 896 + 384 + 160 + 64 + 24 + 8 + 2 = 8194
+		{"123456789C", 6}, // 512 + 512 + 384 + 256 + 160 + 96 + 56 + 32 + 18 = 2026
+		{"001000000A", 8}, // 128
+	}
+
+	v := &CRNValidator{}
+	for _, tt := range tests {
+		valid, err := v.Validate(tt.crn)
+		assert.NoError(t, err)
+		assert.True(t, valid, "%s has remainder %d", tt.crn, tt.remainder)
+
+		// Every other check letter is rejected
+		for _, letter := range "ABCHJKLTVX" {
+			if byte(letter) != tt.crn[9] {
+				valid, err := v.Validate(tt.crn[:9] + string(letter))
+				assert.NoError(t, err)
+				assert.False(t, valid, "%s%c", tt.crn[:9], letter)
+			}
+		}
+	}
+}
+
+func TestChecksumValidator(t *testing.T) {
+	tfnWeights := []int{1, 4, 3, 7, 5, 8, 6, 9, 10}
+	acnWeights := []int{8, 7, 6, 5, 4, 3, 2, 1, 1}
+
+	tests := []struct {
+		name      string
+		validator ChecksumValidator
+		value     string
+		expected  bool
+		wantErr   bool
+	}{
+		{name: "luhn valid", validator: ChecksumValidator{Algorithm: ChecksumLuhn}, value: "4111 1111 1111 1111", expected: true},
+		{name: "luhn odd length", validator: ChecksumValidator{Algorithm: ChecksumLuhn}, value: "79927398713", expected: true},
+		{name: "luhn invalid", validator: ChecksumValidator{Algorithm: ChecksumLuhn}, value: "4111111111111112", expected: false},
+		{name: "mod11 valid", validator: ChecksumValidator{Algorithm: ChecksumMod11, Weights: tfnWeights}, value: "123-456-782", expected: true},
+		{name: "mod11 invalid", validator: ChecksumValidator{Algorithm: ChecksumMod11, Weights: tfnWeights}, value: "123456789", expected: false},
+		{name: "mod11 wrong length", validator: ChecksumValidator{Algorithm: ChecksumMod11, Weights: tfnWeights}, value: "12345678", expected: false},
+		{name: "weights complement check digit", validator: ChecksumValidator{Algorithm: ChecksumWeights, Weights: acnWeights, Modulus: 10}, value: "004 085 616", expected: true},
+		{name: "weights invalid", validator: ChecksumValidator{Algorithm: ChecksumWeights, Weights: acnWeights, Modulus: 10}, value: "004085617", expected: false},
+		{name: "weights without modulus", validator: ChecksumValidator{Algorithm: ChecksumWeights, Weights: acnWeights}, value: "004085616", wantErr: true},
+		{name: "mod97 valid", validator: ChecksumValidator{Algorithm: ChecksumMod97}, value: "WEST 1234 5698 7654 32GB82", expected: true},
+		{name: "mod97 lower case", validator: ChecksumValidator{Algorithm: ChecksumMod97}, value: "west12345698765432gb82", expected: true},
+		{name: "mod97 invalid", validator: ChecksumValidator{Algorithm: ChecksumMod97}, value: "WEST12345698765432GB83", expected: false},
+		{name: "unknown algorithm", validator: ChecksumValidator{Algorithm: "crc32"}, value: "123", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := tt.validator.Validate(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, valid)
+		})
+	}
+}
+
 func TestValidatorRegistry(t *testing.T) {
 	registry := NewValidatorRegistry()
 
 	t.Run("registry has all validators", func(t *testing.T) {
-		validators := []string{"TFN", "ABN", "MEDICARE", "BSB", "ACN"}
+		validators := []string{"TFN", "ABN", "MEDICARE", "BSB", "ACN", "IHI", "HPI_I", "HPI_O", "CRN", "DVA_FILE_NUMBER", "IMMICARD"}
 		for _, vType := range validators {
 			validator, ok := registry.Get(vType)
 			assert.True(t, ok, "Validator %s should be registered", vType)
@@ -330,16 +330,17 @@ func TestPIScannerE2E_ErrorHandling(t *testing.T) {
 	buildScanner(t)
 
 	errorTests := []struct {
-		name        string
-		args        []string
-		expectError bool
-		errorString string
+		name         string
+		args         []string
+		expectError  bool
+		errorString  string
+		requiresAuth bool // Clones with the gh CLI
 	}{
 		{
 			name:        "Missing Repository",
 			args:        []string{"scan"},
 			expectError: true,
-			errorString: "either --repo or --repo-list must be specified",
+			errorString: "either --repo, --repo-list, --org or --path must be specified",
 		},
 		{
 			name:        "Invalid Repository",
@@ -348,20 +349,35 @@ func TestPIScannerE2E_ErrorHandling(t *testing.T) {
 			errorString: "Invalid repository URL", // Should fail validation
 		},
 		{
-			name:        "Valid Repository",
-			args:        []string{"scan", "--repo", "https://github.com/octocat/Hello-World", "--output", "/tmp/test-valid.json"},
-			expectError: false,
-			errorString: "",
+			name:         "Valid Repository",
+			args:         []string{"scan", "--repo", "https://github.com/octocat/Hello-World", "--output", "/tmp/test-valid.json"},
+			expectError:  false,
+			errorString:  "",
+			requiresAuth: true,
 		},
 	}
 
 	for _, test := range errorTests {
 		t.Run(test.name, func(t *testing.T) {
+			if test.requiresAuth {
+				if _, err := exec.LookPath("gh"); err != nil {
+					t.Skip("Skipping: gh CLI not available")
+				}
+				if err := exec.Command("gh", "auth", "status").Run(); err != nil {
+					t.Skip("Skipping: gh CLI not authenticated")
+				}
+			}
+
 			cmd := exec.Command("../pi-scanner", test.args...)
 			output, err := cmd.CombinedOutput()
 
 			if test.expectError {
 				assert.Error(t, err, "Command should fail")
+				// Usage and scan errors exit 2; 1 is kept for policy violations
+				var exitErr *exec.ExitError
+				if assert.ErrorAs(t, err, &exitErr) {
+					assert.Equal(t, 2, exitErr.ExitCode(), "Should exit with the scan error code")
+				}
 				if test.errorString != "" {
 					assert.Contains(t, string(output), test.errorString,
 						"Should contain expected error message")